@@ -17,10 +17,10 @@ func TestWiring_AllCommandsRegistered(t *testing.T) {
 	expected := []string{
 		"login", "logout", "status", "version",
 		"discover", "process", "cancel", "logs",
-		"validate", "search",
+		"validate", "search", "knowledge", "catalog", "schema",
 		"start", "stop", "restart", "scale",
 		"env", "import", "delete", "subdomain",
-		"events", "setup",
+		"events", "history", "setup",
 	}
 
 	cmds := root.Commands()
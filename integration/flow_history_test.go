@@ -0,0 +1,42 @@
+package integration
+
+import (
+	"testing"
+)
+
+func TestFlow_HistoryAndPrune(t *testing.T) {
+	h := NewHarness(t)
+	FixtureFullProject(h)
+
+	h.MustRun("restart --service api")
+	h.MustRun("env set --service db PASSWORD=secret")
+
+	r := h.MustRun("history")
+	r.AssertType("sync")
+	entries := r.Data()["entries"].([]interface{})
+	if len(entries) != 2 {
+		t.Fatalf("entries = %d, want 2", len(entries))
+	}
+	// Newest first, with the command line but not the env value.
+	newest := entries[0].(map[string]interface{})
+	if newest["command"] != "zaia env set --service db PASSWORD=…" {
+		t.Errorf("command = %v", newest["command"])
+	}
+	if newest["status"] != "FINISHED" || newest["serviceHostname"] != "db" {
+		t.Errorf("entry = %v, want FINISHED on db", newest)
+	}
+
+	r = h.MustRun("history --service api")
+	if entries := r.Data()["entries"].([]interface{}); len(entries) != 1 {
+		t.Errorf("api entries = %d, want 1", len(entries))
+	}
+
+	r = h.MustRun("history --prune")
+	if r.Data()["pruned"] != float64(2) {
+		t.Errorf("pruned = %v, want 2", r.Data()["pruned"])
+	}
+	r = h.MustRun("history")
+	if r.Data()["total"] != float64(0) {
+		t.Errorf("total after prune = %v, want 0", r.Data()["total"])
+	}
+}
@@ -149,3 +149,19 @@ func TestFlow_CancelProcess(t *testing.T) {
 		t.Errorf("expected CANCELED, got %v", data["status"])
 	}
 }
+
+func TestFlow_RestartService_Wait(t *testing.T) {
+	h := NewHarness(t)
+	FixtureFullProject(h)
+
+	r := h.MustRun("restart --service api --wait")
+	r.AssertType("sync")
+	procs := r.Data()["processes"].([]interface{})
+	if len(procs) != 1 {
+		t.Fatalf("processes len = %d, want 1", len(procs))
+	}
+	p := procs[0].(map[string]interface{})
+	if p["status"] != "FINISHED" || p["serviceHostname"] != "api" {
+		t.Errorf("process = %v, want FINISHED api", p)
+	}
+}
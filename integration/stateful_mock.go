@@ -145,7 +145,7 @@ func (m *StatefulMock) makeProcess(action, serviceID string) *platform.Process {
 	p := &platform.Process{
 		ID:         id,
 		ActionName: action,
-		Status:     "DONE", // auto-complete for stateful testing
+		Status:     "FINISHED", // auto-complete, mapped as ZeropsClient reports it
 	}
 	if serviceID != "" {
 		p.ServiceStacks = []platform.ServiceStackRef{{ID: serviceID}}
@@ -0,0 +1,250 @@
+package catalog
+
+import (
+	_ "embed"
+	"fmt"
+	"strings"
+	"sync"
+
+	"gopkg.in/yaml.v3"
+)
+
+//go:embed catalog.yml
+var catalogYAML []byte
+
+// Categories, in display order.
+const (
+	CategoryRuntime   = "runtime"
+	CategoryContainer = "container"
+	CategoryDatabase  = "database"
+	CategoryCache     = "cache"
+	CategorySearch    = "search"
+	CategoryQueue     = "queue"
+	CategoryWeb       = "web"
+	CategoryStorage   = "storage"
+)
+
+// Categories lists every category in display order.
+var Categories = []string{
+	CategoryRuntime, CategoryContainer, CategoryDatabase, CategoryCache,
+	CategorySearch, CategoryQueue, CategoryWeb, CategoryStorage,
+}
+
+// Version statuses. An empty status means the version is supported.
+const (
+	StatusDeprecated = "deprecated"
+	StatusEOL        = "eol"
+)
+
+// ServiceType is one entry of the catalog.
+type ServiceType struct {
+	Name         string     `yaml:"name" json:"name"`
+	Category     string     `yaml:"category" json:"category"`
+	Description  string     `yaml:"description" json:"description"`
+	Versions     []Version  `yaml:"versions" json:"versions"`
+	Modes        []string   `yaml:"modes" json:"modes"`
+	ModeRequired bool       `yaml:"modeRequired" json:"modeRequired"`
+	BuildOnly    bool       `yaml:"buildOnly" json:"buildOnly,omitempty"`
+	Defaults     *Resources `yaml:"defaults" json:"defaults,omitempty"`
+}
+
+// Version is a version of a service type, newest first within ServiceType.Versions.
+type Version struct {
+	Version     string `yaml:"version" json:"version"`
+	Status      string `yaml:"status" json:"status,omitempty"`
+	Replacement string `yaml:"replacement" json:"replacement,omitempty"`
+}
+
+// Resources are the default scaling settings applied when import.yml omits them.
+type Resources struct {
+	CPUMode           string  `yaml:"cpuMode" json:"cpuMode,omitempty"`
+	MinCPU            int     `yaml:"minCpu" json:"minCpu,omitempty"`
+	MaxCPU            int     `yaml:"maxCpu" json:"maxCpu,omitempty"`
+	StartCPUCoreCount int     `yaml:"startCpuCoreCount" json:"startCpuCoreCount,omitempty"`
+	MinRAM            float64 `yaml:"minRam" json:"minRam,omitempty"`
+	MaxRAM            float64 `yaml:"maxRam" json:"maxRam,omitempty"`
+	MinDisk           float64 `yaml:"minDisk" json:"minDisk,omitempty"`
+	MaxDisk           float64 `yaml:"maxDisk" json:"maxDisk,omitempty"`
+	MinContainers     int     `yaml:"minContainers" json:"minContainers,omitempty"`
+	MaxContainers     int     `yaml:"maxContainers" json:"maxContainers,omitempty"`
+	ContainersNonHA   int     `yaml:"containersNonHa" json:"containersNonHa,omitempty"`
+	ContainersHA      int     `yaml:"containersHa" json:"containersHa,omitempty"`
+}
+
+var (
+	loadOnce sync.Once
+	types    []ServiceType
+	byName   map[string]*ServiceType
+)
+
+func load() {
+	loadOnce.Do(func() {
+		var doc struct {
+			Types []ServiceType `yaml:"types"`
+		}
+		if err := yaml.Unmarshal(catalogYAML, &doc); err != nil {
+			panic("catalog: invalid embedded catalog.yml: " + err.Error())
+		}
+		types = doc.Types
+		byName = make(map[string]*ServiceType, len(types))
+		for i := range types {
+			byName[types[i].Name] = &types[i]
+		}
+	})
+}
+
+// Types returns every service type in catalog order.
+func Types() []ServiceType {
+	load()
+	return types
+}
+
+// Lookup returns the service type with the given name ("postgresql").
+func Lookup(name string) (*ServiceType, bool) {
+	load()
+	t, ok := byName[name]
+	return t, ok
+}
+
+// Split splits a type ID into name and version ("nodejs@22" → "nodejs", "22").
+func Split(id string) (name, version string) {
+	name, version, _ = strings.Cut(id, "@")
+	return name, version
+}
+
+// ID returns the type ID for version v, or the bare name for unversioned types.
+func (t *ServiceType) ID(v string) string {
+	if v == "" {
+		return t.Name
+	}
+	return t.Name + "@" + v
+}
+
+// Versioned reports whether the type is written as name@version.
+func (t *ServiceType) Versioned() bool {
+	return len(t.Versions) > 0
+}
+
+// Version returns the named version of t.
+func (t *ServiceType) Version(v string) (*Version, bool) {
+	for i := range t.Versions {
+		if t.Versions[i].Version == v {
+			return &t.Versions[i], true
+		}
+	}
+	return nil, false
+}
+
+// IDs returns the type IDs of t, newest first. EOL versions are included only when withEOL is set.
+func (t *ServiceType) IDs(withEOL bool) []string {
+	if !t.Versioned() {
+		return []string{t.Name}
+	}
+	ids := make([]string, 0, len(t.Versions))
+	for _, v := range t.Versions {
+		if withEOL || v.Status != StatusEOL {
+			ids = append(ids, t.ID(v.Version))
+		}
+	}
+	return ids
+}
+
+// Latest returns the ID of the newest supported version of t.
+func (t *ServiceType) Latest() string {
+	for _, v := range t.Versions {
+		if v.Status == "" {
+			return t.ID(v.Version)
+		}
+	}
+	return t.Name
+}
+
+// Resolve looks up a type ID ("nodejs@22"). ok is false if the name or version is unknown.
+func Resolve(id string) (t *ServiceType, v *Version, ok bool) {
+	name, version := Split(id)
+	t, found := Lookup(name)
+	if !found {
+		return nil, nil, false
+	}
+	if !t.Versioned() {
+		return t, nil, version == ""
+	}
+	v, ok = t.Version(version)
+	return t, v, ok
+}
+
+// IDs returns every importable type ID (build-only types excluded), newest versions first.
+// EOL versions are included only when withEOL is set.
+func IDs(withEOL bool) []string {
+	var ids []string
+	for i := range Types() {
+		if !types[i].BuildOnly {
+			ids = append(ids, types[i].IDs(withEOL)...)
+		}
+	}
+	return ids
+}
+
+// Names returns the type names in the given category, or all names when category is empty.
+func Names(category string) []string {
+	var names []string
+	for _, t := range Types() {
+		if category == "" || t.Category == category {
+			names = append(names, t.Name)
+		}
+	}
+	return names
+}
+
+// Check reports why a type ID is not acceptable: an EOL version, or an unknown version of a
+// known type. It returns "" for supported and deprecated IDs and for unknown type names,
+// which callers report with their own did-you-mean logic.
+func Check(id string) (msg, fix string) {
+	name, version := Split(id)
+	t, found := Lookup(name)
+	if !found {
+		return "", ""
+	}
+	if !t.Versioned() {
+		if version != "" {
+			return fmt.Sprintf("'%s' has no versions", name), fmt.Sprintf("Use '%s'", name)
+		}
+		return "", ""
+	}
+	if version == "" {
+		return fmt.Sprintf("'%s' needs a version", name), fmt.Sprintf("Did you mean '%s'?", t.Latest())
+	}
+	v, ok := t.Version(version)
+	if !ok {
+		var available []string
+		for _, id := range t.IDs(false) {
+			_, ver := Split(id)
+			available = append(available, ver)
+		}
+		return fmt.Sprintf("Unknown version '%s' for %s", version, name),
+			fmt.Sprintf("Did you mean '%s'? Available versions: %s", t.Latest(), strings.Join(available, ", "))
+	}
+	if v.Status == StatusEOL {
+		return fmt.Sprintf("'%s' is end-of-life", id), fmt.Sprintf("Did you mean '%s'?", v.Replacement)
+	}
+	return "", ""
+}
+
+// Deprecated returns the replacement for a deprecated (not EOL) type ID.
+func Deprecated(id string) (replacement string, ok bool) {
+	_, v, found := Resolve(id)
+	if !found || v == nil || v.Status != StatusDeprecated {
+		return "", false
+	}
+	return v.Replacement, true
+}
+
+// CategoryOrder returns the display index of category, or len(Categories) if it is unknown.
+func CategoryOrder(category string) int {
+	for i, c := range Categories {
+		if c == category {
+			return i
+		}
+	}
+	return len(Categories)
+}
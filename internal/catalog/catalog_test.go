@@ -0,0 +1,106 @@
+package catalog
+
+import (
+	"testing"
+)
+
+func TestCatalog_Consistent(t *testing.T) {
+	if len(Types()) == 0 {
+		t.Fatal("embedded catalog is empty")
+	}
+	seen := make(map[string]bool)
+	for _, st := range Types() {
+		if seen[st.Name] {
+			t.Errorf("duplicate type %s", st.Name)
+		}
+		seen[st.Name] = true
+		if CategoryOrder(st.Category) == len(Categories) {
+			t.Errorf("%s: unknown category %q", st.Name, st.Category)
+		}
+		if st.Description == "" {
+			t.Errorf("%s: missing description", st.Name)
+		}
+		for _, v := range st.Versions {
+			switch v.Status {
+			case "":
+			case StatusDeprecated, StatusEOL:
+				if _, rv, ok := Resolve(v.Replacement); !ok || (rv != nil && rv.Status != "") {
+					t.Errorf("%s@%s: replacement %q is not a supported type", st.Name, v.Version, v.Replacement)
+				}
+			default:
+				t.Errorf("%s@%s: unknown status %q", st.Name, v.Version, v.Status)
+			}
+		}
+		if st.ModeRequired && len(st.Modes) == 0 {
+			t.Errorf("%s: mode required but no modes listed", st.Name)
+		}
+	}
+}
+
+func TestResolve(t *testing.T) {
+	tests := []struct {
+		id string
+		ok bool
+	}{
+		{"nodejs@22", true},
+		{"nodejs@18", true},
+		{"nodejs@21", false},
+		{"nodejs", false},
+		{"static", true},
+		{"static@1", false},
+		{"unknown@1", false},
+	}
+	for _, tt := range tests {
+		if _, _, ok := Resolve(tt.id); ok != tt.ok {
+			t.Errorf("Resolve(%q) ok = %v, want %v", tt.id, ok, tt.ok)
+		}
+	}
+}
+
+func TestCheck(t *testing.T) {
+	tests := []struct {
+		id, msg, fix string
+	}{
+		{"nodejs@22", "", ""},
+		{"keydb@6", "", ""},
+		{"unknown@1", "", ""},
+		{"nodejs@18", "", ""},
+		{"dotnet@6", "'dotnet@6' is end-of-life", "Did you mean 'dotnet@8'?"},
+		{"postgresql@15", "Unknown version '15' for postgresql", "Did you mean 'postgresql@17'? Available versions: 17, 16, 14"},
+		{"valkey", "'valkey' needs a version", "Did you mean 'valkey@7.2'?"},
+		{"object-storage@1", "'object-storage' has no versions", "Use 'object-storage'"},
+	}
+	for _, tt := range tests {
+		msg, fix := Check(tt.id)
+		if msg != tt.msg || fix != tt.fix {
+			t.Errorf("Check(%q) = %q / %q, want %q / %q", tt.id, msg, fix, tt.msg, tt.fix)
+		}
+	}
+}
+
+func TestIDs(t *testing.T) {
+	ids := IDs(false)
+	has := func(id string) bool {
+		for _, v := range ids {
+			if v == id {
+				return true
+			}
+		}
+		return false
+	}
+	if !has("nodejs@22") || !has("static") || !has("keydb@6") {
+		t.Errorf("IDs missing supported types: %v", ids)
+	}
+	if has("dotnet@6") {
+		t.Error("IDs(false) must exclude EOL versions")
+	}
+	if has("php@8.4") {
+		t.Error("IDs must exclude build-only types")
+	}
+	if rep, ok := Deprecated("keydb@6"); !ok || rep != "valkey@7.2" {
+		t.Errorf("Deprecated(keydb@6) = %q, %v", rep, ok)
+	}
+	if rep, ok := Deprecated("nodejs@18"); !ok || rep != "nodejs@22" {
+		t.Errorf("Deprecated(nodejs@18) = %q, %v", rep, ok)
+	}
+}
@@ -0,0 +1,119 @@
+package commands
+
+import (
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/zeropsio/zaia/internal/catalog"
+	"github.com/zeropsio/zaia/internal/output"
+	"github.com/zeropsio/zaia/internal/platform"
+	"github.com/zeropsio/zaia/internal/validation"
+)
+
+// NewCatalog creates the catalog command with list/show subcommands.
+func NewCatalog() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "catalog",
+		Short: "Service types, versions and defaults",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return output.Err(platform.ErrInvalidUsage,
+				"No subcommand specified for 'catalog'",
+				"Run: zaia catalog <list|show>",
+				map[string]interface{}{"availableSubcommands": []string{"list", "show"}})
+		},
+	}
+
+	cmd.AddCommand(newCatalogList())
+	cmd.AddCommand(newCatalogShow())
+
+	return cmd
+}
+
+func newCatalogList() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "List service types",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			category, _ := cmd.Flags().GetString("category")
+			if category != "" && catalog.CategoryOrder(category) == len(catalog.Categories) {
+				return output.Err(platform.ErrInvalidParameter,
+					"Unknown category: "+category,
+					"Use one of: "+strings.Join(catalog.Categories, ", "), nil)
+			}
+
+			types := make([]map[string]interface{}, 0)
+			for _, t := range catalog.Types() {
+				if category != "" && t.Category != category {
+					continue
+				}
+				types = append(types, map[string]interface{}{
+					"name":        t.Name,
+					"category":    t.Category,
+					"description": t.Description,
+					"types":       t.IDs(false),
+					"latest":      t.Latest(),
+				})
+			}
+			return output.Sync(map[string]interface{}{
+				"categories": catalog.Categories,
+				"types":      types,
+			})
+		},
+	}
+
+	cmd.Flags().String("category", "", "Filter by category: "+strings.Join(catalog.Categories, ", "))
+
+	return cmd
+}
+
+func newCatalogShow() *cobra.Command {
+	return &cobra.Command{
+		Use:   "show <type>",
+		Short: "Show versions, modes and default resources of a service type",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			name, version := catalog.Split(args[0])
+			t, ok := catalog.Lookup(name)
+			if !ok {
+				suggestion := "Run: zaia catalog list"
+				if s := validation.Closest(name, catalog.Names("")); s != "" {
+					suggestion = "Did you mean '" + s + "'?"
+				}
+				return output.Err(platform.ErrServiceTypeNotFound,
+					"Unknown service type: "+args[0], suggestion, nil)
+			}
+			if version != "" {
+				if _, ok := t.Version(version); !ok {
+					_, fix := catalog.Check(args[0])
+					return output.Err(platform.ErrServiceTypeNotFound,
+						"Unknown version '"+version+"' for "+name, fix, nil)
+				}
+			}
+
+			modes := t.Modes
+			if modes == nil {
+				modes = []string{}
+			}
+			versions := t.Versions
+			if versions == nil {
+				versions = []catalog.Version{}
+			}
+			data := map[string]interface{}{
+				"name":         t.Name,
+				"category":     t.Category,
+				"description":  t.Description,
+				"versions":     versions,
+				"latest":       t.Latest(),
+				"modes":        modes,
+				"modeRequired": t.ModeRequired,
+				"buildOnly":    t.BuildOnly,
+				"defaults":     t.Defaults,
+			}
+			if version != "" {
+				data["type"] = args[0]
+			}
+			return output.Sync(data)
+		},
+	}
+}
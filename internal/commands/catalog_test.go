@@ -0,0 +1,67 @@
+package commands
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/zeropsio/zaia/internal/output"
+)
+
+func runCatalog(t *testing.T, args ...string) (map[string]interface{}, error) {
+	t.Helper()
+	cmd := NewCatalog()
+	cmd.SetArgs(args)
+
+	var stdout bytes.Buffer
+	output.SetWriter(&stdout)
+	defer output.ResetWriter()
+
+	err := cmd.Execute()
+	var resp map[string]interface{}
+	_ = json.Unmarshal(stdout.Bytes(), &resp)
+	return resp, err
+}
+
+func TestCatalogList_Category(t *testing.T) {
+	resp, err := runCatalog(t, "list", "--category", "database")
+	if err != nil {
+		t.Fatal(err)
+	}
+	types := resp["data"].(map[string]interface{})["types"].([]interface{})
+	if len(types) == 0 {
+		t.Fatal("expected database types")
+	}
+	for _, raw := range types {
+		if st := raw.(map[string]interface{}); st["category"] != "database" {
+			t.Errorf("type %v has category %v", st["name"], st["category"])
+		}
+	}
+}
+
+func TestCatalogShow(t *testing.T) {
+	resp, err := runCatalog(t, "show", "postgresql")
+	if err != nil {
+		t.Fatal(err)
+	}
+	data := resp["data"].(map[string]interface{})
+	if data["latest"] != "postgresql@17" || data["modeRequired"] != true {
+		t.Errorf("data = %v", data)
+	}
+	if data["defaults"] == nil {
+		t.Error("expected default resources")
+	}
+}
+
+func TestCatalogShow_Unknown(t *testing.T) {
+	resp, err := runCatalog(t, "show", "postgres")
+	if err == nil {
+		t.Fatal("expected error for unknown type")
+	}
+	if resp["code"] != "SERVICE_TYPE_NOT_FOUND" {
+		t.Errorf("code = %v, want SERVICE_TYPE_NOT_FOUND", resp["code"])
+	}
+	if resp["suggestion"] != "Did you mean 'postgresql'?" {
+		t.Errorf("suggestion = %v", resp["suggestion"])
+	}
+}
@@ -57,7 +57,7 @@ func NewDelete(storagePath string, client platform.Client) *cobra.Command {
 				return output.Err(platform.ErrAPIError, err.Error(), "", nil)
 			}
 
-			return output.Async([]output.ProcessOutput{
+			return asyncOutput(cmd, client, storagePath, creds, []output.ProcessOutput{
 				output.MapProcessToOutput(process, hostname),
 			})
 		},
@@ -65,6 +65,7 @@ func NewDelete(storagePath string, client platform.Client) *cobra.Command {
 
 	cmd.Flags().String("service", "", "Service hostname (required)")
 	cmd.Flags().Bool("confirm", false, "Confirm destructive action")
+	addWaitFlags(cmd)
 
 	return cmd
 }
@@ -118,18 +118,16 @@ func newEnvSet(storagePath string, client platform.Client) *cobra.Command {
 
 			if isProject {
 				// For project env, create each var individually
-				var lastProcess *platform.Process
+				var processes []output.ProcessOutput
 				for _, p := range pairs {
 					proc, err := client.CreateProjectEnv(ctx, creds.ProjectID, p.Key, p.Value, false)
 					if err != nil {
 						return output.Err(platform.ErrAPIError, err.Error(), "", nil)
 					}
-					lastProcess = proc
+					processes = append(processes, output.MapProcessToOutput(proc, ""))
 				}
-				if lastProcess != nil {
-					return output.Async([]output.ProcessOutput{
-						output.MapProcessToOutput(lastProcess, ""),
-					})
+				if len(processes) > 0 {
+					return asyncOutput(cmd, client, storagePath, creds, processes)
 				}
 				return output.Sync(map[string]interface{}{"message": "No variables to set"})
 			}
@@ -160,7 +158,7 @@ func newEnvSet(storagePath string, client platform.Client) *cobra.Command {
 				return output.Err(platform.ErrAPIError, err.Error(), "", nil)
 			}
 
-			return output.Async([]output.ProcessOutput{
+			return asyncOutput(cmd, client, storagePath, creds, []output.ProcessOutput{
 				output.MapProcessToOutput(process, hostname),
 			})
 		},
@@ -168,6 +166,7 @@ func newEnvSet(storagePath string, client platform.Client) *cobra.Command {
 
 	cmd.Flags().String("service", "", "Service hostname")
 	cmd.Flags().Bool("project", false, "Set project-level env vars")
+	addWaitFlags(cmd)
 	return cmd
 }
 
@@ -193,7 +192,7 @@ func newEnvDelete(storagePath string, client platform.Client) *cobra.Command {
 					return output.Err(platform.ErrAPIError, err.Error(), "", nil)
 				}
 
-				var lastProcess *platform.Process
+				var processes []output.ProcessOutput
 				for _, key := range args {
 					envID := findEnvIDByKey(envs, key)
 					if envID == "" {
@@ -204,12 +203,10 @@ func newEnvDelete(storagePath string, client platform.Client) *cobra.Command {
 					if err != nil {
 						return output.Err(platform.ErrAPIError, err.Error(), "", nil)
 					}
-					lastProcess = proc
+					processes = append(processes, output.MapProcessToOutput(proc, ""))
 				}
-				if lastProcess != nil {
-					return output.Async([]output.ProcessOutput{
-						output.MapProcessToOutput(lastProcess, ""),
-					})
+				if len(processes) > 0 {
+					return asyncOutput(cmd, client, storagePath, creds, processes)
 				}
 				return output.Sync(nil)
 			}
@@ -235,7 +232,7 @@ func newEnvDelete(storagePath string, client platform.Client) *cobra.Command {
 				return output.Err(platform.ErrAPIError, err.Error(), "", nil)
 			}
 
-			var lastProcess *platform.Process
+			var processes []output.ProcessOutput
 			for _, key := range args {
 				envID := findEnvIDByKey(envs, key)
 				if envID == "" {
@@ -247,12 +244,10 @@ func newEnvDelete(storagePath string, client platform.Client) *cobra.Command {
 				if err != nil {
 					return output.Err(platform.ErrAPIError, err.Error(), "", nil)
 				}
-				lastProcess = proc
+				processes = append(processes, output.MapProcessToOutput(proc, hostname))
 			}
-			if lastProcess != nil {
-				return output.Async([]output.ProcessOutput{
-					output.MapProcessToOutput(lastProcess, hostname),
-				})
+			if len(processes) > 0 {
+				return asyncOutput(cmd, client, storagePath, creds, processes)
 			}
 			return output.Sync(nil)
 		},
@@ -260,6 +255,7 @@ func newEnvDelete(storagePath string, client platform.Client) *cobra.Command {
 
 	cmd.Flags().String("service", "", "Service hostname")
 	cmd.Flags().Bool("project", false, "Delete project-level env vars")
+	addWaitFlags(cmd)
 	return cmd
 }
 
@@ -93,6 +93,29 @@ func TestEnvSet_Service(t *testing.T) {
 	}
 }
 
+func TestEnvSet_Project_AllProcesses(t *testing.T) {
+	storagePath := setupAuthenticatedStorage(t)
+	mock := platform.NewMock()
+
+	cmd := NewEnv(storagePath, mock)
+	cmd.SetArgs([]string{"set", "--project", "A=1", "B=2"})
+
+	var stdout bytes.Buffer
+	output.SetWriter(&stdout)
+	defer output.ResetWriter()
+
+	if err := cmd.Execute(); err != nil {
+		t.Fatal(err)
+	}
+
+	var resp map[string]interface{}
+	_ = json.Unmarshal(stdout.Bytes(), &resp)
+	// One process per variable, so --wait can track every one of them.
+	if procs := resp["processes"].([]interface{}); len(procs) != 2 {
+		t.Errorf("processes len = %d, want 2", len(procs))
+	}
+}
+
 func TestEnvSet_EmptyValue(t *testing.T) {
 	storagePath := setupAuthenticatedStorage(t)
 	mock := platform.NewMock().
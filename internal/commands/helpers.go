@@ -10,15 +10,24 @@ import (
 
 // resolveCredentials loads credentials from storage and returns an error response if not authenticated.
 func resolveCredentials(storagePath string) (*auth.Credentials, error) {
+	creds, authErr := loadCredentials(storagePath)
+	if authErr != nil {
+		return nil, output.Err(authErr.Code, authErr.Message, authErr.Suggestion, nil)
+	}
+	return creds, nil
+}
+
+// loadCredentials is resolveCredentials for commands that report the error in their own format.
+func loadCredentials(storagePath string) (*auth.Credentials, *auth.AuthError) {
 	storage := auth.NewStorage(storagePath)
 	mgr := auth.NewManager(storage, nil)
 	creds, err := mgr.GetCredentials()
 	if err != nil {
 		var authErr *auth.AuthError
 		if errors.As(err, &authErr) {
-			return nil, output.Err(authErr.Code, authErr.Message, authErr.Suggestion, nil)
+			return nil, authErr
 		}
-		return nil, output.Err(platform.ErrAuthRequired, "Not authenticated", "Run: zaia login <token>", nil)
+		return nil, &auth.AuthError{Code: platform.ErrAuthRequired, Message: "Not authenticated", Suggestion: "Run: zaia login <token>"}
 	}
 	return creds, nil
 }
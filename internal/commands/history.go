@@ -0,0 +1,208 @@
+package commands
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"github.com/zeropsio/zaia/internal/auth"
+	"github.com/zeropsio/zaia/internal/journal"
+	"github.com/zeropsio/zaia/internal/output"
+	"github.com/zeropsio/zaia/internal/platform"
+)
+
+// defaultHistoryLimit bounds how many journal entries history returns.
+const defaultHistoryLimit = 50
+
+// maxJournalValue is the longest flag value kept verbatim in a journaled command line.
+const maxJournalValue = 64
+
+// historyEntry is a journal entry as history reports it.
+type historyEntry struct {
+	journal.Entry
+	Error string `json:"error,omitempty"` // re-polling failed; status is the last one known
+}
+
+// NewHistory creates the history command listing the processes zaia initiated.
+func NewHistory(storagePath string, client platform.Client) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "history",
+		Short: "Processes initiated from this machine",
+		Long: "Lists the processes zaia started in the current project, newest first, from the local\n" +
+			"journal next to zaia.data. Entries not yet FINISHED, FAILED or CANCELED are re-polled\n" +
+			"and updated; --prune then drops the finished ones from the journal.",
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			creds, err := resolveCredentials(storagePath)
+			if err != nil {
+				return err
+			}
+
+			pendingOnly, _ := cmd.Flags().GetBool("pending")
+			serviceFilter, _ := cmd.Flags().GetString("service")
+			limit, _ := cmd.Flags().GetInt("limit")
+			prune, _ := cmd.Flags().GetBool("prune")
+			if limit < 1 {
+				return output.Err(platform.ErrInvalidParameter, "--limit must be at least 1",
+					"Example: zaia history --limit 20", nil)
+			}
+
+			j := journalFor(storagePath)
+			entries, err := j.Load()
+			if err != nil {
+				return output.Err(platform.ErrJournalError, err.Error(), "", nil)
+			}
+
+			matches := func(e journal.Entry) bool {
+				return e.ProjectID == creds.ProjectID &&
+					(serviceFilter == "" || e.ServiceHostname == serviceFilter)
+			}
+
+			// Re-poll what may still change.
+			var polled []output.ProcessOutput
+			for _, e := range entries {
+				if matches(e) && !isTerminalOutput(e.Status) {
+					polled = append(polled, output.ProcessOutput{ProcessID: e.ProcessID, ActionName: e.ActionName})
+				}
+			}
+			pollErrors := make(map[string]string)
+			updates := make(map[string]processResult)
+			for _, r := range fetchProcesses(cmd.Context(), client, polled, defaultProcessWorkers) {
+				if r.Error != "" {
+					pollErrors[r.ProcessID] = r.Error
+					continue
+				}
+				updates[r.ProcessID] = r
+			}
+
+			// Apply the new statuses to the journal as it is now: other zaia processes may have
+			// appended entries while these were polled.
+			pruned := 0
+			if len(updates) > 0 || prune {
+				now := time.Now().UTC().Format(time.RFC3339)
+				entries, err = j.Update(func(current []journal.Entry) []journal.Entry {
+					pruned = 0
+					kept := current[:0]
+					for _, e := range current {
+						if r, ok := updates[e.ProcessID]; ok && matches(e) {
+							e.Status, e.Finished, e.FailureReason, e.Checked = r.Status, r.Finished, r.FailureReason, now
+						}
+						if prune && matches(e) && isTerminalOutput(e.Status) {
+							pruned++
+							continue
+						}
+						kept = append(kept, e)
+					}
+					return kept
+				})
+				if err != nil {
+					return output.Err(platform.ErrJournalError, err.Error(), "", nil)
+				}
+			}
+
+			var view []historyEntry
+			for _, e := range entries {
+				if !matches(e) || (pendingOnly && isTerminalOutput(e.Status)) {
+					continue
+				}
+				view = append(view, historyEntry{Entry: e, Error: pollErrors[e.ProcessID]})
+			}
+			// Newest first; the journal is in initiation order.
+			for a, b := 0, len(view)-1; a < b; a, b = a+1, b-1 {
+				view[a], view[b] = view[b], view[a]
+			}
+
+			counts := make(map[string]int)
+			for _, e := range view {
+				counts[e.Status]++
+			}
+			total := len(view)
+			if len(view) > limit {
+				view = view[:limit]
+			}
+
+			data := map[string]interface{}{
+				"entries": view,
+				"total":   total,
+				"counts":  counts,
+				"journal": j.FilePath(),
+			}
+			if prune {
+				data["pruned"] = pruned
+			}
+			return output.Sync(data)
+		},
+	}
+
+	cmd.Flags().Bool("pending", false, "Only processes not yet finished, failed or canceled")
+	cmd.Flags().String("service", "", "Only processes of this service hostname")
+	cmd.Flags().Int("limit", defaultHistoryLimit, "Maximum entries returned")
+	cmd.Flags().Bool("prune", false, "Remove finished, failed and canceled processes from the journal")
+	return cmd
+}
+
+// journalFor returns the journal next to the zaia.data of storagePath.
+func journalFor(storagePath string) *journal.Journal {
+	return journal.NextTo(auth.NewStorage(storagePath).FilePath())
+}
+
+// recordProcesses adds initiated processes to the journal. A failure to write it is reported
+// on stderr only: the processes are running either way.
+func recordProcesses(cmd *cobra.Command, storagePath, projectID string, processes []output.ProcessOutput) {
+	line := commandLine(cmd)
+	now := time.Now().UTC().Format(time.RFC3339)
+	entries := make([]journal.Entry, 0, len(processes))
+	for _, p := range processes {
+		entries = append(entries, journal.Entry{
+			ProcessID:       p.ProcessID,
+			ActionName:      p.ActionName,
+			ServiceHostname: p.ServiceHostname,
+			ServiceID:       p.ServiceID,
+			ProjectID:       projectID,
+			Command:         line,
+			Initiated:       now,
+			Status:          p.Status,
+		})
+	}
+	if err := journalFor(storagePath).Append(entries...); err != nil {
+		fmt.Fprintf(cmd.ErrOrStderr(), "zaia: could not record processes in the journal: %v\n", err)
+	}
+}
+
+// commandLine rebuilds the command line of cmd for the journal. Values of KEY=value
+// arguments, and long or multi-line flag values (e.g. --content), are elided.
+func commandLine(cmd *cobra.Command) string {
+	parts := []string{cmd.CommandPath()}
+	cmd.Flags().Visit(func(f *pflag.Flag) {
+		if f.Value.Type() == "bool" {
+			if f.Value.String() == "true" {
+				parts = append(parts, "--"+f.Name)
+			} else {
+				parts = append(parts, "--"+f.Name+"=false")
+			}
+			return
+		}
+		value := f.Value.String()
+		if len(value) > maxJournalValue || strings.Contains(value, "\n") {
+			value = "…"
+		}
+		parts = append(parts, "--"+f.Name, quoteArg(value))
+	})
+	for _, arg := range cmd.Flags().Args() {
+		if key, _, ok := strings.Cut(arg, "="); ok {
+			arg = key + "=…"
+		}
+		parts = append(parts, quoteArg(arg))
+	}
+	return strings.Join(parts, " ")
+}
+
+func quoteArg(s string) string {
+	if s == "" || strings.ContainsAny(s, " \t\"'") {
+		return strconv.Quote(s)
+	}
+	return s
+}
@@ -0,0 +1,170 @@
+package commands
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/zeropsio/zaia/internal/journal"
+	"github.com/zeropsio/zaia/internal/output"
+	"github.com/zeropsio/zaia/internal/platform"
+)
+
+func runHistory(t *testing.T, storagePath string, mock *platform.Mock, args ...string) (map[string]interface{}, error) {
+	t.Helper()
+	cmd := NewHistory(storagePath, mock)
+	cmd.SetArgs(args)
+
+	var stdout bytes.Buffer
+	output.SetWriter(&stdout)
+	defer output.ResetWriter()
+
+	err := cmd.Execute()
+	var resp map[string]interface{}
+	_ = json.Unmarshal(stdout.Bytes(), &resp)
+	return resp, err
+}
+
+func TestHistory_RepollsAndFilters(t *testing.T) {
+	storagePath := setupAuthenticatedStorage(t)
+	j := journalFor(storagePath)
+	if err := j.Append(
+		journal.Entry{ProcessID: "p1", ProjectID: "proj-1", ServiceHostname: "api", Status: "PENDING"},
+		journal.Entry{ProcessID: "p2", ProjectID: "proj-1", ServiceHostname: "api", Status: "PENDING"},
+		journal.Entry{ProcessID: "p3", ProjectID: "proj-1", ServiceHostname: "db", Status: "RUNNING"},
+		journal.Entry{ProcessID: "p4", ProjectID: "other-project", ServiceHostname: "api", Status: "PENDING"},
+	); err != nil {
+		t.Fatal(err)
+	}
+	mock := platform.NewMock().
+		WithProcess(&platform.Process{ID: "p1", Status: "RUNNING"}).
+		WithProcess(&platform.Process{ID: "p2", Status: "DONE"})
+
+	resp, err := runHistory(t, storagePath, mock, "--pending", "--service", "api")
+	if err != nil {
+		t.Fatal(err)
+	}
+	data := resp["data"].(map[string]interface{})
+	entries := data["entries"].([]interface{})
+	if len(entries) != 1 {
+		t.Fatalf("entries = %v, want only p1", entries)
+	}
+	p1 := entries[0].(map[string]interface{})
+	if p1["processId"] != "p1" || p1["status"] != "RUNNING" || p1["checked"] == nil {
+		t.Errorf("p1 = %v, want re-polled RUNNING", p1)
+	}
+
+	// The journal keeps the re-polled status; db and the other project are untouched.
+	saved, err := j.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]string{"p1": "RUNNING", "p2": "FINISHED", "p3": "RUNNING", "p4": "PENDING"}
+	for _, e := range saved {
+		if e.Status != want[e.ProcessID] {
+			t.Errorf("%s status = %s, want %s", e.ProcessID, e.Status, want[e.ProcessID])
+		}
+	}
+}
+
+func TestHistory_PollErrorKeepsEntry(t *testing.T) {
+	storagePath := setupAuthenticatedStorage(t)
+	if err := journalFor(storagePath).Append(journal.Entry{ProcessID: "gone", ProjectID: "proj-1", Status: "PENDING"}); err != nil {
+		t.Fatal(err)
+	}
+
+	resp, err := runHistory(t, storagePath, platform.NewMock(), "--prune")
+	if err != nil {
+		t.Fatal(err)
+	}
+	data := resp["data"].(map[string]interface{})
+	entry := data["entries"].([]interface{})[0].(map[string]interface{})
+	if entry["status"] != "PENDING" || entry["error"] == nil {
+		t.Errorf("entry = %v, want PENDING with error", entry)
+	}
+	if data["pruned"] != float64(0) {
+		t.Errorf("pruned = %v, want 0", data["pruned"])
+	}
+}
+
+func TestHistory_Empty(t *testing.T) {
+	storagePath := setupAuthenticatedStorage(t)
+	resp, err := runHistory(t, storagePath, platform.NewMock())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp["data"].(map[string]interface{})["total"] != float64(0) {
+		t.Errorf("data = %v, want no entries", resp["data"])
+	}
+}
+
+func TestAsyncOutput_RecordsJournal(t *testing.T) {
+	mock := platform.NewMock().
+		WithServices([]platform.ServiceStack{{ID: "s1", Name: "api", Status: "ACTIVE"}})
+	storagePath := setupAuthenticatedStorage(t)
+	root := NewRootForTest(RootDeps{StoragePath: storagePath, Client: mock})
+	root.SetArgs([]string{"env", "set", "--service", "api", "TOKEN=s3cret", "--project=false"})
+
+	var stdout bytes.Buffer
+	output.SetWriter(&stdout)
+	defer output.ResetWriter()
+	if err := root.Execute(); err != nil {
+		t.Fatal(err)
+	}
+
+	entries, err := journalFor(storagePath).Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 {
+		t.Fatalf("entries = %+v, want 1", entries)
+	}
+	e := entries[0]
+	if e.ProjectID != "proj-1" || e.ServiceHostname != "api" || e.Initiated == "" {
+		t.Errorf("entry = %+v", e)
+	}
+	if e.Command != "zaia env set --project=false --service api TOKEN=…" {
+		t.Errorf("command = %q, want env value elided", e.Command)
+	}
+}
+
+// appendingClient records a new process in the journal while history re-polls,
+// as another zaia process running at the same time would.
+type appendingClient struct {
+	*platform.Mock
+	j *journal.Journal
+}
+
+func (c appendingClient) GetProcess(ctx context.Context, id string) (*platform.Process, error) {
+	if err := c.j.Append(journal.Entry{ProcessID: "new-" + id, ProjectID: "proj-1", Status: "PENDING"}); err != nil {
+		return nil, err
+	}
+	return c.Mock.GetProcess(ctx, id)
+}
+
+func TestHistory_KeepsEntriesAppendedDuringPoll(t *testing.T) {
+	storagePath := setupAuthenticatedStorage(t)
+	j := journalFor(storagePath)
+	if err := j.Append(journal.Entry{ProcessID: "p1", ProjectID: "proj-1", Status: "PENDING"}); err != nil {
+		t.Fatal(err)
+	}
+	client := appendingClient{Mock: platform.NewMock().WithProcess(&platform.Process{ID: "p1", Status: "DONE"}), j: j}
+
+	cmd := NewHistory(storagePath, client)
+	cmd.SetArgs([]string{})
+	var stdout bytes.Buffer
+	output.SetWriter(&stdout)
+	defer output.ResetWriter()
+	if err := cmd.Execute(); err != nil {
+		t.Fatal(err)
+	}
+
+	saved, err := j.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(saved) != 2 || saved[0].Status != "FINISHED" || saved[1].ProcessID != "new-p1" {
+		t.Errorf("journal = %+v, want p1 FINISHED and new-p1 kept", saved)
+	}
+}
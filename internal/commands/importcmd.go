@@ -4,8 +4,10 @@ import (
 	"os"
 
 	"github.com/spf13/cobra"
+	"github.com/zeropsio/zaia/internal/catalog"
 	"github.com/zeropsio/zaia/internal/output"
 	"github.com/zeropsio/zaia/internal/platform"
+	"github.com/zeropsio/zaia/internal/validation"
 	"gopkg.in/yaml.v3"
 )
 
@@ -72,13 +74,14 @@ func NewImport(storagePath string, client platform.Client) *cobra.Command {
 				}
 			}
 
-			return output.Async(processes)
+			return asyncOutput(cmd, client, storagePath, creds, processes)
 		},
 	}
 
 	cmd.Flags().String("file", "", "Path to YAML file")
 	cmd.Flags().String("content", "", "Inline YAML content")
 	cmd.Flags().Bool("dry-run", false, "Validate and preview without executing")
+	addWaitFlags(cmd)
 
 	return cmd
 }
@@ -93,52 +96,44 @@ func hasProjectSection(content string) bool {
 }
 
 func importDryRun(content string) error {
-	var parsed map[string]interface{}
-	if err := yaml.Unmarshal([]byte(content), &parsed); err != nil {
+	if issues := validation.ValidateImportYml([]byte(content)); len(issues) > 0 {
 		return output.Err(platform.ErrInvalidImportYml,
-			"Invalid YAML syntax: "+err.Error(), "", nil)
+			"import.yml validation failed",
+			"Fix the listed errors, then re-run with --dry-run",
+			map[string]interface{}{
+				"dryRun": true,
+				"errors": issues,
+			})
 	}
 
-	servicesRaw, ok := parsed["services"]
-	if !ok {
-		return output.Err(platform.ErrInvalidImportYml,
-			"Missing 'services' section",
-			"import.yml must contain a 'services:' array", nil)
+	var parsed struct {
+		Services []map[string]interface{} `yaml:"services"`
 	}
+	_ = yaml.Unmarshal([]byte(content), &parsed)
 
-	servicesList, ok := servicesRaw.([]interface{})
-	if !ok {
-		return output.Err(platform.ErrInvalidImportYml,
-			"'services' must be an array",
-			"Format: services:\n  - hostname: api\n    type: nodejs@22", nil)
-	}
-
-	preview := make([]map[string]interface{}, 0, len(servicesList))
-	for _, s := range servicesList {
-		sMap, ok := s.(map[string]interface{})
-		if !ok {
-			continue
-		}
+	preview := make([]map[string]interface{}, 0, len(parsed.Services))
+	for _, s := range parsed.Services {
 		entry := map[string]interface{}{
-			"action": "create",
+			"action":   "create",
+			"hostname": s["hostname"],
+			"type":     s["type"],
 		}
-		if h, ok := sMap["hostname"]; ok {
-			entry["hostname"] = h
+		if m, ok := s["mode"]; ok {
+			entry["mode"] = m
 		}
-		if t, ok := sMap["type"]; ok {
-			entry["type"] = t
-		}
-		// Also check for "name" as hostname alias
-		if h, ok := sMap["name"]; ok && entry["hostname"] == nil {
-			entry["hostname"] = h
+		if id, ok := s["type"].(string); ok {
+			if t, _, found := catalog.Resolve(id); found {
+				entry["category"] = t.Category
+			}
 		}
 		preview = append(preview, entry)
 	}
 
+	warnings, _ := splitFindings(validation.LintImportYml([]byte(content)))
 	return output.Sync(map[string]interface{}{
 		"dryRun":   true,
 		"valid":    true,
 		"services": preview,
-		"warnings": []string{},
+		"warnings": warnings,
 	})
 }
@@ -185,3 +185,35 @@ func TestImportCmd_NoInput_Error(t *testing.T) {
 		t.Errorf("code = %v, want INVALID_PARAMETER", resp["code"])
 	}
 }
+
+func TestImportCmd_DryRun_InvalidService(t *testing.T) {
+	storagePath := setupAuthenticatedStorage(t)
+	mock := platform.NewMock()
+
+	content := `services:
+  - hostname: My_Api
+    type: nodejs@21
+`
+	cmd := NewImport(storagePath, mock)
+	cmd.SetArgs([]string{"--content", content, "--dry-run"})
+
+	var stdout bytes.Buffer
+	output.SetWriter(&stdout)
+	defer output.ResetWriter()
+
+	err := cmd.Execute()
+	if err == nil {
+		t.Fatal("expected error for invalid service definition")
+	}
+
+	var resp map[string]interface{}
+	_ = json.Unmarshal(stdout.Bytes(), &resp)
+	if resp["code"] != "INVALID_IMPORT_YML" {
+		t.Errorf("code = %v, want INVALID_IMPORT_YML", resp["code"])
+	}
+	ctx := resp["context"].(map[string]interface{})
+	errs := ctx["errors"].([]interface{})
+	if len(errs) != 2 {
+		t.Errorf("errors = %d, want 2 (hostname + type): %v", len(errs), errs)
+	}
+}
@@ -0,0 +1,280 @@
+package commands
+
+import (
+	"fmt"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/zeropsio/zaia/internal/knowledge"
+	"github.com/zeropsio/zaia/internal/output"
+	"github.com/zeropsio/zaia/internal/platform"
+)
+
+// NewKnowledge creates the knowledge command for browsing the knowledge base and checking its docs.
+func NewKnowledge() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "knowledge",
+		Short: "Knowledge base tools",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return output.Err(platform.ErrInvalidUsage,
+				"No subcommand specified for 'knowledge'",
+				"Run: zaia knowledge <list|tree|related|lint|examples>",
+				map[string]interface{}{"availableSubcommands": []string{"list", "tree", "related", "lint", "examples"}})
+		},
+	}
+
+	cmd.AddCommand(newKnowledgeList())
+	cmd.AddCommand(newKnowledgeTree())
+	cmd.AddCommand(newKnowledgeRelated())
+	cmd.AddCommand(newKnowledgeLint())
+	cmd.AddCommand(newKnowledgeExamples())
+
+	return cmd
+}
+
+func newKnowledgeList() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "List knowledge documents with their URIs and descriptions",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			store, err := knowledgeStore(cmd)
+			if err != nil {
+				return err
+			}
+			defer store.Close()
+
+			category, _ := cmd.Flags().GetString("category")
+			if err := checkCategory(store, category); err != nil {
+				return err
+			}
+
+			resources := make([]knowledge.Resource, 0)
+			for _, r := range store.List() {
+				if category == "" || r.Category == category {
+					resources = append(resources, r)
+				}
+			}
+			return output.Sync(map[string]interface{}{
+				"count":     len(resources),
+				"resources": resources,
+			})
+		},
+	}
+	cmd.Flags().String("category", "", "Only documents of this category: services, decisions, config, ...")
+	cmd.Flags().StringArray("knowledge-dir", nil, "Extra markdown knowledge directory, listed as team:// (repeatable)")
+	return cmd
+}
+
+func newKnowledgeTree() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "tree",
+		Short: "Show knowledge documents grouped by category",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			store, err := knowledgeStore(cmd)
+			if err != nil {
+				return err
+			}
+			defer store.Close()
+
+			resources := store.List()
+			return output.Sync(map[string]interface{}{
+				"count":      len(resources),
+				"categories": knowledge.Tree(resources),
+			})
+		},
+	}
+	cmd.Flags().StringArray("knowledge-dir", nil, "Extra markdown knowledge directory, listed as team:// (repeatable)")
+	return cmd
+}
+
+func newKnowledgeRelated() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "related <uri>",
+		Short: "Follow the See Also links of a knowledge document",
+		Long: "Walks the See Also graph from <uri> breadth-first. Each document is listed once, at the\n" +
+			"number of links it takes to reach it, with the document that links to it (via).",
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			depth, _ := cmd.Flags().GetInt("depth")
+			if depth < 1 {
+				return output.Err(platform.ErrInvalidParameter,
+					fmt.Sprintf("Invalid depth: %d", depth), "Use --depth 1 or more", nil)
+			}
+
+			store, err := knowledgeStore(cmd)
+			if err != nil {
+				return err
+			}
+			defer store.Close()
+
+			doc, related, err := store.Related(args[0], depth)
+			if err != nil {
+				return output.Err("NOT_FOUND", "Document not found", "Run: zaia knowledge list",
+					map[string]interface{}{"uri": args[0]})
+			}
+			return output.Sync(map[string]interface{}{
+				"uri":     doc.URI,
+				"title":   doc.Title,
+				"depth":   depth,
+				"related": related,
+			})
+		},
+	}
+	cmd.Flags().Int("depth", 2, "How many See Also links to follow")
+	cmd.Flags().StringArray("knowledge-dir", nil, "Extra markdown knowledge directory, indexed as team:// (repeatable)")
+	return cmd
+}
+
+// checkCategory rejects a --category no document of store belongs to; "" passes.
+func checkCategory(store *knowledge.Store, category string) error {
+	if category == "" || slices.Contains(store.Categories(), category) {
+		return nil
+	}
+	return output.Err(platform.ErrInvalidParameter,
+		fmt.Sprintf("Unknown category: %s", category),
+		"Use --category "+strings.Join(store.Categories(), ", "),
+		map[string]interface{}{"availableCategories": store.Categories()})
+}
+
+func newKnowledgeLint() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "lint [dir]",
+		Short: "Check knowledge docs for required sections, dead links, duplicate keywords and invalid YAML examples",
+		Long: "Lints the markdown under dir (indexed as team://), or without dir the embedded docs together\n" +
+			"with the " + knowledgePathEnv + " and --knowledge-dir directories.\n" +
+			"Mark a ```yaml block with placeholders as ```yaml template to skip its validation.",
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			source := knowledgeSource(cmd)
+			var report knowledge.LintReport
+			if len(args) == 0 {
+				store, err := knowledgeStore(cmd)
+				if err != nil {
+					return err
+				}
+				defer store.Close()
+				report = store.Lint()
+			} else {
+				source = args[0]
+				var err error
+				if report, err = knowledge.LintDir(source); err != nil {
+					return output.Err(platform.ErrFileNotFound,
+						"Cannot read knowledge dir: "+err.Error(), "Pass a directory of markdown docs", nil)
+				}
+			}
+
+			if len(report.Errors) > 0 {
+				return output.Err(platform.ErrKnowledgeLintFailed,
+					fmt.Sprintf("Knowledge lint found %d errors in %d documents", len(report.Errors), report.Documents),
+					"Fix the errors listed in context.errors",
+					map[string]interface{}{
+						"source":    source,
+						"documents": report.Documents,
+						"errors":    report.Errors,
+						"warnings":  report.Warnings,
+					})
+			}
+			return output.Sync(map[string]interface{}{
+				"source":    source,
+				"valid":     true,
+				"documents": report.Documents,
+				"warnings":  report.Warnings,
+			})
+		},
+	}
+	cmd.Flags().StringArray("knowledge-dir", nil, "Extra markdown knowledge directory to lint as team:// (repeatable, without dir)")
+	return cmd
+}
+
+// knowledgeSource names what lint and examples check without a dir argument.
+func knowledgeSource(cmd *cobra.Command) string {
+	if dirs := knowledgeDirs(cmd); len(dirs) > 0 {
+		return "embedded+" + strings.Join(dirs, string(filepath.ListSeparator))
+	}
+	return "embedded"
+}
+
+func newKnowledgeExamples() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "examples [dir]",
+		Short: "Extract the YAML examples of knowledge docs and validate them as zerops.yml or import.yml",
+		Long: "Lists the ```yaml blocks of the docs under dir, or without dir of the embedded docs and the\n" +
+			knowledgePathEnv + " and --knowledge-dir directories.\n" +
+			"Each block is classified as zerops.yml, import.yml (whole files, or a single setup / a list of\n" +
+			"services validated in a wrapper), fragment or template, and validated like `zaia validate`.",
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			kind, _ := cmd.Flags().GetString("kind")
+			invalidOnly, _ := cmd.Flags().GetBool("invalid")
+
+			kinds := []string{knowledge.KindZeropsYml, knowledge.KindImportYml, knowledge.KindFragment,
+				knowledge.KindTemplate, knowledge.KindInvalid}
+			if kind != "" && !slices.Contains(kinds, kind) {
+				return output.Err(platform.ErrInvalidParameter,
+					"Unknown kind: "+kind, "Use --kind "+strings.Join(kinds, ", "), nil)
+			}
+
+			source := knowledgeSource(cmd)
+			var examples []knowledge.Example
+			if len(args) == 0 {
+				store, err := knowledgeStore(cmd)
+				if err != nil {
+					return err
+				}
+				defer store.Close()
+				examples = store.Examples()
+			} else {
+				source = args[0]
+				var err error
+				if examples, err = knowledge.DirExamples(source); err != nil {
+					return output.Err(platform.ErrFileNotFound,
+						"Cannot read knowledge dir: "+err.Error(), "Pass a directory of markdown docs", nil)
+				}
+			}
+
+			counts := make(map[string]int)
+			list := make([]map[string]interface{}, 0)
+			var invalid []map[string]interface{}
+			for _, ex := range examples {
+				counts[ex.Kind]++
+				entry := map[string]interface{}{
+					"uri":     ex.URI,
+					"file":    ex.File,
+					"line":    ex.Line,
+					"kind":    ex.Kind,
+					"partial": ex.Partial,
+					"valid":   ex.Valid(),
+					"issues":  ex.Issues,
+					"content": ex.Content,
+				}
+				if !ex.Valid() {
+					invalid = append(invalid, entry)
+				}
+				if (kind == "" || ex.Kind == kind) && (!invalidOnly || !ex.Valid()) {
+					list = append(list, entry)
+				}
+			}
+
+			if len(invalid) > 0 {
+				return output.Err(platform.ErrKnowledgeLintFailed,
+					fmt.Sprintf("%d of %d YAML examples are invalid", len(invalid), len(examples)),
+					"Fix the examples, or mark blocks with placeholders as ```yaml template",
+					map[string]interface{}{"source": source, "counts": counts, "invalid": invalid})
+			}
+			return output.Sync(map[string]interface{}{
+				"source":   source,
+				"valid":    true,
+				"counts":   counts,
+				"examples": list,
+			})
+		},
+	}
+	cmd.Flags().String("kind", "", "Only list examples of this kind: zerops.yml, import.yml, fragment, template")
+	cmd.Flags().Bool("invalid", false, "Only list invalid examples")
+	cmd.Flags().StringArray("knowledge-dir", nil, "Extra markdown knowledge directory to check as team:// (repeatable, without dir)")
+	return cmd
+}
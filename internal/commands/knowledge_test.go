@@ -0,0 +1,236 @@
+package commands
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/zeropsio/zaia/internal/output"
+)
+
+func runKnowledge(t *testing.T, args ...string) (map[string]interface{}, error) {
+	t.Helper()
+	cmd := NewKnowledge()
+	cmd.SetArgs(args)
+
+	var stdout bytes.Buffer
+	output.SetWriter(&stdout)
+	defer output.ResetWriter()
+
+	err := cmd.Execute()
+	var resp map[string]interface{}
+	_ = json.Unmarshal(stdout.Bytes(), &resp)
+	return resp, err
+}
+
+func TestKnowledgeLint_Embedded(t *testing.T) {
+	resp, err := runKnowledge(t, "lint")
+	if err != nil {
+		t.Fatalf("embedded docs should lint clean: %v", resp)
+	}
+	data := resp["data"].(map[string]interface{})
+	if data["valid"] != true || data["source"] != "embedded" {
+		t.Errorf("data = %v", data)
+	}
+}
+
+func TestKnowledgeLint_DirWithErrors(t *testing.T) {
+	dir := t.TempDir()
+	doc := "# Rollback\n\n## Keywords\nrollback\n\n## TL;DR\nUndo a deploy.\n\n## See Also\n- zerops://docs/services/nope\n"
+	if err := os.WriteFile(filepath.Join(dir, "rollback.md"), []byte(doc), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	resp, err := runKnowledge(t, "lint", dir)
+	if err == nil {
+		t.Fatal("expected error for dead link")
+	}
+	if resp["code"] != "KNOWLEDGE_LINT_FAILED" {
+		t.Errorf("code = %v, want KNOWLEDGE_LINT_FAILED", resp["code"])
+	}
+	ctx := resp["context"].(map[string]interface{})
+	errs := ctx["errors"].([]interface{})
+	if len(errs) != 1 || errs[0].(map[string]interface{})["rule"] != "dead-link" {
+		t.Errorf("errors = %v, want one dead-link", errs)
+	}
+}
+
+func TestKnowledgeLint_MissingDir(t *testing.T) {
+	resp, err := runKnowledge(t, "lint", filepath.Join(t.TempDir(), "missing"))
+	if err == nil {
+		t.Fatal("expected error for missing directory")
+	}
+	if resp["code"] != "FILE_NOT_FOUND" {
+		t.Errorf("code = %v, want FILE_NOT_FOUND", resp["code"])
+	}
+}
+
+func TestKnowledge_NoSubcommand(t *testing.T) {
+	resp, err := runKnowledge(t)
+	if err == nil {
+		t.Fatal("expected error without subcommand")
+	}
+	if resp["code"] != "INVALID_USAGE" {
+		t.Errorf("code = %v, want INVALID_USAGE", resp["code"])
+	}
+}
+
+func TestKnowledgeExamples_Embedded(t *testing.T) {
+	resp, err := runKnowledge(t, "examples", "--kind", "import.yml")
+	if err != nil {
+		t.Fatalf("embedded examples should be valid: %v", resp)
+	}
+	data := resp["data"].(map[string]interface{})
+	examples := data["examples"].([]interface{})
+	if len(examples) == 0 {
+		t.Fatal("expected import.yml examples")
+	}
+	for _, raw := range examples {
+		if ex := raw.(map[string]interface{}); ex["kind"] != "import.yml" || ex["content"] == "" {
+			t.Errorf("example = %v", ex)
+		}
+	}
+}
+
+func TestKnowledgeExamples_DirWithInvalid(t *testing.T) {
+	dir := t.TempDir()
+	doc := "# Setup\n\n## Example\n```yaml\nzerops:\n  - setup: api\n    build:\n      base: nodejs@22\n      deployFiles: ./\n" +
+		"    run:\n      start: node index.js\n      bogus: true\n```\n"
+	if err := os.WriteFile(filepath.Join(dir, "setup.md"), []byte(doc), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	resp, err := runKnowledge(t, "examples", dir)
+	if err == nil {
+		t.Fatal("expected error for invalid example")
+	}
+	if resp["code"] != "KNOWLEDGE_LINT_FAILED" {
+		t.Errorf("code = %v, want KNOWLEDGE_LINT_FAILED", resp["code"])
+	}
+	invalid := resp["context"].(map[string]interface{})["invalid"].([]interface{})
+	if len(invalid) != 1 {
+		t.Fatalf("invalid = %v, want 1", invalid)
+	}
+	ex := invalid[0].(map[string]interface{})
+	if ex["uri"] != "team://setup#example" || ex["kind"] != "zerops.yml" {
+		t.Errorf("example = %v", ex)
+	}
+	issue := ex["issues"].([]interface{})[0].(map[string]interface{})
+	if issue["line"] != float64(12) {
+		t.Errorf("issue line = %v, want 12", issue["line"])
+	}
+}
+
+func TestKnowledgeExamples_KnowledgeDir(t *testing.T) {
+	dir := t.TempDir()
+	doc := "# Setup\n\n## Example\n```yaml\nzerops:\n  - setup: api\n    run:\n      bogus: true\n```\n"
+	if err := os.WriteFile(filepath.Join(dir, "setup.md"), []byte(doc), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv(knowledgePathEnv, "")
+
+	// Without a dir argument, --knowledge-dir docs are checked along with the embedded ones.
+	for _, sub := range []string{"examples", "lint"} {
+		resp, err := runKnowledge(t, sub, "--knowledge-dir", dir)
+		if err == nil {
+			t.Fatalf("%s: expected error for the team doc", sub)
+		}
+		if resp["code"] != "KNOWLEDGE_LINT_FAILED" {
+			t.Errorf("%s: code = %v, want KNOWLEDGE_LINT_FAILED", sub, resp["code"])
+		}
+		if got := resp["context"].(map[string]interface{})["source"]; got != "embedded+"+dir {
+			t.Errorf("%s: source = %v", sub, got)
+		}
+	}
+}
+
+func TestKnowledgeExamples_UnknownKind(t *testing.T) {
+	resp, err := runKnowledge(t, "examples", "--kind", "docker-compose")
+	if err == nil {
+		t.Fatal("expected error for unknown kind")
+	}
+	if resp["code"] != "INVALID_PARAMETER" {
+		t.Errorf("code = %v, want INVALID_PARAMETER", resp["code"])
+	}
+}
+
+func TestKnowledgeList_Category(t *testing.T) {
+	resp, err := runKnowledge(t, "list", "--category", "decisions")
+	if err != nil {
+		t.Fatal(err)
+	}
+	data := resp["data"].(map[string]interface{})
+	resources := data["resources"].([]interface{})
+	if len(resources) != 5 || data["count"] != float64(5) {
+		t.Fatalf("resources = %d, count = %v, want 5 decisions", len(resources), data["count"])
+	}
+	for _, r := range resources {
+		res := r.(map[string]interface{})
+		if res["category"] != "decisions" || res["description"] == "" {
+			t.Errorf("resource = %v", res)
+		}
+	}
+}
+
+func TestKnowledgeList_UnknownCategory(t *testing.T) {
+	resp, err := runKnowledge(t, "list", "--category", "recipes")
+	if err == nil {
+		t.Fatal("expected error for unknown category")
+	}
+	if resp["code"] != "INVALID_PARAMETER" {
+		t.Errorf("code = %v, want INVALID_PARAMETER", resp["code"])
+	}
+}
+
+func TestKnowledgeTree(t *testing.T) {
+	resp, err := runKnowledge(t, "tree")
+	if err != nil {
+		t.Fatal(err)
+	}
+	data := resp["data"].(map[string]interface{})
+	total := 0
+	names := []string{}
+	for _, c := range data["categories"].([]interface{}) {
+		cat := c.(map[string]interface{})
+		names = append(names, cat["name"].(string))
+		total += len(cat["documents"].([]interface{}))
+	}
+	if float64(total) != data["count"] {
+		t.Errorf("documents in categories = %d, count = %v", total, data["count"])
+	}
+	if len(names) < 5 || names[0] != "config" {
+		t.Errorf("categories = %v, want sorted, starting with config", names)
+	}
+}
+
+func TestKnowledgeRelated(t *testing.T) {
+	resp, err := runKnowledge(t, "related", "zerops://services/postgresql", "--depth", "1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	data := resp["data"].(map[string]interface{})
+	if data["uri"] != "zerops://docs/services/postgresql" {
+		t.Errorf("uri = %v", data["uri"])
+	}
+	related := data["related"].([]interface{})
+	if len(related) == 0 {
+		t.Fatal("expected See Also documents")
+	}
+	first := related[0].(map[string]interface{})
+	if first["uri"] != "zerops://docs/decisions/choose-database" || first["depth"] != float64(1) {
+		t.Errorf("first related = %v", first)
+	}
+}
+
+func TestKnowledgeRelated_Errors(t *testing.T) {
+	resp, err := runKnowledge(t, "related", "zerops://docs/services/nope")
+	if err == nil || resp["code"] != "NOT_FOUND" {
+		t.Errorf("missing doc: err = %v, code = %v", err, resp["code"])
+	}
+	resp, err = runKnowledge(t, "related", "zerops://docs/services/postgresql", "--depth", "0")
+	if err == nil || resp["code"] != "INVALID_PARAMETER" {
+		t.Errorf("depth 0: err = %v, code = %v", err, resp["code"])
+	}
+}
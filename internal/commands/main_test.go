@@ -0,0 +1,18 @@
+package commands
+
+import (
+	"os"
+	"testing"
+)
+
+// TestMain keeps the search index cache out of the user's cache dir.
+func TestMain(m *testing.M) {
+	dir, err := os.MkdirTemp("", "zaia-cache-")
+	if err != nil {
+		panic(err)
+	}
+	_ = os.Setenv(cacheDirEnv, dir)
+	code := m.Run()
+	_ = os.RemoveAll(dir)
+	os.Exit(code)
+}
@@ -57,13 +57,14 @@ func newLifecycleCmd(storagePath string, client platform.Client, action string)
 				return output.Err(platform.ErrAPIError, err.Error(), "", nil)
 			}
 
-			return output.Async([]output.ProcessOutput{
+			return asyncOutput(cmd, client, storagePath, creds, []output.ProcessOutput{
 				output.MapProcessToOutput(process, hostname),
 			})
 		},
 	}
 
 	cmd.Flags().String("service", "", "Service hostname (required)")
+	addWaitFlags(cmd)
 	return cmd
 }
 
@@ -131,7 +132,7 @@ func NewScale(storagePath string, client platform.Client) *cobra.Command {
 				})
 			}
 
-			return output.Async([]output.ProcessOutput{
+			return asyncOutput(cmd, client, storagePath, creds, []output.ProcessOutput{
 				output.MapProcessToOutput(process, hostname),
 			})
 		},
@@ -147,6 +148,7 @@ func NewScale(storagePath string, client platform.Client) *cobra.Command {
 	cmd.Flags().Float64("max-disk", 0, "Max disk in GB (0.5-250)")
 	cmd.Flags().Int32("min-replicas", 0, "Min containers (1-10)")
 	cmd.Flags().Int32("max-replicas", 0, "Max containers (1-10)")
+	addWaitFlags(cmd)
 
 	return cmd
 }
@@ -1,37 +1,274 @@
 package commands
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"sync"
+	"time"
 
 	"github.com/spf13/cobra"
 	"github.com/zeropsio/zaia/internal/output"
 	"github.com/zeropsio/zaia/internal/platform"
 )
 
+// defaultWaitTimeout bounds --wait unless --wait-timeout is given.
+const defaultWaitTimeout = 10 * time.Minute
+
+// defaultProcessWorkers bounds concurrent status queries of a batch.
+const defaultProcessWorkers = 8
+
+// statusUnknown marks a batch entry whose status could not be fetched.
+const statusUnknown = "UNKNOWN"
+
 // NewProcess creates the process command for checking async process status.
 func NewProcess(storagePath string, client platform.Client) *cobra.Command {
 	cmd := &cobra.Command{
-		Use:   "process <id>",
+		Use:   "process <id> [<id> ...]",
 		Short: "Check process status",
-		Args:  cobra.ExactArgs(1),
+		Long: "Returns the current status of a process. With --wait, polls until the process is\n" +
+			"FINISHED, FAILED or CANCELED, backing off from --interval up to 30s between polls.\n" +
+			"--stream waits too, writing NDJSON progress events before the final envelope.\n\n" +
+			"With several IDs, or --from-stdin reading the envelope of an async command\n" +
+			"(zaia import ... | zaia process --from-stdin), queries them concurrently and returns\n" +
+			"every process with counts by status.",
 		RunE: func(cmd *cobra.Command, args []string) error {
 			_, err := resolveCredentials(storagePath)
 			if err != nil {
 				return err
 			}
 
-			processID := args[0]
-			ctx := cmd.Context()
-
-			process, err := client.GetProcess(ctx, processID)
-			if err != nil {
-				return output.Err(platform.ErrProcessNotFound,
-					fmt.Sprintf("Process '%s' not found", processID), "", nil)
+			fromStdin, _ := cmd.Flags().GetBool("from-stdin")
+			if len(args) == 1 && !fromStdin {
+				return processSingle(cmd, client, args[0])
+			}
+			if len(args) == 0 && !fromStdin {
+				return output.Err(platform.ErrInvalidUsage, "No process ID given",
+					"Run: zaia process <id> [<id> ...], or pipe an async envelope into zaia process --from-stdin", nil)
 			}
 
-			out := output.MapProcessToOutput(process, "")
-			return output.Sync(out)
+			processes := make([]output.ProcessOutput, 0, len(args))
+			for _, id := range args {
+				processes = append(processes, output.ProcessOutput{ProcessID: id})
+			}
+			if fromStdin {
+				piped, err := readProcessEnvelope(cmd.InOrStdin())
+				if err != nil {
+					return err
+				}
+				processes = append(processes, piped...)
+			}
+			return processBatch(cmd, client, uniqueProcesses(processes))
 		},
 	}
+	cmd.Flags().Duration("interval", platform.DefaultWaitInterval, "First polling interval, doubled after each poll (with --wait)")
+	cmd.Flags().Bool("from-stdin", false, "Read processes from a zaia envelope on stdin")
+	cmd.Flags().Int("concurrency", defaultProcessWorkers, "Maximum concurrent status queries")
+	addWaitFlags(cmd)
 	return cmd
 }
+
+// processSingle reports one process, polling it with --wait or --stream.
+func processSingle(cmd *cobra.Command, client platform.Client, processID string) error {
+	ctx := cmd.Context()
+
+	wait, _ := cmd.Flags().GetBool("wait")
+	stream, _ := cmd.Flags().GetBool("stream")
+	if !wait && !stream {
+		process, err := client.GetProcess(ctx, processID)
+		if err != nil {
+			return output.Err(platform.ErrProcessNotFound,
+				fmt.Sprintf("Process '%s' not found", processID), "", nil)
+		}
+		return output.Sync(output.MapProcessToOutput(process, ""))
+	}
+
+	timeout, interval, err := processWaitFlags(cmd)
+	if err != nil {
+		return err
+	}
+
+	opts := platform.WaitOptions{Interval: interval}
+	var progress *progressStream
+	if stream {
+		progress = newProgressStream([]output.ProcessOutput{{ProcessID: processID}}, time.Now())
+		progress.startHeartbeat(heartbeatFlag(cmd))
+		opts.OnPoll = func(p *platform.Process) { progress.report(output.MapProcessToOutput(p, "")) }
+	}
+
+	waitCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	process, err := platform.WaitProcess(waitCtx, client, processID, opts)
+	if progress != nil {
+		progress.stop()
+	}
+	switch {
+	case errors.Is(err, context.DeadlineExceeded):
+		var last interface{}
+		if process != nil {
+			last = output.MapProcessToOutput(process, "")
+		}
+		return output.Err(platform.ErrWaitTimeout,
+			fmt.Sprintf("Process '%s' did not finish within %s", processID, timeout),
+			fmt.Sprintf("Run: zaia process %s --wait --wait-timeout <longer>", processID),
+			map[string]interface{}{"process": last})
+	case err != nil && process == nil:
+		return output.Err(platform.ErrProcessNotFound,
+			fmt.Sprintf("Process '%s' not found", processID), "", nil)
+	case err != nil:
+		return output.Err(platform.ErrAPIError, err.Error(), "", nil)
+	}
+
+	out := output.MapProcessToOutput(process, "")
+	if process.Status == platform.ProcessFailed {
+		return processFailedErr(out)
+	}
+	return output.Sync(out)
+}
+
+// processBatch reports many processes with counts by status: their current status, or
+// with --wait/--stream their final status, reported as for async commands.
+func processBatch(cmd *cobra.Command, client platform.Client, processes []output.ProcessOutput) error {
+	wait, _ := cmd.Flags().GetBool("wait")
+	stream, _ := cmd.Flags().GetBool("stream")
+	if wait || stream {
+		timeout, interval, err := processWaitFlags(cmd)
+		if err != nil {
+			return err
+		}
+		return waitAndReport(cmd.Context(), client, processes, waitConfig{
+			timeout:   timeout,
+			interval:  interval,
+			stream:    stream,
+			heartbeat: heartbeatFlag(cmd),
+		})
+	}
+
+	workers, _ := cmd.Flags().GetInt("concurrency")
+	if workers < 1 {
+		return output.Err(platform.ErrInvalidParameter, "--concurrency must be at least 1",
+			"Example: zaia process <id> <id> --concurrency 8", nil)
+	}
+	results := fetchProcesses(cmd.Context(), client, processes, workers)
+	counts := countByStatus(results)
+	summary := map[string]interface{}{
+		"processes": results,
+		"total":     len(results),
+		"counts":    counts,
+	}
+	if len(results) > 0 && counts[statusUnknown] == len(results) {
+		return output.Err(platform.ErrProcessNotFound,
+			fmt.Sprintf("None of the %d processes could be found", len(results)), "", summary)
+	}
+	return output.Sync(summary)
+}
+
+// processWaitFlags reads and checks --wait-timeout and --interval.
+func processWaitFlags(cmd *cobra.Command) (timeout, interval time.Duration, err error) {
+	timeout, _ = cmd.Flags().GetDuration("wait-timeout")
+	interval, _ = cmd.Flags().GetDuration("interval")
+	if timeout <= 0 || interval <= 0 {
+		return 0, 0, output.Err(platform.ErrInvalidParameter,
+			"--wait-timeout and --interval must be positive durations",
+			"Example: zaia process <id> --wait --wait-timeout 10m --interval 2s", nil)
+	}
+	return timeout, interval, nil
+}
+
+// heartbeatFlag reads --heartbeat.
+func heartbeatFlag(cmd *cobra.Command) time.Duration {
+	heartbeat, _ := cmd.Flags().GetDuration("heartbeat")
+	if heartbeat <= 0 {
+		return defaultHeartbeat
+	}
+	return heartbeat
+}
+
+// fetchProcesses queries the processes with at most workers concurrent requests and returns
+// them in input order. A process that cannot be fetched gets status UNKNOWN and an error.
+func fetchProcesses(ctx context.Context, client platform.Client, processes []output.ProcessOutput, workers int) []processResult {
+	results := make([]processResult, len(processes))
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for range min(workers, len(processes)) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				proc := processes[i]
+				p, err := client.GetProcess(ctx, proc.ProcessID)
+				if err != nil {
+					proc.Status = statusUnknown
+					results[i] = processResult{ProcessOutput: proc, Error: err.Error()}
+					continue
+				}
+				results[i] = processResult{ProcessOutput: mergePolled(proc, p)}
+			}
+		}()
+	}
+	for i := range processes {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+	return results
+}
+
+// readProcessEnvelope reads the processes of an async envelope, or of the sync summary
+// written by --wait, from r.
+func readProcessEnvelope(r io.Reader) ([]output.ProcessOutput, error) {
+	var envelope struct {
+		Type      string                 `json:"type"`
+		Code      string                 `json:"code"`
+		Processes []output.ProcessOutput `json:"processes"`
+		Data      struct {
+			Processes []output.ProcessOutput `json:"processes"`
+		} `json:"data"`
+	}
+	if err := json.NewDecoder(r).Decode(&envelope); err != nil {
+		return nil, output.Err(platform.ErrInvalidParameter,
+			fmt.Sprintf("stdin is not a zaia JSON envelope: %v", err),
+			"Pipe an async command into it: zaia import --file services.yml | zaia process --from-stdin", nil)
+	}
+	switch envelope.Type {
+	case "async":
+		return envelope.Processes, nil
+	case "sync":
+		return envelope.Data.Processes, nil
+	case "error":
+		return nil, output.Err(platform.ErrInvalidParameter,
+			fmt.Sprintf("stdin holds an error envelope (%s), not processes", envelope.Code), "", nil)
+	}
+	return nil, output.Err(platform.ErrInvalidParameter,
+		fmt.Sprintf("stdin envelope type %q has no processes", envelope.Type), "", nil)
+}
+
+// uniqueProcesses drops repeated process IDs, keeping the first occurrence.
+func uniqueProcesses(processes []output.ProcessOutput) []output.ProcessOutput {
+	seen := make(map[string]bool, len(processes))
+	unique := processes[:0]
+	for _, p := range processes {
+		if !seen[p.ProcessID] {
+			seen[p.ProcessID] = true
+			unique = append(unique, p)
+		}
+	}
+	return unique
+}
+
+// processFailedErr reports a process that ended FAILED, with its failure reason.
+func processFailedErr(out output.ProcessOutput) error {
+	reason := "no reason given"
+	if out.FailureReason != nil {
+		reason = *out.FailureReason
+	}
+	return output.Err(platform.ErrProcessFailed,
+		fmt.Sprintf("Process '%s' (%s) failed: %s", out.ProcessID, out.ActionName, reason),
+		"Check the logs: zaia logs --service <hostname>",
+		map[string]interface{}{
+			"process":       out,
+			"failureReason": out.FailureReason,
+		})
+}
@@ -3,6 +3,8 @@ package commands
 import (
 	"bytes"
 	"encoding/json"
+	"errors"
+	"strings"
 	"testing"
 
 	"github.com/zeropsio/zaia/internal/output"
@@ -183,3 +185,192 @@ func TestCancelCmd_ProcessNotFound(t *testing.T) {
 		t.Errorf("code = %v, want PROCESS_NOT_FOUND", resp["code"])
 	}
 }
+
+// runProcessWait runs process --wait through the root command, which owns --wait.
+func runProcessWait(t *testing.T, mock *platform.Mock, args ...string) (map[string]interface{}, error) {
+	t.Helper()
+	args = append([]string{"process"}, args...)
+	return runWithRoot(t, mock, append(args, "--wait", "--interval", "1ms")...)
+}
+
+func TestProcessCmd_Wait_Finished(t *testing.T) {
+	mock := platform.NewMock().
+		WithProcess(&platform.Process{ID: "proc-1", ActionName: "restart", Status: "PENDING"}).
+		WithProcessProgress("proc-1", "PENDING", "RUNNING", "RUNNING", "DONE")
+
+	resp, err := runProcessWait(t, mock, "proc-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	data := resp["data"].(map[string]interface{})
+	if data["status"] != "FINISHED" {
+		t.Errorf("status = %v, want FINISHED", data["status"])
+	}
+}
+
+func TestProcessCmd_Wait_Failed(t *testing.T) {
+	reason := "Health check failed"
+	mock := platform.NewMock().
+		WithProcess(&platform.Process{ID: "proc-1", ActionName: "restart", Status: "RUNNING", FailReason: &reason}).
+		WithProcessProgress("proc-1", "RUNNING", "FAILED")
+
+	resp, err := runProcessWait(t, mock, "proc-1")
+	var zaiaErr *output.ZaiaError
+	if !errors.As(err, &zaiaErr) || zaiaErr.ExitCode() == 0 {
+		t.Fatalf("err = %v, want nonzero exit", err)
+	}
+	if resp["code"] != "PROCESS_FAILED" {
+		t.Errorf("code = %v, want PROCESS_FAILED", resp["code"])
+	}
+	ctx := resp["context"].(map[string]interface{})
+	if ctx["failureReason"] != reason {
+		t.Errorf("failureReason = %v, want %q", ctx["failureReason"], reason)
+	}
+}
+
+func TestProcessCmd_Wait_Timeout(t *testing.T) {
+	mock := platform.NewMock().
+		WithProcess(&platform.Process{ID: "proc-1", ActionName: "restart", Status: "RUNNING"})
+
+	resp, err := runProcessWait(t, mock, "proc-1", "--wait-timeout", "20ms")
+	if err == nil {
+		t.Fatal("expected timeout")
+	}
+	if resp["code"] != "WAIT_TIMEOUT" {
+		t.Errorf("code = %v, want WAIT_TIMEOUT", resp["code"])
+	}
+	last := resp["context"].(map[string]interface{})["process"].(map[string]interface{})
+	if last["status"] != "RUNNING" {
+		t.Errorf("last status = %v, want RUNNING", last["status"])
+	}
+}
+
+func TestProcessCmd_Wait_NotFound(t *testing.T) {
+	resp, err := runProcessWait(t, platform.NewMock(), "nonexistent")
+	if err == nil || resp["code"] != "PROCESS_NOT_FOUND" {
+		t.Errorf("err = %v, code = %v, want PROCESS_NOT_FOUND", err, resp["code"])
+	}
+}
+
+func runProcessBatch(t *testing.T, mock *platform.Mock, stdin string, args ...string) (map[string]interface{}, error) {
+	t.Helper()
+	storagePath := setupAuthenticatedStorage(t)
+	cmd := NewRootForTest(RootDeps{StoragePath: storagePath, Client: mock})
+	cmd.SetArgs(append([]string{"process"}, args...))
+	cmd.SetIn(strings.NewReader(stdin))
+
+	var stdout bytes.Buffer
+	output.SetWriter(&stdout)
+	defer output.ResetWriter()
+
+	err := cmd.Execute()
+	var resp map[string]interface{}
+	_ = json.Unmarshal(stdout.Bytes(), &resp)
+	return resp, err
+}
+
+func batchMock() *platform.Mock {
+	return platform.NewMock().
+		WithProcess(&platform.Process{ID: "p1", ActionName: "start", Status: "DONE",
+			ServiceStacks: []platform.ServiceStackRef{{ID: "s1", Name: "api"}}}).
+		WithProcess(&platform.Process{ID: "p2", ActionName: "start", Status: "RUNNING"}).
+		WithProcess(&platform.Process{ID: "p3", ActionName: "start", Status: "RUNNING"})
+}
+
+func TestProcessCmd_Batch(t *testing.T) {
+	resp, err := runProcessBatch(t, batchMock(), "", "p1", "p2", "p3", "missing", "p1", "--concurrency", "2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	data := resp["data"].(map[string]interface{})
+	procs := data["processes"].([]interface{})
+	if len(procs) != 4 || data["total"] != float64(4) {
+		t.Fatalf("processes = %v, want 4 (duplicate p1 dropped)", procs)
+	}
+	first := procs[0].(map[string]interface{})
+	if first["processId"] != "p1" || first["status"] != "FINISHED" || first["serviceHostname"] != "api" {
+		t.Errorf("first = %v, want p1 FINISHED on api", first)
+	}
+	missing := procs[3].(map[string]interface{})
+	if missing["status"] != "UNKNOWN" || missing["error"] == nil {
+		t.Errorf("missing = %v, want UNKNOWN with error", missing)
+	}
+	counts := data["counts"].(map[string]interface{})
+	if counts["FINISHED"] != float64(1) || counts["RUNNING"] != float64(2) || counts["UNKNOWN"] != float64(1) {
+		t.Errorf("counts = %v", counts)
+	}
+}
+
+func TestProcessCmd_Batch_NoneFound(t *testing.T) {
+	resp, err := runProcessBatch(t, platform.NewMock(), "", "x", "y")
+	if err == nil {
+		t.Fatal("expected error")
+	}
+	if resp["code"] != "PROCESS_NOT_FOUND" {
+		t.Errorf("code = %v, want PROCESS_NOT_FOUND", resp["code"])
+	}
+}
+
+func TestProcessCmd_FromStdin(t *testing.T) {
+	envelope := `{"type":"async","status":"initiated","processes":[` +
+		`{"processId":"p2","actionName":"import","serviceHostname":"db","status":"PENDING"},` +
+		`{"processId":"p3","actionName":"import","serviceHostname":"cache","status":"PENDING"}]}`
+
+	resp, err := runProcessBatch(t, batchMock(), envelope, "p1", "--from-stdin")
+	if err != nil {
+		t.Fatal(err)
+	}
+	procs := resp["data"].(map[string]interface{})["processes"].([]interface{})
+	if len(procs) != 3 {
+		t.Fatalf("processes len = %d, want 3", len(procs))
+	}
+	// The envelope's hostname and action name are kept over the API's.
+	p2 := procs[1].(map[string]interface{})
+	if p2["serviceHostname"] != "db" || p2["actionName"] != "import" || p2["status"] != "RUNNING" {
+		t.Errorf("p2 = %v, want import on db, RUNNING", p2)
+	}
+}
+
+func TestProcessCmd_FromStdin_Invalid(t *testing.T) {
+	tests := []struct {
+		name  string
+		stdin string
+	}{
+		{"not_json", "services:\n"},
+		{"error_envelope", `{"type":"error","code":"AUTH_REQUIRED","error":"x"}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := runProcessBatch(t, batchMock(), tt.stdin, "--from-stdin")
+			if err == nil {
+				t.Fatal("expected error")
+			}
+			if resp["code"] != "INVALID_PARAMETER" {
+				t.Errorf("code = %v, want INVALID_PARAMETER", resp["code"])
+			}
+		})
+	}
+}
+
+func TestProcessCmd_NoArgs(t *testing.T) {
+	resp, err := runProcessBatch(t, batchMock(), "")
+	if err == nil {
+		t.Fatal("expected error")
+	}
+	if resp["code"] != "INVALID_USAGE" {
+		t.Errorf("code = %v, want INVALID_USAGE", resp["code"])
+	}
+}
+
+func TestProcessCmd_Batch_Wait(t *testing.T) {
+	mock := batchMock().WithProcessProgress("p2", "RUNNING", "FINISHED")
+
+	resp, err := runProcessBatch(t, mock, "", "p1", "p2", "--wait", "--interval", "1ms")
+	if err != nil {
+		t.Fatal(err)
+	}
+	data := resp["data"].(map[string]interface{})
+	if counts := data["counts"].(map[string]interface{}); counts["FINISHED"] != float64(2) {
+		t.Errorf("counts = %v, want 2 FINISHED", counts)
+	}
+}
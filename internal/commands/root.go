@@ -71,8 +71,11 @@ func NewRootForTest(deps RootDeps) *cobra.Command {
 	rootCmd.AddCommand(NewProcess(storagePath, client))
 	rootCmd.AddCommand(NewCancel(storagePath, client))
 	rootCmd.AddCommand(NewLogs(storagePath, client, fetcher))
-	rootCmd.AddCommand(NewValidate())
-	rootCmd.AddCommand(NewSearch())
+	rootCmd.AddCommand(NewValidate(storagePath, client))
+	rootCmd.AddCommand(NewSearch(storagePath, client))
+	rootCmd.AddCommand(NewKnowledge())
+	rootCmd.AddCommand(NewCatalog())
+	rootCmd.AddCommand(NewSchema())
 	rootCmd.AddCommand(NewStart(storagePath, client))
 	rootCmd.AddCommand(NewStop(storagePath, client))
 	rootCmd.AddCommand(NewRestart(storagePath, client))
@@ -82,6 +85,7 @@ func NewRootForTest(deps RootDeps) *cobra.Command {
 	rootCmd.AddCommand(NewDelete(storagePath, client))
 	rootCmd.AddCommand(NewSubdomain(storagePath, client))
 	rootCmd.AddCommand(NewEvents(storagePath, client))
+	rootCmd.AddCommand(NewHistory(storagePath, client))
 	rootCmd.AddCommand(NewSetup())
 
 	return rootCmd
@@ -124,8 +128,11 @@ func NewRoot() *cobra.Command {
 	rootCmd.AddCommand(NewProcess(storagePath, client))
 	rootCmd.AddCommand(NewCancel(storagePath, client))
 	rootCmd.AddCommand(NewLogs(storagePath, client, fetcher))
-	rootCmd.AddCommand(NewValidate())
-	rootCmd.AddCommand(NewSearch())
+	rootCmd.AddCommand(NewValidate(storagePath, client))
+	rootCmd.AddCommand(NewSearch(storagePath, client))
+	rootCmd.AddCommand(NewKnowledge())
+	rootCmd.AddCommand(NewCatalog())
+	rootCmd.AddCommand(NewSchema())
 	rootCmd.AddCommand(NewStart(storagePath, client))
 	rootCmd.AddCommand(NewStop(storagePath, client))
 	rootCmd.AddCommand(NewRestart(storagePath, client))
@@ -135,6 +142,7 @@ func NewRoot() *cobra.Command {
 	rootCmd.AddCommand(NewDelete(storagePath, client))
 	rootCmd.AddCommand(NewSubdomain(storagePath, client))
 	rootCmd.AddCommand(NewEvents(storagePath, client))
+	rootCmd.AddCommand(NewHistory(storagePath, client))
 	rootCmd.AddCommand(NewSetup())
 
 	return rootCmd
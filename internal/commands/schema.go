@@ -0,0 +1,45 @@
+package commands
+
+import (
+	"github.com/spf13/cobra"
+	"github.com/zeropsio/zaia/internal/output"
+	"github.com/zeropsio/zaia/internal/platform"
+	"github.com/zeropsio/zaia/internal/validation"
+)
+
+// NewSchema creates the schema command.
+// It prints the JSON Schema generated from the spec used by validate, without an envelope,
+// so the output can be saved and referenced from editors directly.
+func NewSchema() *cobra.Command {
+	return &cobra.Command{
+		Use:   "schema <zerops.yml|import.yml>",
+		Short: "Print the JSON Schema of zerops.yml or import.yml",
+		Args:  cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) == 0 {
+				return output.Err(platform.ErrInvalidUsage,
+					"No file type specified for 'schema'",
+					"Run: zaia schema zerops.yml or zaia schema import.yml",
+					map[string]interface{}{"availableTypes": []string{fileTypeZeropsYml, fileTypeImportYml}})
+			}
+
+			var spec *validation.Spec
+			switch args[0] {
+			case fileTypeZeropsYml:
+				spec = validation.ZeropsYmlSpec
+			case fileTypeImportYml:
+				spec = validation.ImportYmlSpec
+			default:
+				return output.Err(platform.ErrUnknownType,
+					"Unknown file type: "+args[0],
+					"Run: zaia schema zerops.yml or zaia schema import.yml", nil)
+			}
+
+			data, err := validation.JSONSchema(spec, args[0])
+			if err != nil {
+				return output.Err(platform.ErrInvalidUsage, "Cannot build schema: "+err.Error(), "", nil)
+			}
+			return output.Raw(data)
+		},
+	}
+}
@@ -0,0 +1,52 @@
+package commands
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/zeropsio/zaia/internal/output"
+)
+
+func TestSchema_ZeropsYml(t *testing.T) {
+	cmd := NewSchema()
+	cmd.SetArgs([]string{"zerops.yml"})
+
+	var stdout bytes.Buffer
+	output.SetWriter(&stdout)
+	defer output.ResetWriter()
+
+	if err := cmd.Execute(); err != nil {
+		t.Fatal(err)
+	}
+	var schema map[string]interface{}
+	if err := json.Unmarshal(stdout.Bytes(), &schema); err != nil {
+		t.Fatalf("output is not JSON: %v", err)
+	}
+	if schema["title"] != "zerops.yml" || schema["type"] != "object" {
+		t.Errorf("schema = %v", schema)
+	}
+	if _, ok := schema["status"]; ok {
+		t.Error("schema must be printed without the response envelope")
+	}
+}
+
+func TestSchema_UnknownType(t *testing.T) {
+	cmd := NewSchema()
+	cmd.SetArgs([]string{"docker-compose.yml"})
+
+	var stdout bytes.Buffer
+	output.SetWriter(&stdout)
+	defer output.ResetWriter()
+
+	if err := cmd.Execute(); err == nil {
+		t.Fatal("expected error for unknown file type")
+	}
+	var resp map[string]interface{}
+	if err := json.Unmarshal(stdout.Bytes(), &resp); err != nil {
+		t.Fatal(err)
+	}
+	if resp["code"] != "UNKNOWN_TYPE" {
+		t.Errorf("code = %v, want UNKNOWN_TYPE", resp["code"])
+	}
+}
@@ -2,34 +2,78 @@ package commands
 
 import (
 	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
 	"strings"
 
 	"github.com/spf13/cobra"
+	"github.com/zeropsio/zaia/internal/catalog"
 	"github.com/zeropsio/zaia/internal/knowledge"
 	"github.com/zeropsio/zaia/internal/output"
+	"github.com/zeropsio/zaia/internal/platform"
+	"github.com/zeropsio/zaia/internal/validation"
 )
 
-// NewSearch creates the search command for BM25 knowledge search.
-func NewSearch() *cobra.Command {
+const (
+	// knowledgePathEnv lists extra knowledge directories, separated like PATH.
+	knowledgePathEnv = "ZAIA_KNOWLEDGE_PATH"
+	// cacheDirEnv overrides the cache directory holding the search index.
+	cacheDirEnv = "ZAIA_CACHE_DIR"
+	// embeddingModelEnv points at a GloVe/word2vec text file replacing the built-in
+	// embedding model of --mode hybrid and vector.
+	embeddingModelEnv = "ZAIA_EMBEDDING_MODEL"
+)
+
+// NewSearch creates the search command for BM25, vector and hybrid knowledge search.
+// The client is only used by --project-context.
+func NewSearch(storagePath string, client platform.Client) *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "search [query...]",
 		Short: "Search knowledge base",
 		Args:  cobra.ArbitraryArgs,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			getURI, _ := cmd.Flags().GetString("get")
+			modeFlag, _ := cmd.Flags().GetString("mode")
+
+			mode := knowledge.Mode(modeFlag)
+			if !slices.Contains(knowledge.Modes, mode) {
+				return output.Err(platform.ErrInvalidParameter,
+					fmt.Sprintf("Unknown mode: %s", modeFlag),
+					"Use --mode hybrid, bm25 or vector", nil)
+			}
+
+			serviceType, _ := cmd.Flags().GetString("service-type")
+			if serviceType != "" {
+				name, _ := catalog.Split(strings.ToLower(serviceType))
+				if _, ok := catalog.Lookup(name); !ok {
+					suggestion := "Run: zaia catalog list"
+					if s := validation.Closest(name, catalog.Names("")); s != "" {
+						suggestion = "Did you mean '" + s + "'?"
+					}
+					return output.Err(platform.ErrServiceTypeNotFound,
+						"Unknown service type: "+serviceType, suggestion, nil)
+				}
+			}
 
-			// --get mode: direct document lookup by URI
+			store, err := knowledgeStore(cmd)
+			if err != nil {
+				return err
+			}
+			defer store.Close()
+
+			category, _ := cmd.Flags().GetString("category")
+			if err := checkCategory(store, category); err != nil {
+				return err
+			}
+
+			// --get mode: direct lookup by URI, a #section fragment returns just that section
 			if getURI != "" {
-				store := knowledge.GetEmbeddedStore()
-				doc, err := store.Get(getURI)
+				data, err := documentContent(store, getURI)
 				if err != nil {
 					return output.Err("NOT_FOUND", "Document not found", "", nil)
 				}
-				return output.Sync(map[string]interface{}{
-					"uri":     doc.URI,
-					"title":   doc.Title,
-					"content": doc.Content,
-				})
+				return output.Sync(data)
 			}
 
 			if len(args) == 0 {
@@ -39,47 +83,224 @@ func NewSearch() *cobra.Command {
 			query := strings.Join(args, " ")
 			limit, _ := cmd.Flags().GetInt("limit")
 
-			store := knowledge.GetEmbeddedStore()
-			results := store.Search(query, limit)
-			suggestions := store.GenerateSuggestions(query, results)
+			if mode != knowledge.ModeBM25 {
+				if err := loadEmbeddingModel(store); err != nil {
+					return err
+				}
+			}
 
-			expandedQuery := knowledge.ExpandQuery(query)
+			opts := knowledge.SearchOptions{Limit: limit, Mode: mode, Category: category, ServiceType: serviceType}
+
+			// --project-context: boost docs about the service types the project runs
+			projectContext, _ := cmd.Flags().GetBool("project-context")
+			var services []platform.ServiceStack
+			hostnamesByType := make(map[string][]string)
+			if projectContext {
+				creds, err := resolveCredentials(storagePath)
+				if err != nil {
+					return err
+				}
+				services, err = client.ListServices(cmd.Context(), creds.ProjectID)
+				if err != nil {
+					return output.Err(platform.ErrAPIError, err.Error(), "", nil)
+				}
+				for _, svc := range services {
+					t := svc.ServiceStackTypeInfo.ServiceStackTypeVersionName
+					if _, seen := hostnamesByType[t]; !seen {
+						opts.Boost = append(opts.Boost, t)
+					}
+					hostnamesByType[t] = append(hostnamesByType[t], svc.Name)
+				}
+			}
+			results := store.SearchWith(query, opts)
+
+			// Misspelled words: suggest a correction, and search with it when nothing matched
+			searched := query
+			didYouMean := store.DidYouMean(query)
+			if len(results) == 0 && didYouMean != "" {
+				if corrected := store.SearchWith(didYouMean, opts); len(corrected) > 0 {
+					results, searched = corrected, didYouMean
+				}
+			}
+
+			suggestions := store.GenerateSuggestions(searched, results)
+			expandedQuery := knowledge.ExpandQuery(searched)
 
 			// Build results list
 			resultList := make([]interface{}, len(results))
+			var boosted []string
 			for i, r := range results {
-				resultList[i] = map[string]interface{}{
+				entry := map[string]interface{}{
 					"uri":     r.URI,
 					"title":   r.Title,
 					"score":   r.Score,
 					"snippet": r.Snippet,
 				}
+				if r.Section != "" {
+					entry["section"] = r.Section
+				}
+				if len(r.BoostedBy) > 0 {
+					var hostnames []string
+					for _, t := range r.BoostedBy {
+						hostnames = append(hostnames, hostnamesByType[t]...)
+					}
+					entry["boostedBy"] = hostnames
+					boosted = append(boosted, r.BoostedBy...)
+				}
+				resultList[i] = entry
 			}
 
-			// Build topResult (full content of #1 if score >= 1.0)
+			// Build topResult (content of #1's best section if its score is confident for the mode)
 			var topResult interface{}
-			if len(results) > 0 && results[0].Score >= 1.0 {
-				doc, err := store.Get(results[0].URI)
-				if err == nil {
-					topResult = map[string]interface{}{
-						"uri":     doc.URI,
-						"title":   doc.Title,
-						"content": doc.Content,
-					}
+			if len(results) > 0 && results[0].Score >= knowledge.TopResultThreshold(mode) {
+				if data, err := documentContent(store, results[0].URI); err == nil {
+					topResult = data
 				}
 			}
 
-			return output.Sync(map[string]interface{}{
+			data := map[string]interface{}{
 				"query":         query,
 				"expandedQuery": expandedQuery,
+				"mode":          mode,
 				"results":       resultList,
 				"topResult":     topResult,
 				"suggestions":   suggestions,
-			})
+			}
+			if category != "" || serviceType != "" {
+				filters := map[string]interface{}{}
+				if category != "" {
+					filters["category"] = category
+				}
+				if serviceType != "" {
+					filters["serviceType"] = serviceType
+				}
+				data["filters"] = filters
+			}
+			if projectContext {
+				data["projectContext"] = projectContextData(services, boosted)
+			}
+			if didYouMean != "" {
+				data["didYouMean"] = didYouMean
+				data["autoCorrected"] = searched == didYouMean
+			}
+			return output.Sync(data)
 		},
 	}
 
 	cmd.Flags().String("get", "", "Get document by URI")
 	cmd.Flags().Int("limit", 5, "Max results (1-20)")
+	cmd.Flags().String("mode", string(knowledge.ModeBM25), "Ranking: bm25 (keywords), vector (meaning) or hybrid (both)")
+	cmd.Flags().String("category", "", "Only documents of this category: services, decisions, config, ...")
+	cmd.Flags().String("service-type", "", "Only documents about this service type (postgresql, or postgresql@16 to respect minimum versions)")
+	cmd.Flags().Bool("project-context", false, "Rank docs about the current project's service types higher (needs login)")
+	cmd.Flags().StringArray("knowledge-dir", nil, "Extra markdown knowledge directory, indexed as team:// (repeatable)")
 	return cmd
 }
+
+// projectContextData lists the project's services and, as boostedBy, those whose type
+// raised at least one of the results.
+func projectContextData(services []platform.ServiceStack, boostedTypes []string) map[string]interface{} {
+	serviceList := make([]map[string]interface{}, 0, len(services))
+	drivers := make([]map[string]interface{}, 0)
+	for _, svc := range services {
+		entry := map[string]interface{}{
+			"hostname": svc.Name,
+			"type":     svc.ServiceStackTypeInfo.ServiceStackTypeVersionName,
+		}
+		serviceList = append(serviceList, entry)
+		if slices.Contains(boostedTypes, svc.ServiceStackTypeInfo.ServiceStackTypeVersionName) {
+			drivers = append(drivers, entry)
+		}
+	}
+	return map[string]interface{}{
+		"services":  serviceList,
+		"boostedBy": drivers,
+	}
+}
+
+// documentContent returns the document at uri, or only its section when uri has a #fragment.
+func documentContent(store *knowledge.Store, uri string) (map[string]interface{}, error) {
+	if _, anchor := knowledge.SplitFragment(uri); anchor != "" {
+		doc, sec, err := store.GetSection(uri)
+		if err != nil {
+			return nil, err
+		}
+		return map[string]interface{}{
+			"uri":      doc.URI + "#" + sec.Anchor,
+			"title":    doc.Title,
+			"section":  sec.Heading,
+			"content":  sec.Content,
+			"document": doc.URI,
+		}, nil
+	}
+
+	doc, err := store.Get(uri)
+	if err != nil {
+		return nil, err
+	}
+	data := map[string]interface{}{
+		"uri":     doc.URI,
+		"title":   doc.Title,
+		"content": doc.Content,
+	}
+	if doc.Overrides != "" {
+		data["overrides"] = doc.Overrides
+	}
+	return data, nil
+}
+
+// knowledgeStore opens the embedded docs, extended with ZAIA_KNOWLEDGE_PATH and --knowledge-dir
+// directories, on the persistent index in the cache dir. Flag directories come last, so they
+// override the env ones. The caller closes the store.
+func knowledgeStore(cmd *cobra.Command) (*knowledge.Store, error) {
+	store, err := knowledge.OpenStore(knowledgeDirs(cmd), knowledgeCacheDir())
+	if err != nil {
+		return nil, output.Err(platform.ErrFileNotFound,
+			"Cannot load knowledge: "+err.Error(),
+			"Check "+knowledgePathEnv+" and --knowledge-dir point to readable directories", nil)
+	}
+	return store, nil
+}
+
+// knowledgeDirs returns the ZAIA_KNOWLEDGE_PATH directories followed by the --knowledge-dir ones.
+func knowledgeDirs(cmd *cobra.Command) []string {
+	dirs := filepath.SplitList(os.Getenv(knowledgePathEnv))
+	flagDirs, _ := cmd.Flags().GetStringArray("knowledge-dir")
+	dirs = append(dirs, flagDirs...)
+
+	nonEmpty := dirs[:0]
+	for _, d := range dirs {
+		if d != "" {
+			nonEmpty = append(nonEmpty, d)
+		}
+	}
+	return nonEmpty
+}
+
+// loadEmbeddingModel switches store to the word vectors named by ZAIA_EMBEDDING_MODEL, if set.
+func loadEmbeddingModel(store *knowledge.Store) error {
+	path := os.Getenv(embeddingModelEnv)
+	if path == "" {
+		return nil
+	}
+	model, err := knowledge.LoadWordVectors(path)
+	if err != nil {
+		return output.Err(platform.ErrFileNotFound,
+			"Cannot load embedding model: "+err.Error(),
+			"Point "+embeddingModelEnv+" to a GloVe or word2vec text file, or unset it to use the built-in model", nil)
+	}
+	store.SetWordModel(model)
+	return nil
+}
+
+// knowledgeCacheDir returns where the search index is kept, or "" to index in memory only.
+func knowledgeCacheDir() string {
+	if dir := os.Getenv(cacheDirEnv); dir != "" {
+		return dir
+	}
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "zaia")
+}
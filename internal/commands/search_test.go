@@ -3,13 +3,17 @@ package commands
 import (
 	"bytes"
 	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/zeropsio/zaia/internal/output"
+	"github.com/zeropsio/zaia/internal/platform"
 )
 
 func TestSearchCmd_ReturnsResults(t *testing.T) {
-	cmd := NewSearch()
+	cmd := NewSearch("", nil)
 	cmd.SetArgs([]string{"postgresql", "connection", "string"})
 
 	var stdout bytes.Buffer
@@ -40,7 +44,7 @@ func TestSearchCmd_ReturnsResults(t *testing.T) {
 }
 
 func TestSearchCmd_NoArgs_Error(t *testing.T) {
-	cmd := NewSearch()
+	cmd := NewSearch("", nil)
 	cmd.SetArgs([]string{})
 
 	err := cmd.Execute()
@@ -50,7 +54,7 @@ func TestSearchCmd_NoArgs_Error(t *testing.T) {
 }
 
 func TestSearch_Get_Found(t *testing.T) {
-	cmd := NewSearch()
+	cmd := NewSearch("", nil)
 	cmd.SetArgs([]string{"--get", "zerops://docs/services/postgresql"})
 
 	var stdout bytes.Buffer
@@ -80,7 +84,7 @@ func TestSearch_Get_Found(t *testing.T) {
 }
 
 func TestSearch_Get_NotFound(t *testing.T) {
-	cmd := NewSearch()
+	cmd := NewSearch("", nil)
 	cmd.SetArgs([]string{"--get", "zerops://docs/nonexistent"})
 
 	var stdout bytes.Buffer
@@ -103,7 +107,7 @@ func TestSearch_Get_NotFound(t *testing.T) {
 }
 
 func TestSearchCmd_UnsupportedService(t *testing.T) {
-	cmd := NewSearch()
+	cmd := NewSearch("", nil)
 	cmd.SetArgs([]string{"mongodb"})
 
 	var stdout bytes.Buffer
@@ -123,3 +127,338 @@ func TestSearchCmd_UnsupportedService(t *testing.T) {
 		t.Error("expected suggestions for unsupported 'mongodb'")
 	}
 }
+
+func TestSearch_KnowledgeDir(t *testing.T) {
+	dir := t.TempDir()
+	doc := "# Deploy Freeze\n\n## Keywords\nfreeze, friday\n\n## TL;DR\nNo production deploys on Fridays.\n"
+	if err := os.WriteFile(filepath.Join(dir, "freeze.md"), []byte(doc), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv(knowledgePathEnv, "")
+
+	cmd := NewSearch("", nil)
+	cmd.SetArgs([]string{"friday", "freeze", "--knowledge-dir", dir})
+
+	var stdout bytes.Buffer
+	output.SetWriter(&stdout)
+	defer output.ResetWriter()
+
+	if err := cmd.Execute(); err != nil {
+		t.Fatal(err)
+	}
+	var resp map[string]interface{}
+	_ = json.Unmarshal(stdout.Bytes(), &resp)
+	results := resp["data"].(map[string]interface{})["results"].([]interface{})
+	if len(results) == 0 || results[0].(map[string]interface{})["uri"] != "team://freeze" {
+		t.Errorf("results = %v, want team://freeze first", results)
+	}
+}
+
+func TestSearch_KnowledgePathMissing(t *testing.T) {
+	t.Setenv(knowledgePathEnv, filepath.Join(t.TempDir(), "missing"))
+
+	cmd := NewSearch("", nil)
+	cmd.SetArgs([]string{"postgresql"})
+
+	var stdout bytes.Buffer
+	output.SetWriter(&stdout)
+	defer output.ResetWriter()
+
+	if err := cmd.Execute(); err == nil {
+		t.Fatal("expected error for missing knowledge directory")
+	}
+	var resp map[string]interface{}
+	_ = json.Unmarshal(stdout.Bytes(), &resp)
+	if resp["code"] != "FILE_NOT_FOUND" {
+		t.Errorf("code = %v, want FILE_NOT_FOUND", resp["code"])
+	}
+}
+
+func TestSearch_GetSection(t *testing.T) {
+	cmd := NewSearch("", nil)
+	cmd.SetArgs([]string{"--get", "zerops://docs/services/postgresql#tldr"})
+
+	var stdout bytes.Buffer
+	output.SetWriter(&stdout)
+	defer output.ResetWriter()
+
+	if err := cmd.Execute(); err != nil {
+		t.Fatal(err)
+	}
+	var resp map[string]interface{}
+	_ = json.Unmarshal(stdout.Bytes(), &resp)
+	data := resp["data"].(map[string]interface{})
+	if data["section"] != "TL;DR" || data["document"] != "zerops://docs/services/postgresql" {
+		t.Errorf("data = %v", data)
+	}
+	content, _ := data["content"].(string)
+	if !strings.HasPrefix(content, "## TL;DR") || strings.Contains(content, "## Keywords") {
+		t.Errorf("content = %q, want only the TL;DR section", content)
+	}
+}
+
+func TestSearchCmd_TopResultIsSection(t *testing.T) {
+	cmd := NewSearch("", nil)
+	cmd.SetArgs([]string{"postgresql", "connection", "string"})
+
+	var stdout bytes.Buffer
+	output.SetWriter(&stdout)
+	defer output.ResetWriter()
+
+	if err := cmd.Execute(); err != nil {
+		t.Fatal(err)
+	}
+	var resp map[string]interface{}
+	_ = json.Unmarshal(stdout.Bytes(), &resp)
+	data := resp["data"].(map[string]interface{})
+	top, ok := data["topResult"].(map[string]interface{})
+	if !ok {
+		t.Fatal("expected topResult")
+	}
+	uri, _ := top["uri"].(string)
+	if !strings.Contains(uri, "#") || top["section"] == nil {
+		t.Errorf("topResult = %v, want a section", top)
+	}
+	results := data["results"].([]interface{})
+	if results[0].(map[string]interface{})["uri"] != uri {
+		t.Errorf("topResult uri %s differs from first result", uri)
+	}
+}
+
+func TestSearchCmd_HybridMode(t *testing.T) {
+	cmd := NewSearch("", nil)
+	cmd.SetArgs([]string{"--mode", "hybrid", "send", "emails", "from", "my", "app"})
+
+	var stdout bytes.Buffer
+	output.SetWriter(&stdout)
+	defer output.ResetWriter()
+
+	if err := cmd.Execute(); err != nil {
+		t.Fatal(err)
+	}
+	var resp map[string]interface{}
+	_ = json.Unmarshal(stdout.Bytes(), &resp)
+	data := resp["data"].(map[string]interface{})
+	if data["mode"] != "hybrid" {
+		t.Errorf("mode = %v, want hybrid", data["mode"])
+	}
+	results := data["results"].([]interface{})
+	if len(results) == 0 {
+		t.Fatal("expected results")
+	}
+	uri, _ := results[0].(map[string]interface{})["uri"].(string)
+	if !strings.HasPrefix(uri, "zerops://docs/operations/smtp") {
+		t.Errorf("first result = %s, want operations/smtp", uri)
+	}
+}
+
+func TestSearchCmd_UnknownMode(t *testing.T) {
+	cmd := NewSearch("", nil)
+	cmd.SetArgs([]string{"--mode", "semantic", "postgresql"})
+
+	var stdout bytes.Buffer
+	output.SetWriter(&stdout)
+	defer output.ResetWriter()
+
+	if err := cmd.Execute(); err == nil {
+		t.Fatal("expected error for unknown mode")
+	}
+	var resp map[string]interface{}
+	_ = json.Unmarshal(stdout.Bytes(), &resp)
+	if resp["code"] != "INVALID_PARAMETER" {
+		t.Errorf("code = %v, want INVALID_PARAMETER", resp["code"])
+	}
+}
+
+func TestSearchCmd_EmbeddingModelMissing(t *testing.T) {
+	t.Setenv(embeddingModelEnv, filepath.Join(t.TempDir(), "missing.vec"))
+
+	cmd := NewSearch("", nil)
+	cmd.SetArgs([]string{"--mode", "vector", "postgresql"})
+
+	var stdout bytes.Buffer
+	output.SetWriter(&stdout)
+	defer output.ResetWriter()
+
+	if err := cmd.Execute(); err == nil {
+		t.Fatal("expected error for missing embedding model")
+	}
+	var resp map[string]interface{}
+	_ = json.Unmarshal(stdout.Bytes(), &resp)
+	if resp["code"] != "FILE_NOT_FOUND" {
+		t.Errorf("code = %v, want FILE_NOT_FOUND", resp["code"])
+	}
+}
+
+func TestSearchCmd_DidYouMean_AutoCorrects(t *testing.T) {
+	cmd := NewSearch("", nil)
+	cmd.SetArgs([]string{"postgress", "conection"})
+
+	var stdout bytes.Buffer
+	output.SetWriter(&stdout)
+	defer output.ResetWriter()
+
+	if err := cmd.Execute(); err != nil {
+		t.Fatal(err)
+	}
+	var resp map[string]interface{}
+	_ = json.Unmarshal(stdout.Bytes(), &resp)
+	data := resp["data"].(map[string]interface{})
+	if data["didYouMean"] != "postgres connection" {
+		t.Errorf("didYouMean = %v, want 'postgres connection'", data["didYouMean"])
+	}
+	if data["autoCorrected"] != true {
+		t.Errorf("autoCorrected = %v, want true", data["autoCorrected"])
+	}
+	if data["query"] != "postgress conection" {
+		t.Errorf("query = %v, want the original query", data["query"])
+	}
+	results := data["results"].([]interface{})
+	if len(results) == 0 {
+		t.Fatal("expected results for the corrected query")
+	}
+	uri, _ := results[0].(map[string]interface{})["uri"].(string)
+	if !strings.HasPrefix(uri, "zerops://docs/services/postgresql") {
+		t.Errorf("first result = %s, want services/postgresql", uri)
+	}
+}
+
+func TestSearchCmd_DidYouMean_OmittedForKnownWords(t *testing.T) {
+	cmd := NewSearch("", nil)
+	cmd.SetArgs([]string{"postgresql", "connection", "string"})
+
+	var stdout bytes.Buffer
+	output.SetWriter(&stdout)
+	defer output.ResetWriter()
+
+	if err := cmd.Execute(); err != nil {
+		t.Fatal(err)
+	}
+	var resp map[string]interface{}
+	_ = json.Unmarshal(stdout.Bytes(), &resp)
+	data := resp["data"].(map[string]interface{})
+	if _, ok := data["didYouMean"]; ok {
+		t.Errorf("didYouMean = %v, want omitted", data["didYouMean"])
+	}
+}
+
+func TestSearchCmd_Filters(t *testing.T) {
+	cmd := NewSearch("", nil)
+	cmd.SetArgs([]string{"--category", "decisions", "--service-type", "postgresql", "which", "database"})
+
+	var stdout bytes.Buffer
+	output.SetWriter(&stdout)
+	defer output.ResetWriter()
+
+	if err := cmd.Execute(); err != nil {
+		t.Fatal(err)
+	}
+	var resp map[string]interface{}
+	_ = json.Unmarshal(stdout.Bytes(), &resp)
+	data := resp["data"].(map[string]interface{})
+	results := data["results"].([]interface{})
+	if len(results) != 1 {
+		t.Fatalf("results = %v, want only choose-database", results)
+	}
+	uri := results[0].(map[string]interface{})["uri"].(string)
+	if !strings.HasPrefix(uri, "zerops://docs/decisions/choose-database") {
+		t.Errorf("uri = %s, want choose-database", uri)
+	}
+	filters := data["filters"].(map[string]interface{})
+	if filters["category"] != "decisions" || filters["serviceType"] != "postgresql" {
+		t.Errorf("filters = %v", filters)
+	}
+}
+
+func TestSearchCmd_UnknownFilters(t *testing.T) {
+	tests := []struct {
+		args []string
+		code string
+	}{
+		{[]string{"--category", "recipes", "postgresql"}, "INVALID_PARAMETER"},
+		{[]string{"--service-type", "postgres", "connection"}, "SERVICE_TYPE_NOT_FOUND"},
+	}
+	for _, tt := range tests {
+		cmd := NewSearch("", nil)
+		cmd.SetArgs(tt.args)
+
+		var stdout bytes.Buffer
+		output.SetWriter(&stdout)
+
+		if err := cmd.Execute(); err == nil {
+			t.Errorf("%v: expected error", tt.args)
+		}
+		var resp map[string]interface{}
+		_ = json.Unmarshal(stdout.Bytes(), &resp)
+		if resp["code"] != tt.code {
+			t.Errorf("%v: code = %v, want %s", tt.args, resp["code"], tt.code)
+		}
+		output.ResetWriter()
+	}
+}
+
+func TestSearchCmd_ProjectContext(t *testing.T) {
+	storagePath := setupAuthenticatedStorage(t)
+	mock := platform.NewMock().WithServices([]platform.ServiceStack{
+		{ID: "s1", Name: "api", ServiceStackTypeInfo: platform.ServiceTypeInfo{ServiceStackTypeVersionName: "nodejs@22"}},
+		{ID: "s2", Name: "db", ServiceStackTypeInfo: platform.ServiceTypeInfo{ServiceStackTypeVersionName: "postgresql@16"}},
+	})
+
+	cmd := NewSearch(storagePath, mock)
+	cmd.SetArgs([]string{"--project-context", "connection", "string"})
+
+	var stdout bytes.Buffer
+	output.SetWriter(&stdout)
+	defer output.ResetWriter()
+
+	if err := cmd.Execute(); err != nil {
+		t.Fatal(err)
+	}
+	var resp map[string]interface{}
+	_ = json.Unmarshal(stdout.Bytes(), &resp)
+	data := resp["data"].(map[string]interface{})
+
+	results := data["results"].([]interface{})
+	boostedPostgres := false
+	for _, r := range results {
+		entry := r.(map[string]interface{})
+		if strings.HasPrefix(entry["uri"].(string), "zerops://docs/services/postgresql") {
+			hostnames, _ := entry["boostedBy"].([]interface{})
+			boostedPostgres = len(hostnames) == 1 && hostnames[0] == "db"
+		}
+	}
+	if !boostedPostgres {
+		t.Errorf("expected postgresql doc boosted by db, got %v", results)
+	}
+
+	ctx := data["projectContext"].(map[string]interface{})
+	if len(ctx["services"].([]interface{})) != 2 {
+		t.Errorf("services = %v, want api and db", ctx["services"])
+	}
+	drivers := ctx["boostedBy"].([]interface{})
+	dbDrove := false
+	for _, d := range drivers {
+		dbDrove = dbDrove || d.(map[string]interface{})["hostname"] == "db"
+	}
+	if !dbDrove {
+		t.Errorf("boostedBy = %v, want db", drivers)
+	}
+}
+
+func TestSearchCmd_ProjectContext_NotAuthenticated(t *testing.T) {
+	cmd := NewSearch(filepath.Join(t.TempDir(), "zaia.data"), platform.NewMock())
+	cmd.SetArgs([]string{"--project-context", "postgresql"})
+
+	var stdout bytes.Buffer
+	output.SetWriter(&stdout)
+	defer output.ResetWriter()
+
+	if err := cmd.Execute(); err == nil {
+		t.Fatal("expected error without login")
+	}
+	var resp map[string]interface{}
+	_ = json.Unmarshal(stdout.Bytes(), &resp)
+	if resp["code"] != "AUTH_REQUIRED" {
+		t.Errorf("code = %v, want AUTH_REQUIRED", resp["code"])
+	}
+}
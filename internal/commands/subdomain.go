@@ -93,11 +93,12 @@ func newSubdomainAction(storagePath string, client platform.Client, action strin
 			proc := output.MapProcessToOutput(process, hostname)
 			proc.ActionName = actionName
 
-			return output.Async([]output.ProcessOutput{proc})
+			return asyncOutput(cmd, client, storagePath, creds, []output.ProcessOutput{proc})
 		},
 	}
 
 	cmd.Flags().String("service", "", "Service hostname (required)")
+	addWaitFlags(cmd)
 
 	return cmd
 }
@@ -1,12 +1,16 @@
 package commands
 
 import (
+	"context"
+	"fmt"
 	"os"
+	"path/filepath"
 	"strings"
 
 	"github.com/spf13/cobra"
 	"github.com/zeropsio/zaia/internal/output"
 	"github.com/zeropsio/zaia/internal/platform"
+	"github.com/zeropsio/zaia/internal/validation"
 	"gopkg.in/yaml.v3"
 )
 
@@ -15,16 +19,76 @@ const (
 	fileTypeImportYml = "import.yml"
 )
 
+// Report formats accepted by validate --format.
+const (
+	formatJSON  = "json"
+	formatSARIF = "sarif"
+	formatJUnit = "junit"
+)
+
 // NewValidate creates the validate command.
-func NewValidate() *cobra.Command {
+// storagePath and client are only used by --live.
+func NewValidate(storagePath string, client platform.Client) *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "validate",
 		Short: "Validate YAML configuration",
 		Args:  cobra.NoArgs,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			file, _ := cmd.Flags().GetString("file")
+			files, _ := cmd.Flags().GetStringArray("file")
+			dir, _ := cmd.Flags().GetString("dir")
 			contentStr, _ := cmd.Flags().GetString("content")
 			fileType, _ := cmd.Flags().GetString("type")
+			strict, _ := cmd.Flags().GetBool("strict")
+			liveMode, _ := cmd.Flags().GetBool("live")
+			format, _ := cmd.Flags().GetString("format")
+
+			switch format {
+			case formatJSON, formatSARIF, formatJUnit:
+			default:
+				return output.Err(platform.ErrInvalidParameter,
+					"Unknown format: "+format,
+					"Use --format json, sarif or junit", nil)
+			}
+			rep := &validateReport{format: format, strict: strict}
+			fix, _ := cmd.Flags().GetBool("fix")
+			dryRun, _ := cmd.Flags().GetBool("dry-run")
+			if dryRun && !fix {
+				return output.Err(platform.ErrInvalidUsage,
+					"--dry-run requires --fix", "Run: zaia validate --fix --dry-run", nil)
+			}
+			if fix && (format != formatJSON || liveMode) {
+				return output.Err(platform.ErrInvalidUsage,
+					"--fix cannot be combined with --format or --live",
+					"Run --fix first, then validate the fixed file", nil)
+			}
+
+			var live liveCheck
+			if liveMode {
+				creds, authErr := loadCredentials(storagePath)
+				if authErr != nil {
+					return rep.inputError(authErr.Code, validation.RuleLiveAPI, inputName(files, dir, contentStr),
+						authErr.Message, authErr.Suggestion)
+				}
+				live = newLiveCheck(cmd.Context(), client, creds.ProjectID)
+			}
+
+			if dir != "" {
+				var err error
+				if files, err = projectFiles(dir, rep); err != nil {
+					return err
+				}
+			}
+			if len(files) > 1 {
+				if fix {
+					return output.Err(platform.ErrInvalidUsage,
+						"--fix works on a single file", "Run --fix separately for each file", nil)
+				}
+				return validateProject(files, rep, live)
+			}
+			file := ""
+			if len(files) == 1 {
+				file = files[0]
+			}
 
 			var content []byte
 			var source string
@@ -40,12 +104,12 @@ func NewValidate() *cobra.Command {
 				content, err = os.ReadFile(file)
 				if err != nil {
 					if os.IsNotExist(err) && file == fileTypeZeropsYml {
-						return output.Err(platform.ErrZeropsYmlNotFound,
+						return rep.inputError(platform.ErrZeropsYmlNotFound, validation.RuleFileRead, file,
 							"zerops.yml not found in current directory",
-							"Create zerops.yml or use --file to specify path", nil)
+							"Create zerops.yml or use --file to specify path")
 					}
-					return output.Err(platform.ErrFileNotFound,
-						"Cannot read file: "+file, "", nil)
+					return rep.inputError(platform.ErrFileNotFound, validation.RuleFileRead, file,
+						"Cannot read file: "+file, "")
 				}
 				source = file
 			}
@@ -54,11 +118,25 @@ func NewValidate() *cobra.Command {
 				fileType = detectYamlType(source, content)
 			}
 
+			if fix {
+				if contentStr != "" && !dryRun {
+					return output.Err(platform.ErrInvalidUsage,
+						"--fix cannot rewrite inline content",
+						"Use --fix --dry-run to preview the fixes as a diff, or --file", nil)
+				}
+				return fixFile(content, source, fileType, dryRun)
+			}
+
 			switch fileType {
 			case fileTypeZeropsYml:
-				return validateZeropsYml(content, source)
+				return validateZeropsYml(content, source, rep, live)
 			case fileTypeImportYml:
-				return validateImportYml(content, source)
+				if live != nil {
+					return output.Err(platform.ErrInvalidUsage,
+						"--live checks env references in zerops.yml",
+						"Validate zerops.yml, or pass both files: --file zerops.yml --file import.yml --live", nil)
+				}
+				return validateImportYml(content, source, rep)
 			default:
 				return output.Err(platform.ErrUnknownType,
 					"Unknown file type: "+fileType,
@@ -67,9 +145,15 @@ func NewValidate() *cobra.Command {
 		},
 	}
 
-	cmd.Flags().String("file", "", "File to validate (default: zerops.yml)")
+	cmd.Flags().StringArray("file", nil, "File to validate (default: zerops.yml); repeat with zerops.yml and import.yml for a cross-file check")
+	cmd.Flags().String("dir", "", "Project directory: validate its zerops.yml and import.yml together")
 	cmd.Flags().String("content", "", "Inline YAML content to validate")
 	cmd.Flags().String("type", "", "File type: "+fileTypeZeropsYml+" or "+fileTypeImportYml)
+	cmd.Flags().Bool("live", false, "Resolve ${hostname_VAR} references against the current project (requires login)")
+	cmd.Flags().Bool("strict", false, "Fail on warnings (suppress rules with '# zaia-ignore: <rule-id>')")
+	cmd.Flags().Bool("fix", false, "Apply mechanical fixes and rewrite the file in place")
+	cmd.Flags().Bool("dry-run", false, "With --fix: show a unified diff instead of writing the file")
+	cmd.Flags().String("format", formatJSON, "Output format: json, sarif (code scanning) or junit (test reports)")
 
 	return cmd
 }
@@ -96,114 +180,448 @@ func detectYamlType(source string, content []byte) string {
 	return fileTypeZeropsYml // default
 }
 
-func validateZeropsYml(content []byte, source string) error {
-	var parsed map[string]interface{}
-	if err := yaml.Unmarshal(content, &parsed); err != nil {
-		return output.Err(platform.ErrInvalidZeropsYml,
+func validateZeropsYml(content []byte, source string, rep *validateReport, live liveCheck) error {
+	if issues := validation.ValidateZeropsYml(content); len(issues) > 0 {
+		return rep.fail(platform.ErrInvalidZeropsYml,
 			"zerops.yml validation failed",
 			"",
 			map[string]interface{}{
-				"file": source,
-				"errors": []map[string]string{
-					{"path": "", "error": "Invalid YAML syntax: " + err.Error(), "fix": "Check YAML formatting"},
-				},
-			})
+				"file":   source,
+				"errors": issues,
+			},
+			validation.FileResult{File: source, Type: fileTypeZeropsYml, Errors: issues})
 	}
 
-	// Basic structural validation
-	var errors []map[string]string
-
-	zeropsRaw, ok := parsed["zerops"]
-	if !ok {
-		errors = append(errors, map[string]string{
-			"path":  "",
-			"error": "Missing 'zerops' key",
-			"fix":   "Add: zerops:\n  - run:\n      base: nodejs@22",
-		})
-	} else {
-		zeropsArr, ok := zeropsRaw.([]interface{})
-		if !ok {
-			errors = append(errors, map[string]string{
-				"path":  "zerops",
-				"error": "'zerops' must be an array",
-				"fix":   "Format: zerops:\n  - run:\n      base: nodejs@22",
-			})
-		} else if len(zeropsArr) == 0 {
-			errors = append(errors, map[string]string{
-				"path":  "zerops",
-				"error": "'zerops' array is empty",
-				"fix":   "Add at least one service configuration",
-			})
+	fields := map[string]interface{}{"file": source, "type": fileTypeZeropsYml}
+	if live != nil {
+		issues, err := live(content, nil)
+		if err != nil {
+			return rep.inputError(platform.ErrAPIError, validation.RuleLiveAPI, source, err.Error(), "")
+		}
+		if len(issues) > 0 {
+			return liveError(rep, source, issues)
 		}
+		fields["live"] = true
 	}
+	findings := validation.LintZeropsYml(content)
+	return rep.result(platform.ErrInvalidZeropsYml, fields, findings,
+		validation.FileResult{File: source, Type: fileTypeZeropsYml, Findings: findings})
+}
 
-	if len(errors) > 0 {
-		return output.Err(platform.ErrInvalidZeropsYml,
-			"zerops.yml validation failed",
+func validateImportYml(content []byte, source string, rep *validateReport) error {
+	// Check for project: section (not allowed in project-scoped context)
+	if hasProjectSection(string(content)) {
+		return projectSectionError(rep, source)
+	}
+
+	if issues := validation.ValidateImportYml(content); len(issues) > 0 {
+		return rep.fail(platform.ErrInvalidImportYml,
+			"import.yml validation failed",
 			"",
 			map[string]interface{}{
 				"file":   source,
-				"errors": errors,
-			})
+				"errors": issues,
+			},
+			validation.FileResult{File: source, Type: fileTypeImportYml, Errors: issues})
 	}
+	findings := validation.LintImportYml(content)
+	return rep.result(platform.ErrInvalidImportYml,
+		map[string]interface{}{"file": source, "type": fileTypeImportYml}, findings,
+		validation.FileResult{File: source, Type: fileTypeImportYml, Findings: findings})
+}
 
-	return output.Sync(map[string]interface{}{
-		"valid":    true,
-		"file":     source,
-		"type":     fileTypeZeropsYml,
-		"warnings": []string{},
-		"info":     []string{},
+func projectSectionError(rep *validateReport, source string) error {
+	const (
+		msg        = "import.yml must not contain 'project:' section in project-scoped context"
+		suggestion = "Remove the 'project:' section. ZAIA imports services into the current project context."
+	)
+	return rep.fail(platform.ErrImportHasProject, msg, suggestion,
+		map[string]interface{}{"file": source},
+		validation.FileResult{
+			File: source, Type: fileTypeImportYml, ErrorRule: validation.RuleProjectSection,
+			Errors: []validation.Issue{{Path: "project", Error: msg, Fix: suggestion}},
+		})
+}
+
+// validateReport renders validation outcomes in the format selected by --format.
+// JSON uses the standard envelopes; SARIF and JUnit replace them with a report
+// document and signal failure through the returned error only.
+type validateReport struct {
+	format string
+	strict bool
+}
+
+// inputError reports that file could not be validated at all: it is unreadable, or --live
+// could not reach the project. Report formats show it as a failed check of file.
+func (r *validateReport) inputError(code, rule, file, message, suggestion string) error {
+	return r.fail(code, message, suggestion, nil, validation.FileResult{
+		File: file, ErrorRule: rule,
+		Errors: []validation.Issue{{Error: message, Fix: suggestion}},
 	})
 }
 
-func validateImportYml(content []byte, source string) error {
-	var parsed map[string]interface{}
-	if err := yaml.Unmarshal(content, &parsed); err != nil {
-		return output.Err(platform.ErrInvalidImportYml,
-			"import.yml validation failed",
-			"",
-			map[string]interface{}{
-				"file": source,
-				"errors": []map[string]string{
-					{"path": "", "error": "Invalid YAML syntax: " + err.Error(), "fix": "Check YAML formatting"},
-				},
-			})
+// inputName names the validated input before it is read, for errors that come first.
+func inputName(files []string, dir, content string) string {
+	switch {
+	case content != "":
+		return "inline"
+	case len(files) > 0:
+		return files[0]
+	case dir != "":
+		return dir
 	}
+	return fileTypeZeropsYml
+}
 
-	var errors []map[string]string
+func (r *validateReport) fail(code, message, suggestion string, ctx map[string]interface{}, results ...validation.FileResult) error {
+	if r.format == formatJSON {
+		return output.Err(code, message, suggestion, ctx)
+	}
+	if err := r.render(results); err != nil {
+		return err
+	}
+	return &output.ZaiaError{Message: message, Code: code}
+}
 
-	// Check for project: section (not allowed in project-scoped context)
-	if _, ok := parsed["project"]; ok {
-		return output.Err(platform.ErrImportHasProject,
-			"import.yml must not contain 'project:' section in project-scoped context",
-			"Remove the 'project:' section. ZAIA imports services into the current project context.",
-			map[string]interface{}{"file": source})
-	}
-
-	// Check services array
-	if _, ok := parsed["services"]; !ok {
-		errors = append(errors, map[string]string{
-			"path":  "",
-			"error": "Missing 'services' key",
-			"fix":   "Add: services:\n  - hostname: api\n    type: nodejs@22",
-		})
+// result reports lint findings for schema-valid input described by fields.
+// In strict mode any warning fails validation with errCode.
+func (r *validateReport) result(errCode string, fields map[string]interface{}, findings []validation.Finding, results ...validation.FileResult) error {
+	warnings, info := splitFindings(findings)
+	if r.strict && len(warnings) > 0 {
+		ctx := map[string]interface{}{"warnings": warnings}
+		for k, v := range fields {
+			ctx[k] = v
+		}
+		return r.fail(errCode,
+			fmt.Sprintf("%s has warnings (--strict)", fields["type"]),
+			"Fix the warnings or suppress a rule with '# zaia-ignore: <rule-id>'",
+			ctx, results...)
 	}
 
-	if len(errors) > 0 {
-		return output.Err(platform.ErrInvalidImportYml,
-			"import.yml validation failed",
-			"",
+	if r.format != formatJSON {
+		return r.render(results)
+	}
+	data := map[string]interface{}{
+		"valid":    true,
+		"warnings": warnings,
+		"info":     info,
+	}
+	for k, v := range fields {
+		data[k] = v
+	}
+	return output.Sync(data)
+}
+
+func (r *validateReport) render(results []validation.FileResult) error {
+	var data []byte
+	var err error
+	if r.format == formatSARIF {
+		data, err = validation.SARIF(results, version)
+	} else {
+		data, err = validation.JUnit(results, r.strict)
+	}
+	if err != nil {
+		return output.Err(platform.ErrInvalidUsage, "Cannot render "+r.format+" report: "+err.Error(), "", nil)
+	}
+	return output.Raw(data)
+}
+
+// splitFindings separates findings by severity. Both slices are non-nil so they encode as [].
+func splitFindings(findings []validation.Finding) (warnings, info []validation.Finding) {
+	warnings, info = []validation.Finding{}, []validation.Finding{}
+	for _, f := range findings {
+		if f.Severity == validation.SeverityWarning {
+			warnings = append(warnings, f)
+		} else {
+			info = append(info, f)
+		}
+	}
+	return warnings, info
+}
+
+// projectFileNames are looked up by --dir, in order of preference.
+var projectFileNames = []string{"zerops.yml", "zerops.yaml", "import.yml", "import.yaml"}
+
+// projectFiles returns the zerops.yml and import.yml files present in dir.
+func projectFiles(dir string, rep *validateReport) ([]string, error) {
+	var files []string
+	seen := make(map[string]bool)
+	for _, name := range projectFileNames {
+		fileType := fileTypeZeropsYml
+		if strings.HasPrefix(name, "import") {
+			fileType = fileTypeImportYml
+		}
+		path := filepath.Join(dir, name)
+		if seen[fileType] {
+			continue
+		}
+		if _, err := os.Stat(path); err == nil {
+			files = append(files, path)
+			seen[fileType] = true
+		}
+	}
+	if len(files) == 0 {
+		return nil, rep.inputError(platform.ErrFileNotFound, validation.RuleFileRead, dir,
+			"No zerops.yml or import.yml found in "+dir,
+			"Use --file to specify the files to validate")
+	}
+	return files, nil
+}
+
+// projectFile is one input of a cross-file validation.
+type projectFile struct {
+	path     string
+	fileType string
+	content  []byte
+}
+
+// validateProject validates a zerops.yml and an import.yml individually, then checks
+// that they agree with each other: setups vs. hostnames and ${hostname_VAR} references.
+func validateProject(paths []string, rep *validateReport, live liveCheck) error {
+	byType := make(map[string]*projectFile)
+	for _, path := range paths {
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return rep.inputError(platform.ErrFileNotFound, validation.RuleFileRead, path, "Cannot read file: "+path, "")
+		}
+		fileType := detectYamlType(filepath.Base(path), content)
+		if prev, dup := byType[fileType]; dup {
+			return output.Err(platform.ErrInvalidUsage,
+				"Both "+prev.path+" and "+path+" are "+fileType,
+				"Pass at most one zerops.yml and one import.yml", nil)
+		}
+		byType[fileType] = &projectFile{path: path, fileType: fileType, content: content}
+	}
+
+	zf, imf := byType[fileTypeZeropsYml], byType[fileTypeImportYml]
+	if imf != nil && hasProjectSection(string(imf.content)) {
+		return projectSectionError(rep, imf.path)
+	}
+
+	var failed []map[string]interface{}
+	var failedResults []validation.FileResult
+	errCode := ""
+	var findings []validation.Finding
+	for _, f := range []*projectFile{zf, imf} {
+		if f == nil {
+			continue
+		}
+		code := platform.ErrInvalidZeropsYml
+		issues, lint := validation.ValidateZeropsYml, validation.LintZeropsYml
+		if f.fileType == fileTypeImportYml {
+			code = platform.ErrInvalidImportYml
+			issues, lint = validation.ValidateImportYml, validation.LintImportYml
+		}
+		if errs := issues(f.content); len(errs) > 0 {
+			if errCode == "" {
+				errCode = code
+			}
+			failed = append(failed, map[string]interface{}{"file": f.path, "type": f.fileType, "errors": errs})
+			failedResults = append(failedResults, validation.FileResult{File: f.path, Type: f.fileType, Errors: errs})
+			continue
+		}
+		for _, finding := range lint(f.content) {
+			finding.File = f.path
+			findings = append(findings, finding)
+		}
+	}
+	if len(failed) > 0 {
+		return rep.fail(errCode, "Project validation failed", "", map[string]interface{}{"files": failed}, failedResults...)
+	}
+
+	if zf != nil && imf != nil {
+		findings = append(findings, validation.CrossCheck(zf.path, zf.content, imf.path, imf.content)...)
+	}
+
+	fields := map[string]interface{}{"files": paths, "type": "project"}
+	if live != nil {
+		if zf == nil {
+			return output.Err(platform.ErrInvalidUsage,
+				"--live checks env references in zerops.yml", "Include zerops.yml in the validated files", nil)
+		}
+		var planned map[string]bool
+		if imf != nil {
+			planned = importHostnames(imf.content)
+		}
+		issues, err := live(zf.content, planned)
+		if err != nil {
+			return rep.inputError(platform.ErrAPIError, validation.RuleLiveAPI, zf.path, err.Error(), "")
+		}
+		if len(issues) > 0 {
+			return liveError(rep, zf.path, issues)
+		}
+		fields["live"] = true
+	}
+	// Under --strict the error code follows the file holding the first warning.
+	strictCode := platform.ErrInvalidZeropsYml
+	if warnings, _ := splitFindings(findings); len(warnings) > 0 && imf != nil && warnings[0].File == imf.path {
+		strictCode = platform.ErrInvalidImportYml
+	}
+	var results []validation.FileResult
+	for _, f := range []*projectFile{zf, imf} {
+		if f == nil {
+			continue
+		}
+		res := validation.FileResult{File: f.path, Type: f.fileType}
+		for _, finding := range findings {
+			if finding.File == f.path {
+				res.Findings = append(res.Findings, finding)
+			}
+		}
+		results = append(results, res)
+	}
+	return rep.result(strictCode, fields, findings, results...)
+}
+
+// liveCheck resolves zerops.yml env references against the current project.
+// planned lists hostnames that do not exist yet but will be created by an import.
+// Errors are API failures, reported by the caller in the selected format.
+type liveCheck func(content []byte, planned map[string]bool) ([]validation.Issue, error)
+
+// newLiveCheck returns a liveCheck that reads services and env variables of projectID.
+// Service env is only fetched for hostnames that are actually referenced.
+func newLiveCheck(ctx context.Context, client platform.Client, projectID string) liveCheck {
+	return func(content []byte, planned map[string]bool) ([]validation.Issue, error) {
+		refs := validation.ZeropsYmlServiceRefs(content)
+		if len(refs) == 0 {
+			return nil, nil
+		}
+
+		services, err := client.ListServices(ctx, projectID)
+		if err != nil {
+			return nil, err
+		}
+		env := validation.ProjectEnv{
+			Services: make(map[string]map[string]bool, len(services)),
+			Project:  make(map[string]bool),
+		}
+		for _, svc := range services {
+			env.Services[svc.Name] = make(map[string]bool)
+		}
+
+		fetched := make(map[string]bool)
+		for _, ref := range refs {
+			svc := findServiceByHostname(services, ref.Hostname)
+			if svc == nil || fetched[svc.Name] {
+				continue
+			}
+			fetched[svc.Name] = true
+			vars, err := client.GetServiceEnv(ctx, svc.ID)
+			if err != nil {
+				return nil, err
+			}
+			for _, v := range vars {
+				env.Services[svc.Name][v.Key] = true
+			}
+		}
+
+		projectVars, err := client.GetProjectEnv(ctx, projectID)
+		if err != nil {
+			return nil, err
+		}
+		for _, v := range projectVars {
+			env.Project[v.Key] = true
+		}
+
+		return validation.CheckLiveRefs(content, env, planned), nil
+	}
+}
+
+func liveError(rep *validateReport, source string, issues []validation.Issue) error {
+	return rep.fail(platform.ErrInvalidZeropsYml,
+		"zerops.yml references env variables that do not exist in the project",
+		"Create the missing variables or fix the references, then re-run with --live",
+		map[string]interface{}{
+			"file":   source,
+			"live":   true,
+			"errors": issues,
+		},
+		validation.FileResult{File: source, Type: fileTypeZeropsYml, ErrorRule: validation.RuleLiveEnvRef, Errors: issues})
+}
+
+// importHostnames returns the hostnames declared in import.yml content.
+func importHostnames(content []byte) map[string]bool {
+	var parsed struct {
+		Services []struct {
+			Hostname string `yaml:"hostname"`
+		} `yaml:"services"`
+	}
+	_ = yaml.Unmarshal(content, &parsed)
+	hosts := make(map[string]bool, len(parsed.Services))
+	for _, s := range parsed.Services {
+		hosts[s.Hostname] = true
+	}
+	return hosts
+}
+
+// fixFile applies mechanical fixes to one file and reports the errors that remain.
+// With dryRun the file is left untouched and the changes are returned as a unified diff.
+func fixFile(content []byte, source, fileType string, dryRun bool) error {
+	fix, validate, errCode := validation.FixZeropsYml, validation.ValidateZeropsYml, platform.ErrInvalidZeropsYml
+	switch fileType {
+	case fileTypeZeropsYml:
+	case fileTypeImportYml:
+		fix, validate, errCode = validation.FixImportYml, validation.ValidateImportYml, platform.ErrInvalidImportYml
+	default:
+		return output.Err(platform.ErrUnknownType,
+			"Unknown file type: "+fileType,
+			"Specify --type zerops.yml or --type import.yml", nil)
+	}
+
+	fixed, fixes, err := fix(content)
+	if err != nil {
+		return output.Err(errCode,
+			fileType+" cannot be fixed automatically",
+			"Fix the YAML syntax error first",
 			map[string]interface{}{
 				"file":   source,
-				"errors": errors,
+				"errors": validate(content),
 			})
 	}
+	if fixes == nil {
+		fixes = []validation.AppliedFix{}
+	}
 
-	return output.Sync(map[string]interface{}{
-		"valid":    true,
-		"file":     source,
-		"type":     fileTypeImportYml,
-		"warnings": []string{},
-		"info":     []string{},
-	})
+	remaining := validate(fixed)
+	if remaining == nil {
+		remaining = []validation.Issue{}
+	}
+	data := map[string]interface{}{
+		"file":   source,
+		"type":   fileType,
+		"dryRun": dryRun,
+		"fixes":  fixes,
+		"valid":  len(remaining) == 0,
+		"errors": remaining,
+	}
+
+	if dryRun {
+		name := diffPath(source)
+		data["diff"] = validation.UnifiedDiff("a/"+name, "b/"+name, content, fixed)
+		return output.Sync(data)
+	}
+	if len(fixes) > 0 {
+		perm := os.FileMode(0644)
+		if info, err := os.Stat(source); err == nil {
+			perm = info.Mode().Perm()
+		}
+		if err := os.WriteFile(source, fixed, perm); err != nil {
+			return output.Err(platform.ErrPermissionDenied, "Cannot write file: "+source, err.Error(), nil)
+		}
+	}
+	data["written"] = len(fixes) > 0
+	return output.Sync(data)
+}
+
+// diffPath returns source as it goes after a/ and b/ in diff headers: relative to the
+// working directory when it is below it, otherwise without the leading slash.
+func diffPath(source string) string {
+	if filepath.IsAbs(source) {
+		if wd, err := os.Getwd(); err == nil {
+			if rel, err := filepath.Rel(wd, source); err == nil && filepath.IsLocal(rel) {
+				return filepath.ToSlash(rel)
+			}
+		}
+	}
+	return strings.TrimLeft(filepath.ToSlash(source), "/")
 }
@@ -3,17 +3,21 @@ package commands
 import (
 	"bytes"
 	"encoding/json"
+	"errors"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/zeropsio/zaia/internal/output"
+	"github.com/zeropsio/zaia/internal/platform"
 )
 
 func TestValidateCmd_ValidZeropsYml(t *testing.T) {
 	dir := t.TempDir()
 	yamlContent := `zerops:
-  - run:
+  - setup: api
+    run:
       base: nodejs@22
       ports:
         - port: 3000
@@ -22,7 +26,7 @@ func TestValidateCmd_ValidZeropsYml(t *testing.T) {
 	yamlPath := filepath.Join(dir, "zerops.yml")
 	_ = os.WriteFile(yamlPath, []byte(yamlContent), 0644)
 
-	cmd := NewValidate()
+	cmd := NewValidate("", nil)
 	cmd.SetArgs([]string{"--file", yamlPath})
 
 	var stdout bytes.Buffer
@@ -51,7 +55,7 @@ func TestValidateCmd_InvalidZeropsYml(t *testing.T) {
 	yamlPath := filepath.Join(dir, "zerops.yml")
 	_ = os.WriteFile(yamlPath, []byte(yamlContent), 0644)
 
-	cmd := NewValidate()
+	cmd := NewValidate("", nil)
 	cmd.SetArgs([]string{"--file", yamlPath, "--type", "zerops.yml"})
 
 	var stdout bytes.Buffer
@@ -77,11 +81,12 @@ func TestValidateCmd_ValidImportYml(t *testing.T) {
     type: nodejs@22
   - hostname: db
     type: postgresql@16
+    mode: NON_HA
 `
 	yamlPath := filepath.Join(dir, "import.yml")
 	_ = os.WriteFile(yamlPath, []byte(yamlContent), 0644)
 
-	cmd := NewValidate()
+	cmd := NewValidate("", nil)
 	cmd.SetArgs([]string{"--file", yamlPath})
 
 	var stdout bytes.Buffer
@@ -115,7 +120,7 @@ services:
 	yamlPath := filepath.Join(dir, "import.yml")
 	_ = os.WriteFile(yamlPath, []byte(yamlContent), 0644)
 
-	cmd := NewValidate()
+	cmd := NewValidate("", nil)
 	cmd.SetArgs([]string{"--file", yamlPath})
 
 	var stdout bytes.Buffer
@@ -136,10 +141,11 @@ services:
 
 func TestValidateCmd_InlineContent(t *testing.T) {
 	content := `zerops:
-  - run:
+  - setup: api
+    run:
       base: nodejs@22
 `
-	cmd := NewValidate()
+	cmd := NewValidate("", nil)
 	cmd.SetArgs([]string{"--content", content, "--type", "zerops.yml"})
 
 	var stdout bytes.Buffer
@@ -160,7 +166,7 @@ func TestValidateCmd_InlineContent(t *testing.T) {
 }
 
 func TestValidateCmd_FileNotFound(t *testing.T) {
-	cmd := NewValidate()
+	cmd := NewValidate("", nil)
 	cmd.SetArgs([]string{"--file", "/nonexistent/zerops.yml"})
 
 	var stdout bytes.Buffer
@@ -181,7 +187,7 @@ func TestValidateCmd_FileNotFound(t *testing.T) {
 
 func TestValidateCmd_InvalidYamlSyntax(t *testing.T) {
 	content := `{invalid yaml[`
-	cmd := NewValidate()
+	cmd := NewValidate("", nil)
 	cmd.SetArgs([]string{"--content", content, "--type", "zerops.yml"})
 
 	var stdout bytes.Buffer
@@ -193,3 +199,371 @@ func TestValidateCmd_InvalidYamlSyntax(t *testing.T) {
 		t.Fatal("expected error for invalid YAML syntax")
 	}
 }
+
+func TestValidateCmd_ReportsEachSchemaError(t *testing.T) {
+	content := `zerops:
+  - setup: api
+    build:
+      base: nodejs@22
+      buildCommand:
+        - npm ci
+      deployFiles: ./
+    run:
+      ports:
+        - httpSupport: true
+`
+	cmd := NewValidate("", nil)
+	cmd.SetArgs([]string{"--content", content, "--type", "zerops.yml"})
+
+	var stdout bytes.Buffer
+	output.SetWriter(&stdout)
+	defer output.ResetWriter()
+
+	err := cmd.Execute()
+	if err == nil {
+		t.Fatal("expected error for schema violations")
+	}
+
+	var resp map[string]interface{}
+	_ = json.Unmarshal(stdout.Bytes(), &resp)
+	if resp["code"] != "INVALID_ZEROPS_YML" {
+		t.Errorf("code = %v, want INVALID_ZEROPS_YML", resp["code"])
+	}
+	ctx := resp["context"].(map[string]interface{})
+	errs := ctx["errors"].([]interface{})
+	if len(errs) != 2 {
+		t.Fatalf("errors = %d, want 2: %v", len(errs), errs)
+	}
+	for _, e := range errs {
+		entry := e.(map[string]interface{})
+		for _, key := range []string{"path", "error", "fix"} {
+			if _, ok := entry[key]; !ok {
+				t.Errorf("error entry missing %q: %v", key, entry)
+			}
+		}
+	}
+}
+
+func TestValidateCmd_ErrorsHaveLineAndColumn(t *testing.T) {
+	content := "zerops:\n  - setup: api\n    run:\n      startt: npm start\n"
+	cmd := NewValidate("", nil)
+	cmd.SetArgs([]string{"--content", content, "--type", "zerops.yml"})
+
+	var stdout bytes.Buffer
+	output.SetWriter(&stdout)
+	defer output.ResetWriter()
+
+	if err := cmd.Execute(); err == nil {
+		t.Fatal("expected error for unknown key")
+	}
+
+	var resp map[string]interface{}
+	_ = json.Unmarshal(stdout.Bytes(), &resp)
+	errs := resp["context"].(map[string]interface{})["errors"].([]interface{})
+	entry := errs[0].(map[string]interface{})
+	if entry["line"] != float64(4) || entry["column"] != float64(7) {
+		t.Errorf("position = %v:%v, want 4:7", entry["line"], entry["column"])
+	}
+}
+
+func TestValidateCmd_WarningsAndStrict(t *testing.T) {
+	content := `services:
+  - hostname: db
+    type: postgresql@16
+    mode: NON_HA
+`
+	run := func(args ...string) (map[string]interface{}, error) {
+		cmd := NewValidate("", nil)
+		cmd.SetArgs(append([]string{"--content", content, "--type", "import.yml"}, args...))
+		var stdout bytes.Buffer
+		output.SetWriter(&stdout)
+		defer output.ResetWriter()
+		err := cmd.Execute()
+		var resp map[string]interface{}
+		_ = json.Unmarshal(stdout.Bytes(), &resp)
+		return resp, err
+	}
+
+	resp, err := run()
+	if err != nil {
+		t.Fatal(err)
+	}
+	data := resp["data"].(map[string]interface{})
+	warnings, _ := data["warnings"].([]interface{})
+	if len(warnings) != 1 {
+		t.Fatalf("warnings = %v, want 1", data["warnings"])
+	}
+	if w := warnings[0].(map[string]interface{}); w["rule"] != "non-ha-database" {
+		t.Errorf("rule = %v, want non-ha-database", w["rule"])
+	}
+
+	resp, err = run("--strict")
+	if err == nil {
+		t.Fatal("expected --strict to fail on warnings")
+	}
+	if resp["code"] != "INVALID_IMPORT_YML" {
+		t.Errorf("code = %v, want INVALID_IMPORT_YML", resp["code"])
+	}
+}
+
+func TestValidateCmd_ProjectDir(t *testing.T) {
+	dir := t.TempDir()
+	_ = os.WriteFile(filepath.Join(dir, "zerops.yml"), []byte(`zerops:
+  - setup: app
+    run:
+      base: nodejs@22
+      envVariables:
+        DB_URL: ${database_connectionString}
+`), 0644)
+	_ = os.WriteFile(filepath.Join(dir, "import.yml"), []byte(`services:
+  - hostname: app
+    type: nodejs@22
+  - hostname: db
+    type: postgresql@16
+    mode: HA
+`), 0644)
+
+	cmd := NewValidate("", nil)
+	cmd.SetArgs([]string{"--dir", dir})
+
+	var stdout bytes.Buffer
+	output.SetWriter(&stdout)
+	defer output.ResetWriter()
+
+	if err := cmd.Execute(); err != nil {
+		t.Fatal(err)
+	}
+
+	var resp map[string]interface{}
+	_ = json.Unmarshal(stdout.Bytes(), &resp)
+	data := resp["data"].(map[string]interface{})
+	if data["type"] != "project" {
+		t.Errorf("type = %v, want project", data["type"])
+	}
+	if files, _ := data["files"].([]interface{}); len(files) != 2 {
+		t.Errorf("files = %v, want both files", data["files"])
+	}
+	warnings, _ := data["warnings"].([]interface{})
+	if len(warnings) != 1 {
+		t.Fatalf("warnings = %v, want 1", data["warnings"])
+	}
+	w := warnings[0].(map[string]interface{})
+	if w["rule"] != "unresolved-env-ref" || w["file"] != filepath.Join(dir, "zerops.yml") {
+		t.Errorf("warning = %v, want unresolved-env-ref in zerops.yml", w)
+	}
+}
+
+func TestValidateCmd_MultipleFilesSchemaErrors(t *testing.T) {
+	dir := t.TempDir()
+	zPath := filepath.Join(dir, "zerops.yml")
+	iPath := filepath.Join(dir, "import.yml")
+	_ = os.WriteFile(zPath, []byte("zerops:\n  - run:\n      start: x\n"), 0644)
+	_ = os.WriteFile(iPath, []byte("services:\n  - hostname: app\n    type: nodejs@22\n"), 0644)
+
+	cmd := NewValidate("", nil)
+	cmd.SetArgs([]string{"--file", zPath, "--file", iPath})
+
+	var stdout bytes.Buffer
+	output.SetWriter(&stdout)
+	defer output.ResetWriter()
+
+	if err := cmd.Execute(); err == nil {
+		t.Fatal("expected error for invalid zerops.yml")
+	}
+
+	var resp map[string]interface{}
+	_ = json.Unmarshal(stdout.Bytes(), &resp)
+	if resp["code"] != "INVALID_ZEROPS_YML" {
+		t.Errorf("code = %v, want INVALID_ZEROPS_YML", resp["code"])
+	}
+}
+
+func TestValidateCmd_Live(t *testing.T) {
+	storagePath := setupAuthenticatedStorage(t)
+	mock := platform.NewMock().
+		WithServices([]platform.ServiceStack{{ID: "s1", Name: "db", Status: "ACTIVE"}}).
+		WithServiceEnv("s1", []platform.EnvVar{{ID: "e1", Key: "connectionString", Content: "postgres://"}})
+
+	run := func(content string) (map[string]interface{}, error) {
+		cmd := NewValidate(storagePath, mock)
+		cmd.SetArgs([]string{"--content", content, "--type", "zerops.yml", "--live"})
+		var stdout bytes.Buffer
+		output.SetWriter(&stdout)
+		defer output.ResetWriter()
+		err := cmd.Execute()
+		var resp map[string]interface{}
+		_ = json.Unmarshal(stdout.Bytes(), &resp)
+		return resp, err
+	}
+
+	resp, err := run("zerops:\n  - setup: api\n    run:\n      envVariables:\n        DB: ${db_connectionString}\n")
+	if err != nil {
+		t.Fatalf("expected resolvable reference to pass, got %v", resp)
+	}
+	if data := resp["data"].(map[string]interface{}); data["live"] != true {
+		t.Errorf("live = %v, want true", data["live"])
+	}
+
+	resp, err = run("zerops:\n  - setup: api\n    run:\n      envVariables:\n        DB: ${db_connectionUrl}\n")
+	if err == nil {
+		t.Fatal("expected error for missing variable")
+	}
+	if resp["code"] != "INVALID_ZEROPS_YML" {
+		t.Errorf("code = %v, want INVALID_ZEROPS_YML", resp["code"])
+	}
+}
+
+func TestValidateCmd_LiveRequiresAuth(t *testing.T) {
+	cmd := NewValidate(t.TempDir(), platform.NewMock())
+	cmd.SetArgs([]string{"--content", "zerops:\n  - setup: api\n", "--live"})
+
+	var stdout bytes.Buffer
+	output.SetWriter(&stdout)
+	defer output.ResetWriter()
+
+	if err := cmd.Execute(); err == nil {
+		t.Fatal("expected auth error")
+	}
+	var resp map[string]interface{}
+	_ = json.Unmarshal(stdout.Bytes(), &resp)
+	if resp["code"] != "AUTH_REQUIRED" {
+		t.Errorf("code = %v, want AUTH_REQUIRED", resp["code"])
+	}
+}
+
+func TestValidateCmd_FormatSARIF(t *testing.T) {
+	cmd := NewValidate("", nil)
+	cmd.SetArgs([]string{"--content", "zerops:\n  - setup: api\n    run:\n      start: [a]\n", "--type", "zerops.yml", "--format", "sarif"})
+
+	var stdout bytes.Buffer
+	output.SetWriter(&stdout)
+	defer output.ResetWriter()
+
+	err := cmd.Execute()
+	var zErr *output.ZaiaError
+	if !errors.As(err, &zErr) || zErr.Code != "INVALID_ZEROPS_YML" {
+		t.Fatalf("err = %v, want INVALID_ZEROPS_YML", err)
+	}
+
+	var log map[string]interface{}
+	if err := json.Unmarshal(stdout.Bytes(), &log); err != nil {
+		t.Fatalf("output is not JSON: %v", err)
+	}
+	if log["version"] != "2.1.0" {
+		t.Errorf("version = %v, want SARIF 2.1.0", log["version"])
+	}
+	if _, isEnvelope := log["type"]; isEnvelope {
+		t.Error("SARIF output must not be wrapped in the JSON envelope")
+	}
+}
+
+func TestValidateCmd_FormatJUnitValid(t *testing.T) {
+	cmd := NewValidate("", nil)
+	cmd.SetArgs([]string{"--content", "zerops:\n  - setup: api\n", "--type", "zerops.yml", "--format", "junit"})
+
+	var stdout bytes.Buffer
+	output.SetWriter(&stdout)
+	defer output.ResetWriter()
+
+	if err := cmd.Execute(); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(stdout.String(), `<testsuite name="inline" tests="1" failures="0">`) {
+		t.Errorf("unexpected JUnit output:\n%s", stdout.String())
+	}
+}
+
+// CI steps parse the report even when validation could not run at all.
+func TestValidateCmd_FormatReportsInputErrors(t *testing.T) {
+	storagePath := setupAuthenticatedStorage(t)
+	apiDown := platform.NewMock().WithError("ListServices", errors.New("connection refused"))
+	refs := "zerops:\n  - setup: api\n    run:\n      envVariables:\n        DB: ${db_connectionString}\n"
+
+	tests := []struct {
+		name   string
+		client platform.Client
+		args   []string
+		code   string
+		rule   string
+	}{
+		{"missing file sarif", nil, []string{"--file", filepath.Join(t.TempDir(), "zerops.yml"), "--format", "sarif"}, "FILE_NOT_FOUND", "file-read"},
+		{"empty dir junit", nil, []string{"--dir", t.TempDir(), "--format", "junit"}, "FILE_NOT_FOUND", "file-read"},
+		{"live api error sarif", apiDown, []string{"--content", refs, "--type", "zerops.yml", "--live", "--format", "sarif"}, "API_ERROR", "live-api"},
+		{"live api error junit", apiDown, []string{"--content", refs, "--type", "zerops.yml", "--live", "--format", "junit"}, "API_ERROR", "live-api"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd := NewValidate(storagePath, tt.client)
+			cmd.SetArgs(tt.args)
+			var stdout bytes.Buffer
+			output.SetWriter(&stdout)
+			defer output.ResetWriter()
+
+			err := cmd.Execute()
+			var zErr *output.ZaiaError
+			if !errors.As(err, &zErr) || zErr.Code != tt.code {
+				t.Fatalf("err = %v, want %s", err, tt.code)
+			}
+			out := stdout.String()
+			if strings.Contains(out, `"type": "error"`) || strings.Contains(out, `"type":"error"`) {
+				t.Fatalf("report format got a JSON envelope:\n%s", out)
+			}
+			if !strings.Contains(out, tt.rule) {
+				t.Errorf("report lacks rule %s:\n%s", tt.rule, out)
+			}
+		})
+	}
+}
+
+func TestValidateCmd_UnknownFormat(t *testing.T) {
+	cmd := NewValidate("", nil)
+	cmd.SetArgs([]string{"--content", "zerops: []", "--format", "xml"})
+
+	var stdout bytes.Buffer
+	output.SetWriter(&stdout)
+	defer output.ResetWriter()
+
+	if err := cmd.Execute(); err == nil {
+		t.Fatal("expected error for unknown format")
+	}
+}
+
+func TestValidateCmd_Fix(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "import.yml")
+	original := "services:\n  - name: api\n    type: nodejs@22\n"
+	_ = os.WriteFile(path, []byte(original), 0644)
+
+	run := func(args ...string) map[string]interface{} {
+		cmd := NewValidate("", nil)
+		cmd.SetArgs(append([]string{"--file", path, "--fix"}, args...))
+		var stdout bytes.Buffer
+		output.SetWriter(&stdout)
+		defer output.ResetWriter()
+		if err := cmd.Execute(); err != nil {
+			t.Fatal(err)
+		}
+		var resp map[string]interface{}
+		_ = json.Unmarshal(stdout.Bytes(), &resp)
+		return resp["data"].(map[string]interface{})
+	}
+
+	data := run("--dry-run")
+	if diff, _ := data["diff"].(string); !strings.Contains(diff, "-  - name: api\n+  - hostname: api\n") {
+		t.Errorf("diff = %q, want name -> hostname change", data["diff"])
+	}
+	if diff, _ := data["diff"].(string); strings.Contains(diff, "//") {
+		t.Errorf("diff headers = %q, want no double slash", diff)
+	}
+	if got, _ := os.ReadFile(path); string(got) != original {
+		t.Fatalf("--dry-run modified the file: %q", got)
+	}
+
+	data = run()
+	if data["written"] != true || data["valid"] != true {
+		t.Errorf("data = %v, want written and valid", data)
+	}
+	if got, _ := os.ReadFile(path); !strings.Contains(string(got), "hostname: api") {
+		t.Errorf("file not fixed: %q", got)
+	}
+}
@@ -0,0 +1,312 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/zeropsio/zaia/internal/auth"
+	"github.com/zeropsio/zaia/internal/output"
+	"github.com/zeropsio/zaia/internal/platform"
+)
+
+// defaultHeartbeat is how often --stream reports that zaia is still waiting.
+const defaultHeartbeat = 10 * time.Second
+
+// Process statuses of ProcessOutput (API statuses mapped by output.MapProcessToOutput).
+const (
+	statusFinished = "FINISHED"
+	statusFailed   = "FAILED"
+	statusCanceled = "CANCELED"
+)
+
+// processResult is the final state of a waited-for process.
+type processResult struct {
+	output.ProcessOutput
+	Duration string `json:"duration,omitempty"` // created → finished, or how long zaia waited
+	Error    string `json:"error,omitempty"`    // polling failed; status is the last one known
+}
+
+// waitConfig controls how async commands wait for their processes.
+type waitConfig struct {
+	timeout   time.Duration
+	interval  time.Duration // first polling interval; zero means platform.DefaultWaitInterval
+	stream    bool          // write NDJSON progress events before the final envelope
+	heartbeat time.Duration // with stream
+}
+
+// addWaitFlags adds the flags that make a command returning processes block until they end.
+func addWaitFlags(cmd *cobra.Command) {
+	cmd.Flags().Bool("wait", false, "Wait for the returned processes and report their final status")
+	cmd.Flags().Duration("wait-timeout", defaultWaitTimeout, "Give up waiting after this long (with --wait)")
+	cmd.Flags().Bool("stream", false, "Wait, writing NDJSON progress events before the final envelope")
+	cmd.Flags().Duration("heartbeat", defaultHeartbeat, "Interval of heartbeat events (with --stream)")
+}
+
+// waitConfigFromFlags reads the wait flags; ok is false when the command should not wait.
+func waitConfigFromFlags(cmd *cobra.Command) (cfg waitConfig, ok bool) {
+	wait, _ := cmd.Flags().GetBool("wait")
+	cfg.stream, _ = cmd.Flags().GetBool("stream")
+	cfg.timeout, _ = cmd.Flags().GetDuration("wait-timeout")
+	cfg.heartbeat, _ = cmd.Flags().GetDuration("heartbeat")
+	if cfg.timeout <= 0 {
+		cfg.timeout = defaultWaitTimeout
+	}
+	if cfg.heartbeat <= 0 {
+		cfg.heartbeat = defaultHeartbeat
+	}
+	return cfg, wait || cfg.stream
+}
+
+// asyncOutput reports the processes a command started: an async envelope, or with --wait one
+// sync envelope with the final state of every process, polled concurrently. A FAILED process
+// turns it into PROCESS_FAILED, a process still running at --wait-timeout into WAIT_TIMEOUT;
+// both errors carry the same summary in their context. --stream implies --wait and precedes
+// the envelope with progress events. Either way the processes are recorded in the journal.
+func asyncOutput(cmd *cobra.Command, client platform.Client, storagePath string, creds *auth.Credentials,
+	processes []output.ProcessOutput) error {
+	recordProcesses(cmd, storagePath, creds.ProjectID, processes)
+	cfg, wait := waitConfigFromFlags(cmd)
+	if !wait {
+		return output.Async(processes)
+	}
+	return waitAndReport(cmd.Context(), client, processes, cfg)
+}
+
+// waitAndReport waits for processes as configured and writes the final envelope.
+func waitAndReport(ctx context.Context, client platform.Client, processes []output.ProcessOutput, cfg waitConfig) error {
+	start := time.Now()
+	ctx, cancel := context.WithTimeout(ctx, cfg.timeout)
+	defer cancel()
+
+	var stream *progressStream
+	if cfg.stream {
+		stream = newProgressStream(processes, start)
+		stream.startHeartbeat(cfg.heartbeat)
+	}
+	results := waitProcesses(ctx, client, processes, cfg.interval, stream)
+	if stream != nil {
+		stream.stop()
+	}
+
+	summary := map[string]interface{}{
+		"processes": results,
+		"counts":    countByStatus(results),
+		"elapsed":   formatDuration(time.Since(start)),
+	}
+	var failed, running, pollErrors []string
+	for _, r := range results {
+		switch {
+		case r.Status == statusFailed:
+			failed = append(failed, r.ProcessID)
+		case r.Error != "":
+			pollErrors = append(pollErrors, r.ProcessID)
+		case !isTerminalOutput(r.Status):
+			running = append(running, r.ProcessID)
+		}
+	}
+	switch {
+	case len(failed) > 0:
+		return output.Err(platform.ErrProcessFailed,
+			fmt.Sprintf("%d of %d processes failed", len(failed), len(results)),
+			"See failureReason of each process in context.processes", summary)
+	case len(running) > 0:
+		return output.Err(platform.ErrWaitTimeout,
+			fmt.Sprintf("%d of %d processes did not finish within %s", len(running), len(results), cfg.timeout),
+			"Check later with: zaia process <id> --wait", summary)
+	case len(pollErrors) > 0:
+		return output.Err(platform.ErrAPIError,
+			fmt.Sprintf("Could not poll %d of %d processes", len(pollErrors), len(results)),
+			"Check them with: zaia process <id>", summary)
+	}
+	return output.Sync(summary)
+}
+
+func isTerminalOutput(status string) bool {
+	return status == statusFinished || status == statusFailed || status == statusCanceled
+}
+
+// waitProcesses waits for every process concurrently and returns their final states in
+// input order. Hostname, service and action names set by the command are kept.
+// A non-nil stream is told about every polled state.
+func waitProcesses(ctx context.Context, client platform.Client, processes []output.ProcessOutput,
+	interval time.Duration, stream *progressStream) []processResult {
+	results := make([]processResult, len(processes))
+	var wg sync.WaitGroup
+	for i, proc := range processes {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			opts := platform.WaitOptions{Interval: interval}
+			if stream != nil {
+				opts.OnPoll = func(p *platform.Process) { stream.report(mergePolled(proc, p)) }
+			}
+			start := time.Now()
+			p, err := platform.WaitProcess(ctx, client, proc.ProcessID, opts)
+			results[i] = finalResult(proc, p, err, time.Since(start))
+		}()
+	}
+	wg.Wait()
+	return results
+}
+
+// mergePolled updates the process as the command reported it with a polled state.
+// Fields the command left empty (e.g. for bare process IDs) come from the polled state.
+func mergePolled(proc output.ProcessOutput, p *platform.Process) output.ProcessOutput {
+	polled := output.MapProcessToOutput(p, "")
+	proc.Status, proc.Finished, proc.FailureReason = polled.Status, polled.Finished, polled.FailureReason
+	if proc.ActionName == "" {
+		proc.ActionName = polled.ActionName
+	}
+	if proc.ServiceHostname == "" {
+		proc.ServiceHostname = polled.ServiceHostname
+	}
+	if proc.ServiceID == "" {
+		proc.ServiceID = polled.ServiceID
+	}
+	if proc.Created == "" {
+		proc.Created = polled.Created
+	}
+	return proc
+}
+
+// countByStatus counts results by status.
+func countByStatus(results []processResult) map[string]int {
+	counts := make(map[string]int)
+	for _, r := range results {
+		counts[r.Status]++
+	}
+	return counts
+}
+
+// finalResult merges the last polled state p into the process as the command reported it.
+func finalResult(proc output.ProcessOutput, p *platform.Process, err error, waited time.Duration) processResult {
+	r := processResult{ProcessOutput: proc}
+	if p != nil {
+		r.ProcessOutput = mergePolled(proc, p)
+	}
+	if r.Status == "" {
+		r.Status = statusUnknown // never polled, and the command did not report one
+	}
+	if err != nil && !errors.Is(err, context.DeadlineExceeded) {
+		r.Error = err.Error()
+	}
+	r.Duration = formatDuration(waited)
+	if r.Finished != nil {
+		created, errC := time.Parse(time.RFC3339, r.Created)
+		finished, errF := time.Parse(time.RFC3339, *r.Finished)
+		if errC == nil && errF == nil {
+			r.Duration = formatDuration(finished.Sub(created))
+		}
+	}
+	return r
+}
+
+// progressStream writes --stream events: one per status change of a process, and
+// periodic heartbeats listing the processes still being waited for.
+type progressStream struct {
+	start time.Time
+	order []string // process IDs in command order
+
+	mu      sync.Mutex
+	status  map[string]string // last reported status by process ID; "" until first polled
+	stopped bool
+	done    chan struct{} // closed by stop; ends the heartbeat
+	beating sync.WaitGroup
+}
+
+func newProgressStream(processes []output.ProcessOutput, start time.Time) *progressStream {
+	s := &progressStream{
+		start:  start,
+		status: make(map[string]string, len(processes)),
+		done:   make(chan struct{}),
+	}
+	for _, p := range processes {
+		if _, dup := s.status[p.ProcessID]; !dup {
+			s.order = append(s.order, p.ProcessID)
+			s.status[p.ProcessID] = ""
+		}
+	}
+	return s
+}
+
+// statusEvent is the data of an output.EventStatus event.
+type statusEvent struct {
+	output.ProcessOutput
+	PreviousStatus string `json:"previousStatus,omitempty"` // empty on the first poll
+	Elapsed        string `json:"elapsed"`
+}
+
+// report emits a status event when proc's status differs from the last one reported.
+func (s *progressStream) report(proc output.ProcessOutput) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	prev := s.status[proc.ProcessID]
+	if s.stopped || prev == proc.Status {
+		return
+	}
+	s.status[proc.ProcessID] = proc.Status
+	_ = output.Event(output.EventStatus, statusEvent{
+		ProcessOutput:  proc,
+		PreviousStatus: prev,
+		Elapsed:        formatDuration(time.Since(s.start)),
+	})
+}
+
+// startHeartbeat emits a heartbeat event every interval until stop.
+func (s *progressStream) startHeartbeat(every time.Duration) {
+	s.beating.Add(1)
+	go func() {
+		defer s.beating.Done()
+		ticker := time.NewTicker(every)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-s.done:
+				return
+			case <-ticker.C:
+				s.heartbeat()
+			}
+		}
+	}()
+}
+
+func (s *progressStream) heartbeat() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.stopped {
+		return
+	}
+	waiting := []string{}
+	for _, id := range s.order {
+		if !isTerminalOutput(s.status[id]) {
+			waiting = append(waiting, id)
+		}
+	}
+	_ = output.Event(output.EventHeartbeat, map[string]interface{}{
+		"elapsed": formatDuration(time.Since(s.start)),
+		"waiting": waiting,
+	})
+}
+
+// stop ends the stream; no events are written after it returns.
+func (s *progressStream) stop() {
+	s.mu.Lock()
+	if !s.stopped {
+		s.stopped = true
+		close(s.done)
+	}
+	s.mu.Unlock()
+	s.beating.Wait()
+}
+
+// formatDuration rounds d for output: 12.3s, 2m5s.
+func formatDuration(d time.Duration) string {
+	if d >= time.Minute {
+		return d.Round(time.Second).String()
+	}
+	return d.Round(100 * time.Millisecond).String()
+}
@@ -0,0 +1,318 @@
+package commands
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/zeropsio/zaia/internal/output"
+	"github.com/zeropsio/zaia/internal/platform"
+)
+
+// runWithRoot runs args through the root command, where the --wait flags live.
+func runWithRoot(t *testing.T, mock *platform.Mock, args ...string) (map[string]interface{}, error) {
+	t.Helper()
+	storagePath := setupAuthenticatedStorage(t)
+	root := NewRootForTest(RootDeps{StoragePath: storagePath, Client: mock})
+	root.SetArgs(args)
+
+	var stdout bytes.Buffer
+	output.SetWriter(&stdout)
+	defer output.ResetWriter()
+
+	err := root.Execute()
+	var resp map[string]interface{}
+	_ = json.Unmarshal(stdout.Bytes(), &resp)
+	return resp, err
+}
+
+func waitImportMock(procs ...*platform.Process) *platform.Mock {
+	mock := platform.NewMock()
+	result := &platform.ImportResult{ProjectID: "proj-1", ProjectName: "my-app"}
+	for i, name := range []string{"api", "db"} {
+		result.ServiceStacks = append(result.ServiceStacks, platform.ImportedServiceStack{
+			ID:        "s" + string(rune('1'+i)),
+			Name:      name,
+			Processes: []platform.Process{{ID: procs[i].ID, ActionName: "serviceStackCreate", Status: "PENDING"}},
+		})
+		mock.WithProcess(procs[i])
+	}
+	return mock.WithImportResult(result)
+}
+
+const waitImportYml = `services:
+  - hostname: api
+    type: nodejs@22
+  - hostname: db
+    type: postgresql@16
+`
+
+func TestWait_Import_AllFinished(t *testing.T) {
+	finished := "2026-01-29T10:00:12Z"
+	mock := waitImportMock(
+		&platform.Process{ID: "proc-1", Status: "DONE", Created: "2026-01-29T10:00:00Z", Finished: &finished},
+		&platform.Process{ID: "proc-2", Status: "DONE", Created: "2026-01-29T10:00:00Z", Finished: &finished},
+	)
+
+	resp, err := runWithRoot(t, mock, "import", "--content", waitImportYml, "--wait")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp["type"] != "sync" {
+		t.Fatalf("type = %v, want sync", resp["type"])
+	}
+	procs := resp["data"].(map[string]interface{})["processes"].([]interface{})
+	if len(procs) != 2 {
+		t.Fatalf("processes len = %d, want 2", len(procs))
+	}
+	for i, host := range []string{"api", "db"} {
+		p := procs[i].(map[string]interface{})
+		if p["serviceHostname"] != host || p["actionName"] != "import" {
+			t.Errorf("process %d = %v, want %s import", i, p, host)
+		}
+		if p["status"] != "FINISHED" || p["duration"] != "12s" {
+			t.Errorf("status = %v, duration = %v, want FINISHED 12s", p["status"], p["duration"])
+		}
+	}
+}
+
+func TestWait_Import_OneFailed(t *testing.T) {
+	reason := "Build failed"
+	mock := waitImportMock(
+		&platform.Process{ID: "proc-1", Status: "DONE"},
+		&platform.Process{ID: "proc-2", Status: "FAILED", FailReason: &reason},
+	)
+
+	resp, err := runWithRoot(t, mock, "import", "--content", waitImportYml, "--wait")
+	if err == nil {
+		t.Fatal("expected error")
+	}
+	if resp["code"] != "PROCESS_FAILED" {
+		t.Fatalf("code = %v, want PROCESS_FAILED", resp["code"])
+	}
+	procs := resp["context"].(map[string]interface{})["processes"].([]interface{})
+	if procs[0].(map[string]interface{})["status"] != "FINISHED" {
+		t.Errorf("proc-1 = %v, want FINISHED", procs[0])
+	}
+	failed := procs[1].(map[string]interface{})
+	if failed["status"] != "FAILED" || failed["failureReason"] != "Build failed" {
+		t.Errorf("proc-2 = %v, want FAILED with reason", failed)
+	}
+}
+
+func TestWait_MappedFinished(t *testing.T) {
+	// ZeropsClient reports DONE as FINISHED; --wait must return instead of timing out.
+	mock := platform.NewMock().
+		WithServices([]platform.ServiceStack{{ID: "s1", Name: "api", Status: "ACTIVE"}}).
+		WithProcess(&platform.Process{ID: "proc-restart-s1", ActionName: "restart", Status: "FINISHED"})
+
+	resp, err := runWithRoot(t, mock, "restart", "--service", "api", "--wait", "--wait-timeout", "5s")
+	if err != nil {
+		t.Fatalf("err = %v, resp = %v", err, resp)
+	}
+	p := resp["data"].(map[string]interface{})["processes"].([]interface{})[0].(map[string]interface{})
+	if p["status"] != "FINISHED" {
+		t.Errorf("process = %v, want FINISHED", p)
+	}
+}
+
+func TestWait_Timeout(t *testing.T) {
+	mock := platform.NewMock().
+		WithServices([]platform.ServiceStack{{ID: "s1", Name: "api", Status: "ACTIVE"}}).
+		WithProcess(&platform.Process{ID: "proc-start-s1", ActionName: "start", Status: "RUNNING"})
+
+	resp, err := runWithRoot(t, mock, "start", "--service", "api", "--wait", "--wait-timeout", "20ms")
+	if err == nil {
+		t.Fatal("expected error")
+	}
+	if resp["code"] != "WAIT_TIMEOUT" {
+		t.Fatalf("code = %v, want WAIT_TIMEOUT", resp["code"])
+	}
+	p := resp["context"].(map[string]interface{})["processes"].([]interface{})[0].(map[string]interface{})
+	if p["status"] != "RUNNING" || p["serviceHostname"] != "api" {
+		t.Errorf("process = %v, want RUNNING api", p)
+	}
+}
+
+func TestWait_PollError(t *testing.T) {
+	// proc-stop-s1 is not known to the mock, so polling it fails.
+	mock := platform.NewMock().
+		WithServices([]platform.ServiceStack{{ID: "s1", Name: "api", Status: "ACTIVE"}})
+
+	resp, err := runWithRoot(t, mock, "stop", "--service", "api", "--wait")
+	if err == nil {
+		t.Fatal("expected error")
+	}
+	if resp["code"] != "API_ERROR" {
+		t.Errorf("code = %v, want API_ERROR", resp["code"])
+	}
+}
+
+func TestWait_ProcessWaitTimeout(t *testing.T) {
+	mock := platform.NewMock().
+		WithProcess(&platform.Process{ID: "proc-1", ActionName: "restart", Status: "RUNNING"})
+
+	resp, err := runWithRoot(t, mock, "process", "proc-1", "--wait", "--wait-timeout", "20ms")
+	if err == nil {
+		t.Fatal("expected error")
+	}
+	if resp["code"] != "WAIT_TIMEOUT" {
+		t.Errorf("code = %v, want WAIT_TIMEOUT", resp["code"])
+	}
+
+	// The timeout has one flag name; there is no --timeout alias.
+	if _, err := runWithRoot(t, mock, "process", "proc-1", "--wait", "--timeout", "20ms"); err == nil {
+		t.Error("expected --timeout to be rejected")
+	}
+}
+
+func TestWait_FlagsOnlyOnAsyncCommands(t *testing.T) {
+	for _, args := range [][]string{
+		{"validate", "--content", "zerops: []", "--wait"},
+		{"search", "postgres", "--stream"},
+		{"catalog", "--wait-timeout", "1m"},
+		{"status", "--heartbeat", "1s"},
+	} {
+		storagePath := setupAuthenticatedStorage(t)
+		root := NewRootForTest(RootDeps{StoragePath: storagePath, Client: platform.NewMock()})
+		root.SetArgs(args)
+
+		var stdout bytes.Buffer
+		output.SetWriter(&stdout)
+		err := Execute(root)
+		output.ResetWriter()
+
+		var resp map[string]interface{}
+		_ = json.Unmarshal(stdout.Bytes(), &resp)
+		if err == nil || resp["code"] != "INVALID_USAGE" {
+			t.Errorf("%v: code = %v, want INVALID_USAGE", args, resp["code"])
+		}
+	}
+}
+
+// streamLines splits NDJSON output into decoded lines.
+func streamLines(t *testing.T, out []byte) []map[string]interface{} {
+	t.Helper()
+	var lines []map[string]interface{}
+	for _, line := range bytes.Split(bytes.TrimSpace(out), []byte("\n")) {
+		var m map[string]interface{}
+		if err := json.Unmarshal(line, &m); err != nil {
+			t.Fatalf("line %q is not JSON: %v", line, err)
+		}
+		lines = append(lines, m)
+	}
+	return lines
+}
+
+func TestStream_StatusTransitions(t *testing.T) {
+	mock := platform.NewMock().
+		WithProcess(&platform.Process{ID: "proc-1", Status: "PENDING"}).
+		WithProcessProgress("proc-1", "PENDING", "RUNNING", "RUNNING", "DONE").
+		WithProcess(&platform.Process{ID: "proc-2", Status: "PENDING"}).
+		WithProcessProgress("proc-2", "RUNNING", "FINISHED")
+	processes := []output.ProcessOutput{
+		{ProcessID: "proc-1", ActionName: "import", ServiceHostname: "api", Status: "PENDING"},
+		{ProcessID: "proc-2", ActionName: "import", ServiceHostname: "db", Status: "PENDING"},
+	}
+
+	var stdout bytes.Buffer
+	output.SetWriter(&stdout)
+	defer output.ResetWriter()
+
+	err := waitAndReport(context.Background(), mock, processes,
+		waitConfig{timeout: time.Second, interval: time.Millisecond, stream: true, heartbeat: time.Hour})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	lines := streamLines(t, stdout.Bytes())
+	transitions := map[string][]string{}
+	for _, l := range lines[:len(lines)-1] {
+		if l["type"] != "event" || l["event"] != output.EventStatus || l["time"] == "" {
+			t.Fatalf("line = %v, want status event", l)
+		}
+		data := l["data"].(map[string]interface{})
+		id := data["processId"].(string)
+		transitions[id] = append(transitions[id], data["status"].(string))
+		if data["actionName"] != "import" {
+			t.Errorf("event = %v, want the command's actionName", data)
+		}
+	}
+	if got := strings.Join(transitions["proc-1"], ","); got != "PENDING,RUNNING,FINISHED" {
+		t.Errorf("proc-1 transitions = %s", got)
+	}
+	if got := strings.Join(transitions["proc-2"], ","); got != "RUNNING,FINISHED" {
+		t.Errorf("proc-2 transitions = %s", got)
+	}
+	if last := lines[len(lines)-1]; last["type"] != "sync" {
+		t.Errorf("last line = %v, want sync summary", last)
+	}
+}
+
+func TestStream_HeartbeatAndTimeout(t *testing.T) {
+	mock := platform.NewMock().
+		WithServices([]platform.ServiceStack{{ID: "s1", Name: "api", Status: "ACTIVE"}}).
+		WithProcess(&platform.Process{ID: "proc-restart-s1", ActionName: "restart", Status: "RUNNING"})
+
+	storagePath := setupAuthenticatedStorage(t)
+	root := NewRootForTest(RootDeps{StoragePath: storagePath, Client: mock})
+	root.SetArgs([]string{"restart", "--service", "api", "--stream", "--wait-timeout", "80ms", "--heartbeat", "10ms"})
+
+	var stdout bytes.Buffer
+	output.SetWriter(&stdout)
+	defer output.ResetWriter()
+
+	if err := root.Execute(); err == nil {
+		t.Fatal("expected WAIT_TIMEOUT")
+	}
+
+	lines := streamLines(t, stdout.Bytes())
+	var heartbeats int
+	for _, l := range lines[:len(lines)-1] {
+		if l["event"] != output.EventHeartbeat {
+			continue
+		}
+		heartbeats++
+		waiting := l["data"].(map[string]interface{})["waiting"].([]interface{})
+		if len(waiting) != 1 || waiting[0] != "proc-restart-s1" {
+			t.Errorf("waiting = %v, want [proc-restart-s1]", waiting)
+		}
+	}
+	if heartbeats == 0 {
+		t.Error("expected heartbeat events")
+	}
+	if last := lines[len(lines)-1]; last["code"] != "WAIT_TIMEOUT" {
+		t.Errorf("last line = %v, want WAIT_TIMEOUT", last)
+	}
+}
+
+func TestStream_Process(t *testing.T) {
+	mock := platform.NewMock().
+		WithProcess(&platform.Process{ID: "proc-1", ActionName: "restart", Status: "PENDING"}).
+		WithProcessProgress("proc-1", "PENDING", "DONE")
+
+	storagePath := setupAuthenticatedStorage(t)
+	root := NewRootForTest(RootDeps{StoragePath: storagePath, Client: mock})
+	root.SetArgs([]string{"process", "proc-1", "--stream", "--interval", "1ms"})
+
+	var stdout bytes.Buffer
+	output.SetWriter(&stdout)
+	defer output.ResetWriter()
+
+	if err := root.Execute(); err != nil {
+		t.Fatal(err)
+	}
+	lines := streamLines(t, stdout.Bytes())
+	if len(lines) != 3 {
+		t.Fatalf("lines = %v, want PENDING, FINISHED events and the final envelope", lines)
+	}
+	if lines[1]["data"].(map[string]interface{})["previousStatus"] != "PENDING" {
+		t.Errorf("second event = %v, want previousStatus PENDING", lines[1])
+	}
+	if lines[2]["type"] != "sync" {
+		t.Errorf("last line = %v, want sync", lines[2])
+	}
+}
@@ -0,0 +1,219 @@
+// Package journal records the processes zaia initiated, so they can be found and
+// resumed after the caller lost track of them.
+package journal
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// FileName is the journal's file name, next to zaia.data.
+const FileName = "zaia.journal"
+
+// MaxEntries caps the journal; Append and Update keep only the newest entries.
+const MaxEntries = 1000
+
+// Writers of the journal take a lock file next to it, so that concurrent zaia
+// processes don't lose each other's entries.
+const (
+	lockTimeout = 5 * time.Second  // give up waiting for the lock
+	lockStale   = 30 * time.Second // a lock older than this was left by a crashed process
+	lockPoll    = 10 * time.Millisecond
+)
+
+// Entry is one initiated process.
+type Entry struct {
+	ProcessID       string  `json:"processId"`
+	ActionName      string  `json:"actionName"`
+	ServiceHostname string  `json:"serviceHostname,omitempty"`
+	ServiceID       string  `json:"serviceId,omitempty"`
+	ProjectID       string  `json:"projectId"`
+	Command         string  `json:"command"`           // command line that initiated it, values elided
+	Initiated       string  `json:"initiated"`         // RFC 3339, when zaia started it
+	Status          string  `json:"status"`            // last known status
+	Checked         string  `json:"checked,omitempty"` // RFC 3339, when Status was last polled
+	Finished        *string `json:"finished,omitempty"`
+	FailureReason   *string `json:"failureReason,omitempty"`
+}
+
+// Journal handles reading and writing the journal file: one JSON entry per line,
+// oldest first.
+type Journal struct {
+	filePath string
+}
+
+// New creates a journal handler for the file at filePath.
+func New(filePath string) *Journal {
+	return &Journal{filePath: filePath}
+}
+
+// NextTo creates a journal handler for the journal next to the data file dataFilePath.
+func NextTo(dataFilePath string) *Journal {
+	return New(filepath.Join(filepath.Dir(dataFilePath), FileName))
+}
+
+// FilePath returns the journal file path.
+func (j *Journal) FilePath() string {
+	return j.filePath
+}
+
+// Append adds entries at the end of the journal. When that would exceed MaxEntries,
+// the journal is rewritten with only the newest entries.
+func (j *Journal) Append(entries ...Entry) error {
+	if len(entries) == 0 {
+		return nil
+	}
+	return j.withLock(func() error {
+		existing, err := j.Load()
+		if err != nil {
+			return err
+		}
+		if len(existing)+len(entries) > MaxEntries {
+			return j.save(append(existing, entries...))
+		}
+
+		var buf bytes.Buffer
+		enc := json.NewEncoder(&buf)
+		for _, e := range entries {
+			if err := enc.Encode(e); err != nil {
+				return fmt.Errorf("failed to encode entry: %w", err)
+			}
+		}
+
+		f, err := os.OpenFile(j.filePath, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0600)
+		if err != nil {
+			return fmt.Errorf("failed to open %s: %w", j.filePath, err)
+		}
+		if _, err := f.Write(buf.Bytes()); err != nil {
+			f.Close()
+			return fmt.Errorf("failed to write %s: %w", j.filePath, err)
+		}
+		return f.Close()
+	})
+}
+
+// Update replaces the journal with what fn returns for its current entries, holding the
+// lock from reading to writing. fn should be quick: other zaia processes wait for it.
+// Returns the entries saved.
+func (j *Journal) Update(fn func([]Entry) []Entry) ([]Entry, error) {
+	var saved []Entry
+	err := j.withLock(func() error {
+		entries, err := j.Load()
+		if err != nil {
+			return err
+		}
+		saved = newest(fn(entries))
+		return j.save(saved)
+	})
+	return saved, err
+}
+
+// Load reads all entries, oldest first. Returns no entries if the file doesn't exist.
+// Lines that are not valid entries (e.g. a write cut short) are skipped.
+func (j *Journal) Load() ([]Entry, error) {
+	f, err := os.Open(j.filePath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read %s: %w", j.filePath, err)
+	}
+	defer f.Close()
+
+	var entries []Entry
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		var e Entry
+		if err := json.Unmarshal(scanner.Bytes(), &e); err != nil || e.ProcessID == "" {
+			continue
+		}
+		entries = append(entries, e)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", j.filePath, err)
+	}
+	return entries, nil
+}
+
+// save replaces the journal with entries atomically (write to .new, then rename), keeping
+// at most MaxEntries of the newest. The caller holds the lock.
+func (j *Journal) save(entries []Entry) error {
+	entries = newest(entries)
+
+	newPath := j.filePath + ".new"
+	f, err := os.OpenFile(newPath, os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to create temp file: %w", err)
+	}
+
+	w := bufio.NewWriter(f)
+	enc := json.NewEncoder(w)
+	for _, e := range entries {
+		if err := enc.Encode(e); err != nil {
+			f.Close()
+			os.Remove(newPath)
+			return fmt.Errorf("failed to write entry: %w", err)
+		}
+	}
+	if err := w.Flush(); err != nil {
+		f.Close()
+		os.Remove(newPath)
+		return fmt.Errorf("failed to write entries: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		os.Remove(newPath)
+		return fmt.Errorf("failed to close temp file: %w", err)
+	}
+
+	if err := os.Rename(newPath, j.filePath); err != nil {
+		os.Remove(newPath)
+		return fmt.Errorf("failed to rename: %w", err)
+	}
+	return nil
+}
+
+// newest returns the last MaxEntries of entries.
+func newest(entries []Entry) []Entry {
+	if len(entries) > MaxEntries {
+		return entries[len(entries)-MaxEntries:]
+	}
+	return entries
+}
+
+// withLock runs fn holding the journal's lock file. A lock left behind by a crashed
+// process is taken over once it is older than lockStale.
+func (j *Journal) withLock(fn func() error) error {
+	dir := filepath.Dir(j.filePath)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("failed to create directory %s: %w", dir, err)
+	}
+
+	lockPath := j.filePath + ".lock"
+	deadline := time.Now().Add(lockTimeout)
+	for {
+		f, err := os.OpenFile(lockPath, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+		if err == nil {
+			f.Close()
+			break
+		}
+		if !os.IsExist(err) {
+			return fmt.Errorf("failed to lock %s: %w", j.filePath, err)
+		}
+		if info, err := os.Stat(lockPath); err == nil && time.Since(info.ModTime()) > lockStale {
+			os.Remove(lockPath)
+			continue
+		}
+		if time.Now().After(deadline) {
+			return fmt.Errorf("failed to lock %s: %s is held by another zaia process", j.filePath, lockPath)
+		}
+		time.Sleep(lockPoll)
+	}
+	defer os.Remove(lockPath)
+	return fn()
+}
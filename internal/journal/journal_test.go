@@ -0,0 +1,159 @@
+package journal
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestJournal_AppendAndLoad(t *testing.T) {
+	dir := t.TempDir()
+	j := NextTo(filepath.Join(dir, "zaia.data"))
+	if j.FilePath() != filepath.Join(dir, FileName) {
+		t.Errorf("FilePath() = %q, want next to zaia.data", j.FilePath())
+	}
+
+	if err := j.Append(Entry{ProcessID: "p1", ProjectID: "proj-1", Status: "PENDING"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := j.Append(Entry{ProcessID: "p2", ProjectID: "proj-1"}, Entry{ProcessID: "p3", ProjectID: "proj-2"}); err != nil {
+		t.Fatal(err)
+	}
+
+	entries, err := j.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 3 || entries[0].ProcessID != "p1" || entries[2].ProjectID != "proj-2" {
+		t.Errorf("entries = %+v, want p1, p2, p3 in order", entries)
+	}
+}
+
+func TestJournal_LoadMissing(t *testing.T) {
+	entries, err := New(filepath.Join(t.TempDir(), FileName)).Load()
+	if err != nil || len(entries) != 0 {
+		t.Errorf("Load() = %v, %v; want no entries", entries, err)
+	}
+}
+
+func TestJournal_LoadSkipsBrokenLines(t *testing.T) {
+	path := filepath.Join(t.TempDir(), FileName)
+	content := `{"processId":"p1","projectId":"proj-1"}
+not json
+{"projectId":"no-id"}
+{"processId":"p2","proj`
+	if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	entries, err := New(path).Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 || entries[0].ProcessID != "p1" {
+		t.Errorf("entries = %+v, want only p1", entries)
+	}
+}
+
+func TestJournal_UpdateKeepsNewest(t *testing.T) {
+	j := New(filepath.Join(t.TempDir(), FileName))
+	entries := make([]Entry, MaxEntries+5)
+	for i := range entries {
+		entries[i] = Entry{ProcessID: fmt.Sprintf("p%d", i)}
+	}
+	if _, err := j.Update(func([]Entry) []Entry { return entries }); err != nil {
+		t.Fatal(err)
+	}
+
+	loaded, err := j.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(loaded) != MaxEntries || loaded[0].ProcessID != "p5" {
+		t.Errorf("loaded %d entries starting at %s, want %d from p5", len(loaded), loaded[0].ProcessID, MaxEntries)
+	}
+	if _, err := os.Stat(j.FilePath() + ".new"); !os.IsNotExist(err) {
+		t.Error("temp file left behind")
+	}
+}
+
+func TestJournal_AppendKeepsNewest(t *testing.T) {
+	j := New(filepath.Join(t.TempDir(), FileName))
+	full := make([]Entry, MaxEntries)
+	for i := range full {
+		full[i] = Entry{ProcessID: fmt.Sprintf("p%d", i)}
+	}
+	if _, err := j.Update(func([]Entry) []Entry { return full }); err != nil {
+		t.Fatal(err)
+	}
+	for i := MaxEntries; i < MaxEntries+3; i++ {
+		if err := j.Append(Entry{ProcessID: fmt.Sprintf("p%d", i)}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	loaded, err := j.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(loaded) != MaxEntries || loaded[0].ProcessID != "p3" {
+		t.Errorf("loaded %d entries starting at %s, want %d from p3", len(loaded), loaded[0].ProcessID, MaxEntries)
+	}
+}
+
+func TestJournal_ConcurrentWriters(t *testing.T) {
+	j := New(filepath.Join(t.TempDir(), FileName))
+	if err := j.Append(Entry{ProcessID: "p0", Status: "RUNNING"}); err != nil {
+		t.Fatal(err)
+	}
+
+	var wg sync.WaitGroup
+	for w := range 4 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range 20 {
+				if err := j.Append(Entry{ProcessID: fmt.Sprintf("w%d-%d", w, i)}); err != nil {
+					t.Error(err)
+				}
+				if _, err := j.Update(func(entries []Entry) []Entry {
+					entries[0].Status = "FINISHED"
+					return entries
+				}); err != nil {
+					t.Error(err)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	loaded, err := j.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(loaded) != 81 || loaded[0].Status != "FINISHED" {
+		t.Errorf("loaded %d entries, first %+v; want 81 with p0 FINISHED", len(loaded), loaded[0])
+	}
+	if _, err := os.Stat(j.FilePath() + ".lock"); !os.IsNotExist(err) {
+		t.Error("lock file left behind")
+	}
+}
+
+func TestJournal_StaleLock(t *testing.T) {
+	j := New(filepath.Join(t.TempDir(), FileName))
+	lockPath := j.FilePath() + ".lock"
+	if err := os.WriteFile(lockPath, nil, 0600); err != nil {
+		t.Fatal(err)
+	}
+	old := time.Now().Add(-2 * lockStale)
+	if err := os.Chtimes(lockPath, old, old); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := j.Append(Entry{ProcessID: "p1"}); err != nil {
+		t.Fatalf("stale lock not taken over: %v", err)
+	}
+}
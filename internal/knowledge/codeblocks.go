@@ -0,0 +1,59 @@
+package knowledge
+
+import "strings"
+
+// templateInfo marks a fenced block as a template with placeholders ("```yaml template"),
+// which is shown for its shape and not validated.
+const templateInfo = "template"
+
+// codeBlock is a fenced code block of a markdown document.
+type codeBlock struct {
+	Lang    string // first word of the info string: yaml, bash, ...
+	Info    string // full info string after the backticks
+	Line    int    // 1-based line of the first content line
+	Content string
+}
+
+// isTemplate reports whether the block is marked as a template.
+func (b codeBlock) isTemplate() bool {
+	fields := strings.Fields(b.Info)
+	for _, f := range fields[min(1, len(fields)):] {
+		if f == templateInfo {
+			return true
+		}
+	}
+	return false
+}
+
+// codeBlocks returns the fenced code blocks of content in order. An unterminated
+// fence runs to the end of the document.
+func codeBlocks(content string) []codeBlock {
+	var blocks []codeBlock
+	var cur *codeBlock
+	var body strings.Builder
+	for i, line := range strings.Split(content, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if !strings.HasPrefix(trimmed, "```") {
+			if cur != nil {
+				body.WriteString(line)
+				body.WriteByte('\n')
+			}
+			continue
+		}
+		if cur != nil {
+			cur.Content = body.String()
+			blocks = append(blocks, *cur)
+			cur = nil
+			continue
+		}
+		info := strings.TrimSpace(strings.TrimPrefix(trimmed, "```"))
+		lang, _, _ := strings.Cut(info, " ")
+		cur = &codeBlock{Lang: strings.ToLower(lang), Info: info, Line: i + 2}
+		body.Reset()
+	}
+	if cur != nil {
+		cur.Content = body.String()
+		blocks = append(blocks, *cur)
+	}
+	return blocks
+}
@@ -2,10 +2,19 @@ package knowledge
 
 import (
 	"embed"
+	"fmt"
 	"io/fs"
+	"os"
+	"path/filepath"
 	"strings"
 )
 
+// Document URI namespaces.
+const (
+	embeddedScheme = "zerops://docs/"
+	externalScheme = "team://"
+)
+
 //go:embed embed/**/*.md
 var contentFS embed.FS
 
@@ -18,6 +27,36 @@ type Document struct {
 	TLDR        string   // One-sentence summary
 	Content     string   // Full markdown content
 	Description string   // TL;DR or first paragraph
+	Overrides   string   // zerops://docs/... URI this external document replaces, if any
+	Sections    []Section
+
+	// Metadata from the optional YAML frontmatter.
+	Category     string            // services (first URI path segment unless set in frontmatter)
+	ServiceTypes []string          // [postgresql]: catalog types the document is about
+	MinVersions  map[string]string // postgresql → 14: lowest version the document applies to
+	LastReviewed string            // 2026-01-15
+
+	frontmatter    frontmatter // as written, for lint
+	frontmatterErr error       // reported by lint; the document is parsed without its frontmatter
+	bodyLine       int         // lines of frontmatter before Content, for file-relative line numbers
+}
+
+// Section is a heading-delimited part of a Document, addressed as <uri>#<anchor>.
+type Section struct {
+	Anchor  string // multi-service-example
+	Heading string // Multi-Service Example
+	Level   int    // 1 for the title, 2 for ##, 3 for ###
+	Content string // markdown from the heading line up to the next heading
+}
+
+// Section returns the section with the given anchor, or nil.
+func (d *Document) Section(anchor string) *Section {
+	for i := range d.Sections {
+		if d.Sections[i].Anchor == anchor {
+			return &d.Sections[i]
+		}
+	}
+	return nil
 }
 
 // loadFromEmbedded walks the embedded filesystem and parses all markdown documents.
@@ -32,32 +71,161 @@ func loadFromEmbedded() map[string]*Document {
 			return nil //nolint:nilerr // intentional: continue walking on individual file errors
 		}
 		doc := parseDocument(path, string(data))
+		if doc.Category == "" {
+			doc.Category = uriCategory(doc.URI)
+		}
 		docs[doc.URI] = doc
 		return nil
 	})
 	return docs
 }
 
+// loadFromDir parses all markdown documents under dir into the team:// namespace.
+// The URI is the path relative to dir without the .md extension (runbooks/deploy.md → team://runbooks/deploy).
+func loadFromDir(dir string) (map[string]*Document, error) {
+	info, err := os.Stat(dir)
+	if err != nil {
+		return nil, err
+	}
+	if !info.IsDir() {
+		return nil, fmt.Errorf("%s is not a directory", dir)
+	}
+	docs := make(map[string]*Document)
+	err = filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || !strings.HasSuffix(path, ".md") {
+			return nil
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		doc := parseDocument(path, string(data))
+		doc.URI = externalScheme + strings.TrimSuffix(filepath.ToSlash(rel), ".md")
+		if doc.Category == "" {
+			doc.Category = uriCategory(doc.URI)
+		}
+		docs[doc.URI] = doc
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return docs, nil
+}
+
+// parseDocument parses a markdown document with optional YAML frontmatter. Content is the
+// markdown after the frontmatter; frontmatter fields take precedence over headings. Category
+// is only set from frontmatter, the loaders default it from the URI.
 func parseDocument(path, content string) *Document {
-	uri := pathToURI(path)
-	title := extractTitle(content)
-	keywords := extractKeywords(content)
-	tldr := extractTLDR(content)
+	yml, body, bodyLine, hasFrontmatter := splitFrontmatter(content)
+	tldr := extractTLDR(body)
 
 	desc := tldr
 	if desc == "" {
-		desc = extractFirstParagraph(content)
+		desc = extractFirstParagraph(body)
 	}
 
-	return &Document{
+	doc := &Document{
 		Path:        path,
-		URI:         uri,
-		Title:       title,
-		Keywords:    keywords,
+		URI:         pathToURI(path),
+		Title:       extractTitle(body),
+		Keywords:    extractKeywords(body),
 		TLDR:        tldr,
-		Content:     content,
+		Content:     body,
 		Description: desc,
+		Sections:    splitSections(body),
+		bodyLine:    bodyLine,
 	}
+	if hasFrontmatter {
+		fm, err := parseFrontmatter(yml)
+		if err != nil {
+			doc.frontmatterErr = err
+		} else {
+			doc.frontmatter = fm
+			applyFrontmatter(doc, fm)
+		}
+	}
+	return doc
+}
+
+// splitSections splits content at #, ## and ### headings outside code fences.
+// Text before the first heading belongs to no section.
+func splitSections(content string) []Section {
+	var sections []Section
+	var cur *Section
+	var body strings.Builder
+	anchors := make(map[string]int)
+	inFence := false
+
+	flush := func() {
+		if cur != nil {
+			cur.Content = body.String()
+			sections = append(sections, *cur)
+		}
+		body.Reset()
+	}
+
+	for _, line := range strings.SplitAfter(content, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "```") {
+			inFence = !inFence
+		}
+		if level, heading := headingOf(trimmed); !inFence && level > 0 {
+			flush()
+			cur = &Section{Anchor: uniqueAnchor(slugify(heading), anchors), Heading: heading, Level: level}
+		}
+		body.WriteString(line)
+	}
+	flush()
+	return sections
+}
+
+// headingOf returns the level and text of a #, ## or ### heading line, or 0.
+func headingOf(line string) (int, string) {
+	for level := 3; level >= 1; level-- {
+		prefix := strings.Repeat("#", level) + " "
+		if strings.HasPrefix(line, prefix) {
+			return level, strings.TrimSpace(strings.TrimPrefix(line, prefix))
+		}
+	}
+	return 0, ""
+}
+
+// slugify returns the GitHub-style anchor of a heading ("TL;DR" → "tldr").
+func slugify(heading string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(heading) {
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9', r == '-', r == '_':
+			b.WriteRune(r)
+		case r == ' ':
+			b.WriteByte('-')
+		}
+	}
+	return b.String()
+}
+
+// uniqueAnchor suffixes repeated anchors with -1, -2, ... as GitHub does.
+func uniqueAnchor(anchor string, seen map[string]int) string {
+	n, dup := seen[anchor]
+	seen[anchor] = n + 1
+	if !dup {
+		return anchor
+	}
+	return fmt.Sprintf("%s-%d", anchor, n)
+}
+
+// SplitFragment splits "zerops://docs/x#anchor" into the document URI and the anchor.
+func SplitFragment(uri string) (docURI, anchor string) {
+	docURI, anchor, _ = strings.Cut(uri, "#")
+	return docURI, anchor
 }
 
 func pathToURI(fsPath string) string {
@@ -65,11 +233,22 @@ func pathToURI(fsPath string) string {
 	rel := strings.TrimPrefix(fsPath, "embed/")
 	// Remove .md extension
 	rel = strings.TrimSuffix(rel, ".md")
-	return "zerops://docs/" + rel
+	return embeddedScheme + rel
+}
+
+// uriCategory returns the first path segment of a document URI (zerops://docs/services/x →
+// services), or "" for a document at the top level.
+func uriCategory(uri string) string {
+	rel := strings.TrimPrefix(strings.TrimPrefix(uri, embeddedScheme), externalScheme)
+	category, _, nested := strings.Cut(rel, "/")
+	if !nested {
+		return ""
+	}
+	return category
 }
 
 func uriToPath(uri string) string {
-	rel := strings.TrimPrefix(uri, "zerops://docs/")
+	rel := strings.TrimPrefix(uri, embeddedScheme)
 	return "embed/" + rel + ".md"
 }
 
@@ -0,0 +1,267 @@
+package knowledge
+
+import (
+	"bufio"
+	"bytes"
+	_ "embed"
+	"encoding/binary"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode"
+
+	porterstemmer "github.com/blevesearch/go-porterstemmer"
+)
+
+// WordModel turns words into vectors. A text vector is the IDF-weighted sum of the
+// vectors of its words; texts are compared by cosine similarity.
+type WordModel interface {
+	Name() string
+	Dim() int
+	// Add adds weight times the vector of word to vec and reports whether the word is known.
+	Add(vec []float32, word string, weight float32) bool
+}
+
+// --- Built-in model ---
+
+//go:generate go test -run ^TestGenerateWordVectors$ -generate-wordvec .
+
+// builtinVectors holds the built-in word vectors, trained ahead of time by
+// TestGenerateWordVectors: the truncated SVD of the positive PMI co-occurrence matrix of
+// the embedded documents and the doc comments of the Go standard library. Words used in
+// the same contexts ("smtp", "email"; "internet", "ipv4") get similar vectors. The file is
+// a header line "zaia-wordvec 1 <dim> <count> <docs digest>" followed by count records of
+// a length-prefixed word stem, a little-endian float32 scale and dim int8 components.
+//
+//go:embed model/wordvec.bin
+var builtinVectors []byte
+
+// stemVectors is a model keyed by word stems, so "emails" and "email" share a vector.
+type stemVectors struct {
+	name    string
+	dim     int
+	digest  string // digest of the documents the vectors were trained on
+	vectors map[string][]float32
+}
+
+// builtinModel decodes the built-in vectors on first use.
+var builtinModel = sync.OnceValue(func() *stemVectors {
+	m, err := decodeWordVectors("builtin", builtinVectors)
+	if err != nil {
+		panic("knowledge: corrupt built-in word vectors: " + err.Error())
+	}
+	return m
+})
+
+// decodeWordVectors parses the built-in vector format.
+func decodeWordVectors(name string, data []byte) (*stemVectors, error) {
+	header, body, ok := bytes.Cut(data, []byte("\n"))
+	fields := strings.Fields(string(header))
+	if !ok || len(fields) != 5 || fields[0] != "zaia-wordvec" || fields[1] != "1" {
+		return nil, fmt.Errorf("unknown header %q", header)
+	}
+	dim, err1 := strconv.Atoi(fields[2])
+	count, err2 := strconv.Atoi(fields[3])
+	if err1 != nil || err2 != nil || dim <= 0 || count < 0 {
+		return nil, fmt.Errorf("bad dimensions in header %q", header)
+	}
+	m := &stemVectors{name: name, dim: dim, digest: fields[4], vectors: make(map[string][]float32, count)}
+	for range count {
+		if len(body) < 1 || len(body) < 1+int(body[0])+4+dim {
+			return nil, fmt.Errorf("truncated after %d of %d words", len(m.vectors), count)
+		}
+		n := int(body[0])
+		stem := string(body[1 : 1+n])
+		scale := math.Float32frombits(binary.LittleEndian.Uint32(body[1+n:]))
+		vec := make([]float32, dim)
+		for i, b := range body[1+n+4 : 1+n+4+dim] {
+			vec[i] = float32(int8(b)) * scale
+		}
+		m.vectors[stem] = vec
+		body = body[1+n+4+dim:]
+	}
+	if len(body) != 0 {
+		return nil, fmt.Errorf("%d trailing bytes", len(body))
+	}
+	return m, nil
+}
+
+func (m *stemVectors) Name() string { return m.name }
+func (m *stemVectors) Dim() int     { return m.dim }
+
+func (m *stemVectors) Add(vec []float32, word string, weight float32) bool {
+	v, ok := m.vectors[stemWord(word)]
+	if !ok {
+		return false
+	}
+	for i, x := range v {
+		vec[i] += weight * x
+	}
+	return true
+}
+
+// --- Word vectors loaded from a file ---
+
+// wordVectors is a pre-trained model loaded from a GloVe or word2vec text file.
+type wordVectors struct {
+	name    string
+	dim     int
+	vectors map[string][]float32
+}
+
+// LoadWordVectors loads word vectors in GloVe or word2vec text format ("word v1 v2 ...",
+// word2vec files start with a "count dim" header). Large vocabularies are slow to load on
+// every call; a file trimmed to the most frequent words works best.
+func LoadWordVectors(path string) (WordModel, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	m := &wordVectors{name: filepath.Base(path), vectors: make(map[string][]float32)}
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for lineNo := 1; scanner.Scan(); lineNo++ {
+		fields := strings.Fields(scanner.Text())
+		if lineNo == 1 && len(fields) == 2 {
+			continue // word2vec header
+		}
+		if len(fields) < 2 {
+			continue
+		}
+		if m.dim == 0 {
+			m.dim = len(fields) - 1
+		}
+		if len(fields)-1 != m.dim {
+			return nil, fmt.Errorf("%s:%d: expected %d values, got %d", path, lineNo, m.dim, len(fields)-1)
+		}
+		vec := make([]float32, m.dim)
+		for i, s := range fields[1:] {
+			v, err := strconv.ParseFloat(s, 32)
+			if err != nil {
+				return nil, fmt.Errorf("%s:%d: %w", path, lineNo, err)
+			}
+			vec[i] = float32(v)
+		}
+		m.vectors[strings.ToLower(fields[0])] = vec
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if len(m.vectors) == 0 {
+		return nil, fmt.Errorf("%s: no word vectors found", path)
+	}
+	return m, nil
+}
+
+func (m *wordVectors) Name() string { return m.name }
+func (m *wordVectors) Dim() int     { return m.dim }
+
+func (m *wordVectors) Add(vec []float32, word string, weight float32) bool {
+	v, ok := m.vectors[word]
+	if !ok {
+		return false
+	}
+	for i, x := range v {
+		vec[i] += weight * x
+	}
+	return true
+}
+
+// --- Text vectors ---
+
+// stopWords carry no topical meaning and are skipped when embedding text.
+var stopWords = map[string]bool{
+	"a": true, "an": true, "the": true, "and": true, "or": true, "of": true, "to": true,
+	"in": true, "on": true, "for": true, "with": true, "my": true, "our": true, "your": true,
+	"i": true, "we": true, "you": true, "it": true, "is": true, "are": true, "be": true,
+	"do": true, "does": true, "how": true, "what": true, "which": true, "when": true,
+	"where": true, "why": true, "can": true, "should": true, "would": true, "make": true,
+	"get": true, "use": true, "using": true, "from": true, "into": true, "at": true,
+	"by": true, "this": true, "that": true, "these": true, "those": true, "there": true,
+	"as": true, "so": true, "if": true, "not": true, "no": true, "me": true, "want": true,
+	"need": true, "zerops": true,
+}
+
+// words splits text into lowercase words without stop words.
+func words(text string) []string {
+	fields := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '-'
+	})
+	out := fields[:0]
+	for _, w := range fields {
+		w = strings.Trim(w, "-")
+		if len(w) > 1 && !stopWords[w] {
+			out = append(out, w)
+		}
+	}
+	return out
+}
+
+func stemWord(w string) string {
+	return porterstemmer.StemString(w)
+}
+
+// embedText returns the unit vector of text: the sum of its word vectors weighted by
+// sublinear term frequency and IDF. It returns nil when no word is known to the model.
+func embedText(model WordModel, idf func(stem string) float64, text string) []float32 {
+	counts := make(map[string]int)
+	for _, w := range words(text) {
+		counts[w]++
+		// Like the BM25 analyzer, also match the parts: "shared-storage" finds "shared storage".
+		if strings.Contains(w, "-") {
+			for _, part := range strings.Split(w, "-") {
+				if len(part) > 1 && !stopWords[part] {
+					counts[part]++
+				}
+			}
+		}
+	}
+	vec := make([]float32, model.Dim())
+	known := false
+	for w, n := range counts {
+		weight := float32((1 + math.Log(float64(n))) * idf(stemWord(w)))
+		if model.Add(vec, w, weight) {
+			known = true
+		}
+	}
+	if !known {
+		return nil
+	}
+	if !normalize(vec) {
+		return nil
+	}
+	return vec
+}
+
+func normalize(vec []float32) bool {
+	var sum float64
+	for _, x := range vec {
+		sum += float64(x) * float64(x)
+	}
+	if sum == 0 {
+		return false
+	}
+	norm := float32(math.Sqrt(sum))
+	for i := range vec {
+		vec[i] /= norm
+	}
+	return true
+}
+
+// cosine returns the cosine similarity of two unit vectors.
+func cosine(a, b []float32) float64 {
+	if a == nil || b == nil {
+		return 0
+	}
+	var dot float64
+	for i := range a {
+		dot += float64(a[i]) * float64(b[i])
+	}
+	return dot
+}
@@ -0,0 +1,129 @@
+package knowledge
+
+import (
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLoadWordVectors_GloVe(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "vectors.txt")
+	data := "mail 1 0 0\nemail 0.9 0.1 0\nbackup 0 0 1\n"
+	if err := os.WriteFile(path, []byte(data), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	m, err := LoadWordVectors(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m.Dim() != 3 {
+		t.Errorf("Dim = %d, want 3", m.Dim())
+	}
+	vec := make([]float32, 3)
+	if !m.Add(vec, "email", 2) || vec[0] != 1.8 {
+		t.Errorf("Add(email) = %v", vec)
+	}
+	if m.Add(vec, "unknown", 1) {
+		t.Error("Add(unknown) should report false")
+	}
+}
+
+func TestLoadWordVectors_Word2VecHeader(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "vectors.vec")
+	if err := os.WriteFile(path, []byte("2 2\nmail 1 0\nbackup 0 1\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	m, err := LoadWordVectors(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m.Dim() != 2 {
+		t.Errorf("Dim = %d, want 2", m.Dim())
+	}
+}
+
+func TestLoadWordVectors_Errors(t *testing.T) {
+	dir := t.TempDir()
+	ragged := filepath.Join(dir, "ragged.txt")
+	if err := os.WriteFile(ragged, []byte("mail 1 0\nbackup 1\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	empty := filepath.Join(dir, "empty.txt")
+	if err := os.WriteFile(empty, nil, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	for _, path := range []string{ragged, empty, filepath.Join(dir, "missing.txt")} {
+		if _, err := LoadWordVectors(path); err == nil {
+			t.Errorf("LoadWordVectors(%s): expected error", filepath.Base(path))
+		}
+	}
+}
+
+func TestBuiltinModel_RelatesCoOccurringWords(t *testing.T) {
+	model := builtinModel()
+	idf := func(string) float64 { return 1 }
+	email := embedText(model, idf, "email")
+	smtp := embedText(model, idf, "smtp")
+	backup := embedText(model, idf, "backup")
+	if cosine(email, smtp) <= cosine(email, backup) {
+		t.Errorf("email~smtp %.3f should exceed email~backup %.3f", cosine(email, smtp), cosine(email, backup))
+	}
+	// Words the documents never use are known from the Go documentation.
+	if embedText(model, idf, "reachable") == nil {
+		t.Error("expected a vector for reachable")
+	}
+}
+
+func TestBuiltinModel_UpToDate(t *testing.T) {
+	if got, want := builtinModel().digest, documentsDigest(loadFromEmbedded()); got != want {
+		t.Errorf("model/wordvec.bin was trained on other documents (%s, want %s); run go generate ./internal/knowledge", got, want)
+	}
+}
+
+func TestDecodeWordVectors(t *testing.T) {
+	vectors := map[string][]float32{"mail": {0.6, -0.8}, "backup": {0, 1}}
+	data, err := encodeWordVectors(vectors, 2, "digest")
+	if err != nil {
+		t.Fatal(err)
+	}
+	m, err := decodeWordVectors("test", data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m.Dim() != 2 || m.digest != "digest" || len(m.vectors) != 2 {
+		t.Fatalf("decoded %d-dim model with %d words, digest %q", m.Dim(), len(m.vectors), m.digest)
+	}
+	for w, want := range vectors {
+		for i, x := range m.vectors[w] {
+			if math.Abs(float64(x-want[i])) > 0.01 {
+				t.Errorf("%s[%d] = %.3f, want %.3f", w, i, x, want[i])
+			}
+		}
+	}
+	for _, bad := range [][]byte{nil, []byte("zaia-wordvec 2 2 1 x\n"), data[:len(data)-1], append(data, 0)} {
+		if _, err := decodeWordVectors("test", bad); err == nil {
+			t.Errorf("decodeWordVectors(%q...): expected error", bad[:min(len(bad), 20)])
+		}
+	}
+}
+
+func TestSetWordModel(t *testing.T) {
+	store := NewStore()
+	path := filepath.Join(t.TempDir(), "vectors.txt")
+	if err := os.WriteFile(path, []byte("smtp 1 0\nemail 1 0\nbackup 0 1\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	m, err := LoadWordVectors(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	store.SetWordModel(m)
+	results := store.SearchWith("email", SearchOptions{Limit: 3, Mode: ModeVector})
+	if len(results) == 0 {
+		t.Fatal("expected vector results with a loaded model")
+	}
+	if store.vectorIndex().model.Name() != "vectors.txt" {
+		t.Errorf("model = %s, want vectors.txt", store.vectorIndex().model.Name())
+	}
+}
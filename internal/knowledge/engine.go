@@ -2,42 +2,70 @@ package knowledge
 
 import (
 	"fmt"
+	"maps"
+	"path/filepath"
+	"slices"
 	"sort"
 	"strings"
+	"sync"
 
 	"github.com/blevesearch/bleve/v2"
+	"github.com/blevesearch/bleve/v2/search/query"
 )
 
 const analyzerStandard = "standard"
 
-// SearchResult represents a single search result.
+// sectionFanout is how many section hits are fetched per result document
+// when picking each document's best section.
+const sectionFanout = 10
+
+// SearchResult represents a single search result: a document and its best matching section.
 type SearchResult struct {
-	URI     string  `json:"uri"`
+	URI     string  `json:"uri"` // zerops://docs/...#section, or the document URI when no section matches
 	Title   string  `json:"title"`
+	Section string  `json:"section,omitempty"`
 	Score   float64 `json:"score"`
 	Snippet string  `json:"snippet"`
+	// BoostedBy lists the SearchOptions.Boost types the document is about.
+	BoostedBy []string `json:"boostedBy,omitempty"`
 }
 
 // Resource represents an MCP Resource entry.
 type Resource struct {
-	URI         string `json:"uri"`
-	Name        string `json:"name"`
-	Description string `json:"description"`
-	MimeType    string `json:"mimeType"`
+	URI          string            `json:"uri"`
+	Name         string            `json:"name"`
+	Description  string            `json:"description"`
+	MimeType     string            `json:"mimeType"`
+	Category     string            `json:"category,omitempty"`
+	ServiceTypes []string          `json:"serviceTypes,omitempty"`
+	MinVersions  map[string]string `json:"minVersions,omitempty"`
+	LastReviewed string            `json:"lastReviewed,omitempty"`
 }
 
 // Provider interface for knowledge access.
 type Provider interface {
 	List() []Resource
 	Get(uri string) (*Document, error)
+	GetSection(uri string) (*Document, *Section, error)
 	Search(query string, limit int) []SearchResult
+	SearchWith(query string, opts SearchOptions) []SearchResult
 	GenerateSuggestions(query string, results []SearchResult) []string
+	DidYouMean(query string) string
 }
 
 // Store holds the knowledge base with BM25 index.
 type Store struct {
-	docs  map[string]*Document
-	index bleve.Index
+	docs      map[string]*Document
+	overrides map[string]string // overridden zerops://docs/ URI → team:// URI
+	chunks    map[string]chunk  // index document ID → chunk
+	index     bleve.Index
+
+	model       WordModel // nil = the built-in corpus model
+	vectors     *vectorIndex
+	vectorsOnce sync.Once
+
+	spell     *spellIndex
+	spellOnce sync.Once
 }
 
 // Verify Store implements Provider
@@ -62,41 +90,87 @@ func NewStore() *Store {
 	return s
 }
 
-func (s *Store) buildIndex() {
-	titleMapping := bleve.NewTextFieldMapping()
-	titleMapping.Analyzer = analyzerStandard
-	titleMapping.Store = false
-	titleMapping.IncludeInAll = true
+// NewStoreWithDirs creates a Store from the embedded documents plus the markdown in dirs.
+// External documents are indexed under team://<relative path>. An external document whose
+// relative path matches an embedded one (services/postgresql.md) replaces it; Get resolves the
+// embedded URI to the replacement. When dirs share a path, the later directory wins.
+func NewStoreWithDirs(dirs []string) (*Store, error) {
+	s := &Store{docs: loadFromEmbedded()}
+	if err := s.loadDirs(dirs); err != nil {
+		return nil, err
+	}
+	s.buildIndex()
+	return s, nil
+}
 
-	kwMapping := bleve.NewTextFieldMapping()
-	kwMapping.Analyzer = analyzerStandard
-	kwMapping.Store = false
+// OpenStore is NewStoreWithDirs backed by a persistent index under cacheDir, reused across
+// invocations and updated only for changed documents. Any cache failure (unwritable dir,
+// index held by another process) falls back to an in-memory index. An empty cacheDir
+// disables the cache. Close releases the index.
+func OpenStore(dirs []string, cacheDir string) (*Store, error) {
+	s := &Store{docs: loadFromEmbedded()}
+	if err := s.loadDirs(dirs); err != nil {
+		return nil, err
+	}
+	s.buildChunks()
+	if cacheDir != "" {
+		path := filepath.Join(cacheDir, "index-"+indexKey(dirs))
+		if idx, _, err := openCachedIndex(path, s.chunks); err == nil {
+			s.index = idx
+			pruneIndexes(path)
+			return s, nil
+		}
+	}
+	s.buildIndex()
+	return s, nil
+}
 
-	contentMapping := bleve.NewTextFieldMapping()
-	contentMapping.Analyzer = analyzerStandard
-	contentMapping.Store = false
+// Close releases the search index.
+func (s *Store) Close() error {
+	return s.index.Close()
+}
 
-	docMapping := bleve.NewDocumentMapping()
-	docMapping.AddFieldMappingsAt("title", titleMapping)
-	docMapping.AddFieldMappingsAt("keywords", kwMapping)
-	docMapping.AddFieldMappingsAt("content", contentMapping)
+func (s *Store) loadDirs(dirs []string) error {
+	s.overrides = make(map[string]string)
+	for _, dir := range dirs {
+		external, err := loadFromDir(dir)
+		if err != nil {
+			return fmt.Errorf("knowledge dir %s: %w", dir, err)
+		}
+		for uri, doc := range external {
+			embedded := embeddedScheme + strings.TrimPrefix(uri, externalScheme)
+			if _, ok := s.docs[embedded]; ok {
+				delete(s.docs, embedded)
+				s.overrides[embedded] = uri
+			}
+			if _, ok := s.overrides[embedded]; ok {
+				doc.Overrides = embedded
+			}
+			s.docs[uri] = doc
+		}
+	}
+	return nil
+}
 
-	indexMapping := bleve.NewIndexMapping()
-	indexMapping.DefaultMapping = docMapping
-	indexMapping.DefaultAnalyzer = analyzerStandard
+func (s *Store) buildChunks() {
+	s.chunks = make(map[string]chunk)
+	for _, doc := range s.docs {
+		for _, c := range chunksOf(doc) {
+			s.chunks[c.id] = c
+		}
+	}
+}
 
-	index, err := bleve.NewMemOnly(indexMapping)
+func (s *Store) buildIndex() {
+	s.buildChunks()
+	index, err := bleve.NewMemOnly(newIndexMapping())
 	if err != nil {
 		panic(fmt.Sprintf("failed to create search index: %v", err))
 	}
 
 	batch := index.NewBatch()
-	for uri, doc := range s.docs {
-		_ = batch.Index(uri, map[string]interface{}{
-			"title":    doc.Title,
-			"keywords": strings.Join(doc.Keywords, " "),
-			"content":  doc.Content,
-		})
+	for id, c := range s.chunks {
+		_ = batch.Index(id, indexFields(c))
 	}
 	if err := index.Batch(batch); err != nil {
 		panic(fmt.Sprintf("failed to index documents: %v", err))
@@ -106,13 +180,119 @@ func (s *Store) buildIndex() {
 }
 
 // Search performs a BM25 search with field boosts and query expansion.
+// Documents are ranked as a whole; each result then points at the document's best matching
+// section, so agents can fetch just that part.
 func (s *Store) Search(query string, limit int) []SearchResult {
+	return s.SearchWith(query, SearchOptions{Limit: limit})
+}
+
+// SearchWith searches with explicit options; see SearchOptions.
+func (s *Store) SearchWith(query string, opts SearchOptions) []SearchResult {
+	limit := opts.Limit
 	if limit <= 0 {
 		limit = 5
 	}
 
 	expanded := expandQuery(query)
 
+	size := limit
+	if opts.rankAll() {
+		size = len(s.docs)
+	}
+	var ranked []scoredDoc
+	switch opts.Mode {
+	case ModeVector:
+		ranked = s.vectorRank(expanded)
+	case ModeHybrid:
+		ranked = s.hybridRank(expanded)
+	case ModeBM25, "":
+		ranked = s.bm25Rank(expanded, size)
+	}
+	if opts.filtered() {
+		kept := ranked[:0]
+		for _, d := range ranked {
+			if opts.matches(s.docs[d.uri]) {
+				kept = append(kept, d)
+			}
+		}
+		ranked = kept
+	}
+	boostedBy := s.boost(ranked, opts.Boost)
+	if len(ranked) > limit {
+		ranked = ranked[:limit]
+	}
+	if len(ranked) == 0 {
+		return nil
+	}
+
+	uris := make([]string, len(ranked))
+	for i, d := range ranked {
+		uris[i] = d.uri
+	}
+	var best map[string]chunk
+	if opts.Mode == ModeVector {
+		best = s.vectorSections(expanded, uris)
+	} else {
+		best = s.bestSections(expanded, uris)
+		if opts.Mode == ModeHybrid {
+			for uri, c := range s.vectorSections(expanded, uris) {
+				if _, ok := best[uri]; !ok {
+					best[uri] = c
+				}
+			}
+		}
+	}
+
+	out := make([]SearchResult, 0, len(ranked))
+	for _, d := range ranked {
+		doc := s.docs[d.uri]
+		r := SearchResult{
+			URI:       doc.URI,
+			Title:     doc.Title,
+			Score:     d.score,
+			Snippet:   extractSnippet(doc.Content, query, 300),
+			BoostedBy: boostedBy[d.uri],
+		}
+		if c, ok := best[d.uri]; ok {
+			r.URI = c.id
+			r.Section = c.section.Heading
+			r.Snippet = extractSnippet(c.section.Content, query, 300)
+		}
+		out = append(out, r)
+	}
+	return out
+}
+
+// boost multiplies the scores of documents about any of types by projectBoost and re-sorts
+// ranked. It returns the types each boosted document matched.
+func (s *Store) boost(ranked []scoredDoc, types []string) map[string][]string {
+	boostedBy := make(map[string][]string)
+	if len(types) == 0 {
+		return boostedBy
+	}
+	for i, d := range ranked {
+		doc := s.docs[d.uri]
+		for _, t := range types {
+			if doc.appliesTo(t) {
+				boostedBy[d.uri] = append(boostedBy[d.uri], t)
+			}
+		}
+		if len(boostedBy[d.uri]) > 0 {
+			ranked[i].score *= projectBoost
+		}
+	}
+	sortScored(ranked)
+	return boostedBy
+}
+
+// scoredDoc is a document URI with its ranking score.
+type scoredDoc struct {
+	uri   string
+	score float64
+}
+
+// bm25Rank returns up to size documents ranked by BM25 over title, keywords and content.
+func (s *Store) bm25Rank(expanded string, size int) []scoredDoc {
 	titleQuery := bleve.NewMatchQuery(expanded)
 	titleQuery.SetField("title")
 	titleQuery.SetBoost(2.0)
@@ -127,28 +307,72 @@ func (s *Store) Search(query string, limit int) []SearchResult {
 
 	disjunction := bleve.NewDisjunctionQuery(titleQuery, kwQuery, contentQuery)
 
-	searchRequest := bleve.NewSearchRequest(disjunction)
-	searchRequest.Size = limit
+	searchRequest := bleve.NewSearchRequest(bleve.NewConjunctionQuery(disjunction, kindQuery(kindDocument)))
+	searchRequest.Size = size
 
 	results, err := s.index.Search(searchRequest)
 	if err != nil || results.Total == 0 {
 		return nil
 	}
 
-	out := make([]SearchResult, 0, len(results.Hits))
+	ranked := make([]scoredDoc, 0, len(results.Hits))
+	for _, hit := range results.Hits {
+		if c, ok := s.chunks[hit.ID]; ok {
+			ranked = append(ranked, scoredDoc{uri: c.doc.URI, score: hit.Score})
+		}
+	}
+	return ranked
+}
+
+// bestSections returns the best matching section of each document in uris.
+func (s *Store) bestSections(expanded string, uris []string) map[string]chunk {
+	best := make(map[string]chunk)
+	if len(uris) == 0 {
+		return best
+	}
+
+	headingQuery := bleve.NewMatchQuery(expanded)
+	headingQuery.SetField("heading")
+	headingQuery.SetBoost(1.5)
+
+	contentQuery := bleve.NewMatchQuery(expanded)
+	contentQuery.SetField("content")
+	contentQuery.SetBoost(1.0)
+
+	docQueries := make([]query.Query, len(uris))
+	for i, uri := range uris {
+		q := bleve.NewTermQuery(uri)
+		q.SetField("doc")
+		docQueries[i] = q
+	}
+
+	searchRequest := bleve.NewSearchRequest(bleve.NewConjunctionQuery(
+		bleve.NewDisjunctionQuery(headingQuery, contentQuery),
+		kindQuery(kindSection),
+		bleve.NewDisjunctionQuery(docQueries...),
+	))
+	searchRequest.Size = len(uris) * sectionFanout
+
+	results, err := s.index.Search(searchRequest)
+	if err != nil {
+		return best
+	}
 	for _, hit := range results.Hits {
-		doc := s.docs[hit.ID]
-		if doc == nil {
+		c, ok := s.chunks[hit.ID]
+		if !ok {
 			continue
 		}
-		out = append(out, SearchResult{
-			URI:     doc.URI,
-			Title:   doc.Title,
-			Score:   hit.Score,
-			Snippet: extractSnippet(doc.Content, query, 300),
-		})
+		if _, seen := best[c.doc.URI]; !seen {
+			best[c.doc.URI] = c
+		}
 	}
-	return out
+	return best
+}
+
+func kindQuery(kind string) query.Query {
+	q := bleve.NewTermQuery(kind)
+	q.SetField("kind")
+	return q
 }
 
 // List returns all available resources for MCP list_resources().
@@ -156,10 +380,14 @@ func (s *Store) List() []Resource {
 	resources := make([]Resource, 0, len(s.docs))
 	for _, doc := range s.docs {
 		resources = append(resources, Resource{
-			URI:         doc.URI,
-			Name:        doc.Title,
-			Description: doc.Description,
-			MimeType:    "text/markdown",
+			URI:          doc.URI,
+			Name:         doc.Title,
+			Description:  doc.Description,
+			MimeType:     "text/markdown",
+			Category:     doc.Category,
+			ServiceTypes: doc.ServiceTypes,
+			MinVersions:  doc.MinVersions,
+			LastReviewed: doc.LastReviewed,
 		})
 	}
 	sort.Slice(resources, func(i, j int) bool {
@@ -168,8 +396,13 @@ func (s *Store) List() []Resource {
 	return resources
 }
 
-// Get returns a document by URI.
+// Get returns a document by URI; a #section fragment is ignored.
+// The URI of an overridden embedded document returns its replacement.
 func (s *Store) Get(uri string) (*Document, error) {
+	uri, _ = SplitFragment(uri)
+	if replacement, ok := s.overrides[uri]; ok {
+		uri = replacement
+	}
 	doc, ok := s.docs[uri]
 	if !ok {
 		return nil, fmt.Errorf("document not found: %s", uri)
@@ -177,6 +410,31 @@ func (s *Store) Get(uri string) (*Document, error) {
 	return doc, nil
 }
 
+// GetSection returns the document and section addressed by a <uri>#<anchor> URI.
+func (s *Store) GetSection(uri string) (*Document, *Section, error) {
+	doc, err := s.Get(uri)
+	if err != nil {
+		return nil, nil, err
+	}
+	_, anchor := SplitFragment(uri)
+	sec := doc.Section(anchor)
+	if sec == nil {
+		return nil, nil, fmt.Errorf("section not found: %s", uri)
+	}
+	return doc, sec, nil
+}
+
+// Categories returns the categories of the documents in the store, sorted.
+func (s *Store) Categories() []string {
+	seen := make(map[string]bool)
+	for _, doc := range s.docs {
+		if doc.Category != "" {
+			seen[doc.Category] = true
+		}
+	}
+	return slices.Sorted(maps.Keys(seen))
+}
+
 // DocumentCount returns the number of indexed documents.
 func (s *Store) DocumentCount() int {
 	return len(s.docs)
@@ -452,7 +452,8 @@ func TestHitRate(t *testing.T) {
 				expectedURI = tc.wantTop3
 			}
 
-			if results[0].URI == expectedURI || (tc.wantURI != "" && results[0].URI == tc.wantURI) {
+			top, _ := SplitFragment(results[0].URI)
+			if top == expectedURI || (tc.wantURI != "" && top == tc.wantURI) {
 				hit1++
 				hit3++
 				return
@@ -479,9 +480,10 @@ func TestHitRate(t *testing.T) {
 
 // --- Helpers ---
 
+// containsURI reports whether a result points into the document uri.
 func containsURI(results []SearchResult, uri string) bool {
 	for _, r := range results {
-		if r.URI == uri {
+		if doc, _ := SplitFragment(r.URI); doc == uri {
 			return true
 		}
 	}
@@ -493,7 +495,7 @@ func containsURIInTop(results []SearchResult, uri string, _ int) bool {
 		if i >= 3 {
 			break
 		}
-		if r.URI == uri {
+		if doc, _ := SplitFragment(r.URI); doc == uri {
 			return true
 		}
 	}
@@ -0,0 +1,254 @@
+package knowledge
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/zeropsio/zaia/internal/validation"
+	"gopkg.in/yaml.v3"
+)
+
+// Example kinds.
+const (
+	KindZeropsYml = "zerops.yml"
+	KindImportYml = "import.yml"
+	KindFragment  = "fragment" // YAML that is neither, e.g. env variables alone
+	KindTemplate  = "template" // ```yaml template: placeholders, not validated
+	KindInvalid   = "invalid"  // not parseable YAML
+)
+
+// zeropsSetupKeys are the keys of one zerops: entry; a block made of them is a single setup.
+var zeropsSetupKeys = map[string]bool{"setup": true, "extends": true, "build": true, "deploy": true, "run": true}
+
+// Example is a fenced YAML block of a knowledge document with its validation result.
+// Partial examples are validated in a minimal wrapper (a single setup as zerops: [setup],
+// a list of services as services: [...]) and may leave out required keys.
+type Example struct {
+	URI     string             `json:"uri"`     // document URI with the #section the block is in
+	File    string             `json:"file"`    // source file
+	Line    int                `json:"line"`    // 1-based line of the first YAML line
+	Kind    string             `json:"kind"`    // zerops.yml, import.yml, fragment, template or invalid
+	Partial bool               `json:"partial"` // validated in a wrapper
+	Content string             `json:"content"` // YAML as written in the document
+	Issues  []validation.Issue `json:"issues"`  // lines are relative to the document
+}
+
+// Valid reports whether the example passed validation. Fragments and templates are not validated.
+func (e Example) Valid() bool {
+	return len(e.Issues) == 0
+}
+
+// Examples returns the YAML examples of the documents in the store, ordered by URI and line.
+func (s *Store) Examples() []Example {
+	return examplesOf(s.docs)
+}
+
+// DirExamples returns the YAML examples of the markdown under dir.
+func DirExamples(dir string) ([]Example, error) {
+	docs, err := loadFromDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	return examplesOf(docs), nil
+}
+
+func examplesOf(docs map[string]*Document) []Example {
+	var out []Example
+	for _, doc := range docs {
+		out = append(out, docExamples(doc)...)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].File != out[j].File {
+			return out[i].File < out[j].File
+		}
+		return out[i].Line < out[j].Line
+	})
+	return out
+}
+
+// docExamples extracts, classifies and validates the YAML blocks of doc.
+func docExamples(doc *Document) []Example {
+	var out []Example
+	for _, b := range codeBlocks(doc.Content) {
+		if b.Lang != "yaml" && b.Lang != "yml" {
+			continue
+		}
+		ex := Example{URI: doc.URI, File: doc.Path, Line: b.Line + doc.bodyLine, Content: b.Content, Issues: []validation.Issue{}}
+		if sec := sectionAt(doc, b.Line); sec != nil {
+			ex.URI = doc.URI + "#" + sec.Anchor
+		}
+		if b.isTemplate() {
+			ex.Kind = KindTemplate
+		} else {
+			validateExample(&ex)
+		}
+		out = append(out, ex)
+	}
+	return out
+}
+
+// validateExample classifies ex and validates it as its kind.
+func validateExample(ex *Example) {
+	var node yaml.Node
+	if err := yaml.Unmarshal([]byte(ex.Content), &node); err != nil {
+		ex.Kind = KindInvalid
+		// Both validators report a syntax error the same way.
+		ex.Issues = shiftIssues(validation.ValidateZeropsYml([]byte(ex.Content)), ex.Line-1)
+		return
+	}
+
+	content, offset := ex.Content, 0
+	ex.Kind, ex.Partial = classify(&node)
+	if ex.Partial {
+		content, offset = wrapExample(ex.Kind, ex.Content)
+	}
+	var issues []validation.Issue
+	switch ex.Kind {
+	case KindZeropsYml:
+		issues = validation.ValidateZeropsYml([]byte(content))
+	case KindImportYml:
+		issues = projectImportIssues(validation.ValidateImportYml([]byte(content)))
+	default:
+		return
+	}
+	if ex.Partial {
+		issues = partialIssues(issues)
+	}
+	ex.Issues = shiftIssues(issues, ex.Line-1-offset)
+}
+
+// partialIssues drops missing-key issues: a partial example leaves out what it is not about,
+// but its keys and values must still be valid.
+func partialIssues(issues []validation.Issue) []validation.Issue {
+	out := issues[:0]
+	for _, is := range issues {
+		if !strings.HasPrefix(is.Error, "Missing '") {
+			out = append(out, is)
+		}
+	}
+	return out
+}
+
+// classify returns the kind of a parsed YAML block and whether it is a partial example.
+func classify(node *yaml.Node) (string, bool) {
+	if len(node.Content) == 0 {
+		return KindFragment, false
+	}
+	root := node.Content[0]
+	switch root.Kind {
+	case yaml.MappingNode:
+		keys := mappingKeys(root)
+		for i, key := range keys {
+			switch {
+			case key == "zerops":
+				return KindZeropsYml, false
+			case key == "services" && root.Content[2*i+1].Kind == yaml.SequenceNode:
+				return KindImportYml, false
+			}
+		}
+		// A setup fragment must configure something beyond its name.
+		setup := len(keys) > 0
+		for _, key := range keys {
+			setup = setup && zeropsSetupKeys[key]
+		}
+		if setup && (len(keys) > 1 || keys[0] != "setup") {
+			return KindZeropsYml, true
+		}
+	case yaml.SequenceNode:
+		services := len(root.Content) > 0
+		for _, item := range root.Content {
+			keys := mappingKeys(item)
+			services = services && item.Kind == yaml.MappingNode && containsKey(keys, "hostname") && containsKey(keys, "type")
+		}
+		if services {
+			return KindImportYml, true
+		}
+	case yaml.DocumentNode, yaml.ScalarNode, yaml.AliasNode:
+	}
+	return KindFragment, false
+}
+
+// wrapExample embeds a partial example in a minimal document of kind and returns it with
+// the number of lines added before the example.
+func wrapExample(kind, content string) (string, int) {
+	if kind == KindImportYml {
+		return "services:\n" + content, 1
+	}
+	var b strings.Builder
+	b.WriteString("zerops:\n")
+	if strings.Contains("\n"+content, "\nsetup:") {
+		b.WriteString("  -\n")
+	} else {
+		b.WriteString("  - setup: example\n")
+	}
+	for _, line := range strings.SplitAfter(content, "\n") {
+		if strings.TrimSpace(line) != "" {
+			b.WriteString("    ")
+		}
+		b.WriteString(line)
+	}
+	return b.String(), 2
+}
+
+// shiftIssues moves issue lines by delta; issues without a position are placed on the first
+// line of the example.
+func shiftIssues(issues []validation.Issue, delta int) []validation.Issue {
+	out := make([]validation.Issue, len(issues))
+	for i, is := range issues {
+		if is.Line > 0 {
+			is.Line += delta
+		}
+		if is.Line <= 0 {
+			is.Line, is.Column = delta+1, 0
+		}
+		out[i] = is
+	}
+	return out
+}
+
+// sectionAt returns the section containing the 1-based line, or nil before the first heading.
+func sectionAt(doc *Document, line int) *Section {
+	sectioned := 0
+	for _, sec := range doc.Sections {
+		sectioned += len(sec.Content)
+	}
+	// Text before the first heading belongs to no section.
+	start := strings.Count(doc.Content[:len(doc.Content)-sectioned], "\n") + 1
+	var found *Section
+	for i := range doc.Sections {
+		if start > line {
+			break
+		}
+		found = &doc.Sections[i]
+		start += strings.Count(doc.Sections[i].Content, "\n")
+	}
+	return found
+}
+
+func mappingKeys(n *yaml.Node) []string {
+	if n.Kind != yaml.MappingNode {
+		return nil
+	}
+	keys := make([]string, 0, len(n.Content)/2)
+	for i := 0; i+1 < len(n.Content); i += 2 {
+		keys = append(keys, n.Content[i].Value)
+	}
+	return keys
+}
+
+func containsKey(keys []string, key string) bool {
+	for _, k := range keys {
+		if k == key {
+			return true
+		}
+	}
+	return false
+}
+
+// exampleKindName names the kind of ex in messages.
+func exampleKindName(ex Example) string {
+	if ex.Kind == KindInvalid {
+		return "YAML"
+	}
+	return ex.Kind
+}
@@ -0,0 +1,87 @@
+package knowledge
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestExamples_EmbeddedValid fails the build when an embedded YAML example goes stale.
+func TestExamples_EmbeddedValid(t *testing.T) {
+	examples := GetEmbeddedStore().Examples()
+	kinds := make(map[string]int)
+	for _, ex := range examples {
+		kinds[ex.Kind]++
+		for _, is := range ex.Issues {
+			t.Errorf("%s:%d (%s, %s): %s: %s", ex.File, is.Line, ex.URI, ex.Kind, is.Path, is.Error)
+		}
+	}
+	if kinds[KindZeropsYml] < 50 || kinds[KindImportYml] < 25 {
+		t.Errorf("kinds = %v, want >= 50 zerops.yml and >= 25 import.yml examples", kinds)
+	}
+	t.Logf("%d examples: %v", len(examples), kinds)
+}
+
+func TestDocExamples_Classify(t *testing.T) {
+	doc := parseDocument("embed/test/examples.md", "# Examples\n\n## Full\n"+
+		"```yaml\nzerops:\n  - setup: api\n    run:\n      base: nodejs@22\n```\n\n"+
+		"## Setup\n```yaml\nrun:\n  base: nodejs@22\n  start: node index.js\n```\n\n"+
+		"```yaml\n- hostname: db\n  type: postgresql@16\n  mode: HA\n```\n\n"+
+		"```yaml\nenvVariables:\n  NODE_ENV: production\n```\n\n"+
+		"```yaml template\nzerops:\n  - setup: <hostname>\n```\n\n"+
+		"```yaml\nkey: [unclosed\n```\n")
+	examples := docExamples(doc)
+
+	want := []struct {
+		kind    string
+		partial bool
+		anchor  string
+	}{
+		{KindZeropsYml, false, "full"},
+		{KindZeropsYml, true, "setup"},
+		{KindImportYml, true, "setup"},
+		{KindFragment, false, "setup"},
+		{KindTemplate, false, "setup"},
+		{KindInvalid, false, "setup"},
+	}
+	if len(examples) != len(want) {
+		t.Fatalf("got %d examples, want %d", len(examples), len(want))
+	}
+	for i, w := range want {
+		ex := examples[i]
+		if ex.Kind != w.kind || ex.Partial != w.partial || !strings.HasSuffix(ex.URI, "#"+w.anchor) {
+			t.Errorf("example %d = %s partial=%v %s, want %s partial=%v #%s", i, ex.Kind, ex.Partial, ex.URI, w.kind, w.partial, w.anchor)
+		}
+		if wantValid := w.kind != KindInvalid; ex.Valid() != wantValid {
+			t.Errorf("example %d valid = %v, issues %v", i, ex.Valid(), ex.Issues)
+		}
+	}
+	if is := examples[5].Issues; len(is) != 1 || is[0].Line != examples[5].Line {
+		t.Errorf("syntax issue = %v, want one at line %d", is, examples[5].Line)
+	}
+}
+
+func TestDocExamples_PartialIssueLines(t *testing.T) {
+	doc := parseDocument("embed/test/partial.md", "# Partial\n\n```yaml\nrun:\n  start: node index.js\n  bogus: true\n```\n\n"+
+		"```yaml\n- hostname: db\n  type: postgresql@16\n  mode: SOMETIMES\n```\n")
+	examples := docExamples(doc)
+	if len(examples) != 2 {
+		t.Fatalf("got %d examples, want 2", len(examples))
+	}
+	// Missing keys (build, base) are ignored in partial examples, unknown keys are not.
+	if is := examples[0].Issues; len(is) != 1 || is[0].Line != 6 || !strings.Contains(is[0].Error, "bogus") {
+		t.Errorf("setup issues = %v, want unknown key 'bogus' at line 6", is)
+	}
+	if is := examples[1].Issues; len(is) != 1 || is[0].Line != 12 || is[0].Path != "services[0].mode" {
+		t.Errorf("service issues = %v, want invalid mode at line 12", is)
+	}
+}
+
+func TestWrapExample_KeepsSetup(t *testing.T) {
+	wrapped, offset := wrapExample(KindZeropsYml, "# comment\nsetup: api\nrun:\n  start: x\n")
+	if offset != 2 || strings.Contains(wrapped, "setup: example") || !strings.Contains(wrapped, "    setup: api\n") {
+		t.Errorf("wrapped = %q, offset %d", wrapped, offset)
+	}
+	if issues := partialIssues(nil); len(issues) != 0 {
+		t.Errorf("partialIssues(nil) = %v", issues)
+	}
+}
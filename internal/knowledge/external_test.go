@@ -0,0 +1,100 @@
+package knowledge
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeDoc(t *testing.T, dir, rel, content string) {
+	t.Helper()
+	path := filepath.Join(dir, rel)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestNewStoreWithDirs_Extends(t *testing.T) {
+	dir := t.TempDir()
+	writeDoc(t, dir, "runbooks/rollback.md", "# Rollback Runbook\n\n## Keywords\nrollback, incident, flamingo\n\n## TL;DR\nHow our team rolls back a bad deploy.\n")
+	writeDoc(t, dir, "notes.txt", "not markdown")
+
+	store, err := NewStoreWithDirs([]string{dir})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if store.DocumentCount() != NewStore().DocumentCount()+1 {
+		t.Errorf("DocumentCount = %d, want embedded + 1", store.DocumentCount())
+	}
+
+	doc, err := store.Get("team://runbooks/rollback")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if doc.Title != "Rollback Runbook" || doc.TLDR != "How our team rolls back a bad deploy." {
+		t.Errorf("doc = %q / %q", doc.Title, doc.TLDR)
+	}
+	if len(doc.Keywords) != 3 || doc.Keywords[2] != "flamingo" {
+		t.Errorf("keywords = %v", doc.Keywords)
+	}
+
+	results := store.Search("flamingo", 5)
+	if len(results) == 0 || results[0].URI != "team://runbooks/rollback" {
+		t.Errorf("search results = %+v, want team doc first", results)
+	}
+}
+
+func TestNewStoreWithDirs_Overrides(t *testing.T) {
+	dir := t.TempDir()
+	writeDoc(t, dir, "services/postgresql.md", "# PostgreSQL at Acme\n\n## Keywords\npostgresql\n\n## TL;DR\nUse the shared cluster.\n")
+
+	store, err := NewStoreWithDirs([]string{dir})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if store.DocumentCount() != NewStore().DocumentCount() {
+		t.Errorf("DocumentCount = %d, an override must not add a document", store.DocumentCount())
+	}
+
+	doc, err := store.Get("zerops://docs/services/postgresql")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if doc.URI != "team://services/postgresql" || doc.Overrides != "zerops://docs/services/postgresql" {
+		t.Errorf("doc = %s overrides %q", doc.URI, doc.Overrides)
+	}
+	for _, r := range store.List() {
+		if r.URI == "zerops://docs/services/postgresql" {
+			t.Error("overridden document must not be listed")
+		}
+	}
+}
+
+func TestNewStoreWithDirs_LaterDirWins(t *testing.T) {
+	first, second := t.TempDir(), t.TempDir()
+	writeDoc(t, first, "conventions.md", "# First\n")
+	writeDoc(t, second, "conventions.md", "# Second\n")
+
+	store, err := NewStoreWithDirs([]string{first, second})
+	if err != nil {
+		t.Fatal(err)
+	}
+	doc, err := store.Get("team://conventions")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if doc.Title != "Second" {
+		t.Errorf("title = %q, want the later directory's document", doc.Title)
+	}
+}
+
+func TestNewStoreWithDirs_MissingDir(t *testing.T) {
+	_, err := NewStoreWithDirs([]string{filepath.Join(t.TempDir(), "missing")})
+	if err == nil || !strings.Contains(err.Error(), "missing") {
+		t.Fatalf("err = %v, want error naming the directory", err)
+	}
+}
@@ -0,0 +1,182 @@
+package knowledge
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/zeropsio/zaia/internal/catalog"
+	"gopkg.in/yaml.v3"
+)
+
+// frontmatterDelim opens and closes the YAML frontmatter at the top of a document.
+const frontmatterDelim = "---"
+
+// reviewDateLayout is the format of lastReviewed.
+const reviewDateLayout = "2006-01-02"
+
+// frontmatter is the optional YAML header of a knowledge document. Title, keywords and
+// description replace what the markdown headings say; the rest has no markdown equivalent.
+//
+//	---
+//	serviceTypes: [postgresql]
+//	minVersions: {postgresql: "14"}
+//	lastReviewed: 2026-01-15
+//	---
+type frontmatter struct {
+	Title        string            `yaml:"title"`
+	Description  string            `yaml:"description"`
+	Keywords     []string          `yaml:"keywords"`
+	Category     string            `yaml:"category"`
+	ServiceTypes []string          `yaml:"serviceTypes"`
+	MinVersions  map[string]string `yaml:"minVersions"`
+	LastReviewed string            `yaml:"lastReviewed"`
+}
+
+// splitFrontmatter separates the frontmatter from the markdown body. It returns the YAML
+// between the delimiters, the body, and the number of lines before the body; ok is false
+// when content has no frontmatter.
+func splitFrontmatter(content string) (yml, body string, lines int, ok bool) {
+	first, rest, found := strings.Cut(content, "\n")
+	if !found || strings.TrimSpace(first) != frontmatterDelim {
+		return "", content, 0, false
+	}
+	var b strings.Builder
+	lines = 1
+	for rest != "" {
+		var line string
+		line, rest, _ = strings.Cut(rest, "\n")
+		lines++
+		if strings.TrimSpace(line) == frontmatterDelim {
+			return b.String(), rest, lines, true
+		}
+		b.WriteString(line)
+		b.WriteByte('\n')
+	}
+	// Unterminated: not frontmatter but a document starting with a horizontal rule.
+	return "", content, 0, false
+}
+
+// parseFrontmatter decodes yml, rejecting unknown fields so typos do not go unnoticed.
+func parseFrontmatter(yml string) (frontmatter, error) {
+	var fm frontmatter
+	if strings.TrimSpace(yml) == "" {
+		return fm, nil
+	}
+	dec := yaml.NewDecoder(bytes.NewReader([]byte(yml)))
+	dec.KnownFields(true)
+	if err := dec.Decode(&fm); err != nil {
+		return frontmatter{}, fmt.Errorf("invalid frontmatter: %w", err)
+	}
+	return fm, nil
+}
+
+// applyFrontmatter copies the frontmatter fields onto doc, normalized like their markdown
+// counterparts.
+func applyFrontmatter(doc *Document, fm frontmatter) {
+	if fm.Title != "" {
+		doc.Title = fm.Title
+	}
+	if fm.Description != "" {
+		doc.Description = fm.Description
+	}
+	if len(fm.Keywords) > 0 {
+		doc.Keywords = make([]string, 0, len(fm.Keywords))
+		for _, kw := range fm.Keywords {
+			if kw = strings.ToLower(strings.TrimSpace(kw)); kw != "" {
+				doc.Keywords = append(doc.Keywords, kw)
+			}
+		}
+	}
+	if fm.Category != "" {
+		doc.Category = fm.Category
+	}
+	for _, t := range fm.ServiceTypes {
+		doc.ServiceTypes = append(doc.ServiceTypes, strings.ToLower(strings.TrimSpace(t)))
+	}
+	if len(fm.MinVersions) > 0 {
+		doc.MinVersions = make(map[string]string, len(fm.MinVersions))
+		for t, v := range fm.MinVersions {
+			doc.MinVersions[strings.ToLower(t)] = v
+		}
+	}
+	doc.LastReviewed = fm.LastReviewed
+}
+
+// frontmatterIssues checks the metadata of doc against the service catalog.
+func frontmatterIssues(doc *Document) []string {
+	if doc.frontmatterErr != nil {
+		return []string{doc.frontmatterErr.Error()}
+	}
+	var issues []string
+	for _, t := range doc.ServiceTypes {
+		if _, ok := catalog.Lookup(t); !ok {
+			issues = append(issues, fmt.Sprintf("Unknown service type '%s' in serviceTypes", t))
+		}
+	}
+	types := make([]string, 0, len(doc.MinVersions))
+	for t := range doc.MinVersions {
+		types = append(types, t)
+	}
+	sort.Strings(types)
+	for _, t := range types {
+		switch {
+		case !containsKey(doc.ServiceTypes, t):
+			issues = append(issues, fmt.Sprintf("minVersions names '%s', which is not in serviceTypes", t))
+		case !validVersion(doc.MinVersions[t]):
+			issues = append(issues, fmt.Sprintf("minVersions.%s '%s' is not a version number", t, doc.MinVersions[t]))
+		}
+	}
+	if doc.LastReviewed != "" {
+		if _, err := time.Parse(reviewDateLayout, doc.LastReviewed); err != nil {
+			issues = append(issues, fmt.Sprintf("lastReviewed '%s' is not a YYYY-MM-DD date", doc.LastReviewed))
+		}
+	}
+	return issues
+}
+
+// appliesTo reports whether doc covers a service type given as name or name@version.
+// A version below the document's minimum for that type excludes it.
+func (d *Document) appliesTo(serviceType string) bool {
+	name, version := catalog.Split(strings.ToLower(serviceType))
+	if !containsKey(d.ServiceTypes, name) {
+		return false
+	}
+	minVersion, ok := d.MinVersions[name]
+	return !ok || version == "" || compareVersions(version, minVersion) >= 0
+}
+
+// validVersion reports whether v is a dotted version number such as 16 or 3.12.
+func validVersion(v string) bool {
+	for _, part := range strings.Split(v, ".") {
+		if _, err := strconv.Atoi(part); err != nil {
+			return false
+		}
+	}
+	return true
+}
+
+// compareVersions compares dotted version numbers part by part; missing parts count as 0
+// and a part that is not a number compares as 0.
+func compareVersions(a, b string) int {
+	pa, pb := strings.Split(a, "."), strings.Split(b, ".")
+	for i := range max(len(pa), len(pb)) {
+		var x, y int
+		if i < len(pa) {
+			x, _ = strconv.Atoi(pa[i])
+		}
+		if i < len(pb) {
+			y, _ = strconv.Atoi(pb[i])
+		}
+		if x != y {
+			if x < y {
+				return -1
+			}
+			return 1
+		}
+	}
+	return 0
+}
@@ -0,0 +1,225 @@
+package knowledge
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+const frontmatterDoc = `---
+title: Managed PostgreSQL
+keywords: [Postgres-Runbook, replicas]
+serviceTypes: [postgresql]
+minVersions: {postgresql: "16"}
+lastReviewed: 2026-01-15
+---
+# PostgreSQL Runbook
+
+## TL;DR
+Failover and replicas.
+
+## See Also
+- zerops://services/postgresql
+`
+
+func TestParseDocument_Frontmatter(t *testing.T) {
+	doc := parseDocument("embed/runbooks/pg.md", frontmatterDoc)
+
+	if doc.Title != "Managed PostgreSQL" {
+		t.Errorf("Title = %q, want frontmatter title", doc.Title)
+	}
+	if !slices.Equal(doc.Keywords, []string{"postgres-runbook", "replicas"}) {
+		t.Errorf("Keywords = %v", doc.Keywords)
+	}
+	if doc.Description != "Failover and replicas." {
+		t.Errorf("Description = %q, want TL;DR", doc.Description)
+	}
+	if !slices.Equal(doc.ServiceTypes, []string{"postgresql"}) || doc.MinVersions["postgresql"] != "16" {
+		t.Errorf("ServiceTypes = %v, MinVersions = %v", doc.ServiceTypes, doc.MinVersions)
+	}
+	if doc.LastReviewed != "2026-01-15" {
+		t.Errorf("LastReviewed = %q", doc.LastReviewed)
+	}
+	if !strings.HasPrefix(doc.Content, "# PostgreSQL Runbook") {
+		t.Errorf("Content keeps the frontmatter: %q", doc.Content[:20])
+	}
+	if doc.bodyLine != 7 {
+		t.Errorf("bodyLine = %d, want 7", doc.bodyLine)
+	}
+}
+
+func TestParseDocument_NoFrontmatter(t *testing.T) {
+	// A leading horizontal rule without a closing one is markdown, not frontmatter.
+	content := "---\n# Title\n\n## TL;DR\nText.\n"
+	doc := parseDocument("embed/x/y.md", content)
+	if doc.Content != content || doc.bodyLine != 0 || doc.frontmatterErr != nil {
+		t.Errorf("Content = %q, bodyLine = %d, err = %v", doc.Content, doc.bodyLine, doc.frontmatterErr)
+	}
+	if doc.Title != "Title" {
+		t.Errorf("Title = %q, want Title", doc.Title)
+	}
+}
+
+func TestParseDocument_FrontmatterUnknownField(t *testing.T) {
+	doc := parseDocument("embed/x/y.md", "---\nserviceType: postgresql\n---\n# Title\n")
+	if doc.frontmatterErr == nil {
+		t.Fatal("expected error for unknown field serviceType")
+	}
+	if doc.Title != "Title" || len(doc.ServiceTypes) != 0 {
+		t.Errorf("Title = %q, ServiceTypes = %v", doc.Title, doc.ServiceTypes)
+	}
+}
+
+func TestDocument_AppliesTo(t *testing.T) {
+	doc := parseDocument("embed/runbooks/pg.md", frontmatterDoc)
+	tests := []struct {
+		serviceType string
+		want        bool
+	}{
+		{"postgresql", true},
+		{"PostgreSQL", true},
+		{"postgresql@16", true},
+		{"postgresql@17", true},
+		{"postgresql@14", false},
+		{"mariadb", false},
+	}
+	for _, tt := range tests {
+		if got := doc.appliesTo(tt.serviceType); got != tt.want {
+			t.Errorf("appliesTo(%q) = %v, want %v", tt.serviceType, got, tt.want)
+		}
+	}
+}
+
+func TestCompareVersions(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"16", "16", 0},
+		{"16", "14", 1},
+		{"3.9", "3.12", -1},
+		{"1.22", "1.22.0", 0},
+	}
+	for _, tt := range tests {
+		if got := compareVersions(tt.a, tt.b); got != tt.want {
+			t.Errorf("compareVersions(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestStore_Categories(t *testing.T) {
+	store := NewStore()
+	categories := store.Categories()
+	for _, want := range []string{"decisions", "services", "config"} {
+		if !slices.Contains(categories, want) {
+			t.Errorf("Categories() = %v, missing %s", categories, want)
+		}
+	}
+	doc, err := store.Get("zerops://docs/services/postgresql")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if doc.Category != "services" || !slices.Equal(doc.ServiceTypes, []string{"postgresql"}) {
+		t.Errorf("Category = %q, ServiceTypes = %v", doc.Category, doc.ServiceTypes)
+	}
+}
+
+func TestSearch_FilterServiceType(t *testing.T) {
+	store := NewStore()
+	results := store.SearchWith("connection string", SearchOptions{Limit: 10, ServiceType: "postgresql"})
+	if len(results) == 0 {
+		t.Fatal("expected results for postgresql")
+	}
+	for _, r := range results {
+		uri, _ := SplitFragment(r.URI)
+		doc, _ := store.Get(uri)
+		if !slices.Contains(doc.ServiceTypes, "postgresql") {
+			t.Errorf("%s does not cover postgresql", r.URI)
+		}
+	}
+	if !containsURI(results, "zerops://docs/services/postgresql") {
+		t.Errorf("expected postgresql doc, got %v", urisFromResults(results))
+	}
+}
+
+func TestSearch_FilterCategory(t *testing.T) {
+	store := NewStore()
+	for _, mode := range Modes {
+		results := store.SearchWith("which database", SearchOptions{Limit: 5, Mode: mode, Category: "decisions"})
+		if len(results) == 0 {
+			t.Errorf("%s: expected results in decisions", mode)
+		}
+		for _, r := range results {
+			if !strings.HasPrefix(r.URI, "zerops://docs/decisions/") {
+				t.Errorf("%s: %s is not in decisions", mode, r.URI)
+			}
+		}
+	}
+}
+
+func TestLintDir_Frontmatter(t *testing.T) {
+	dir := t.TempDir()
+	writeLintDoc(t, dir, "runbooks/pg.md", frontmatterDoc)
+	writeLintDoc(t, dir, "runbooks/bad.md", "---\nserviceTypes: [postgres]\nminVersions: {mariadb: \"10\"}\nlastReviewed: Jan 2026\n---\n"+
+		"# Bad\n\n## Keywords\nbad-frontmatter\n\n## TL;DR\nBad.\n\n## See Also\n- zerops://services/nope\n")
+
+	report, err := LintDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var frontmatter, deadLinks []LintIssue
+	for _, is := range report.Errors {
+		switch is.Rule {
+		case ruleFrontmatter:
+			frontmatter = append(frontmatter, is)
+		case ruleDeadLink:
+			deadLinks = append(deadLinks, is)
+		default:
+			t.Errorf("unexpected error %v", is)
+		}
+	}
+	// Unknown type, minVersions type not in serviceTypes, bad date; pg.md's frontmatter
+	// keywords and title stand in for the missing sections.
+	if len(frontmatter) != 3 {
+		t.Errorf("frontmatter errors = %v, want 3", frontmatter)
+	}
+	for _, is := range frontmatter {
+		if !strings.HasSuffix(is.File, "bad.md") || is.Line != 1 {
+			t.Errorf("frontmatter error at %s:%d, want bad.md:1", is.File, is.Line)
+		}
+	}
+	// Lines are counted in the file, frontmatter included.
+	if len(deadLinks) != 1 || deadLinks[0].Line != 15 {
+		t.Errorf("dead links = %v, want one at line 15", deadLinks)
+	}
+}
+
+func TestSearch_Boost(t *testing.T) {
+	store := NewStore()
+	plain := store.SearchWith("connection string", SearchOptions{Limit: 5})
+	boosted := store.SearchWith("connection string", SearchOptions{Limit: 5, Boost: []string{"postgresql@16"}})
+	if len(boosted) == 0 || !strings.HasPrefix(boosted[0].URI, "zerops://docs/") {
+		t.Fatalf("boosted = %v", urisFromResults(boosted))
+	}
+
+	rank := func(results []SearchResult, prefix string) int {
+		for i, r := range results {
+			if strings.HasPrefix(r.URI, prefix) {
+				return i
+			}
+		}
+		return len(results)
+	}
+	const pg = "zerops://docs/services/postgresql"
+	if rank(boosted, pg) > rank(plain, pg) {
+		t.Errorf("postgresql ranked %d with boost, %d without", rank(boosted, pg), rank(plain, pg))
+	}
+	for _, r := range boosted {
+		if strings.HasPrefix(r.URI, pg) && !slices.Equal(r.BoostedBy, []string{"postgresql@16"}) {
+			t.Errorf("BoostedBy = %v, want postgresql@16", r.BoostedBy)
+		}
+	}
+	if rank(boosted, pg) == len(boosted) {
+		t.Errorf("postgresql not in boosted results %v", urisFromResults(boosted))
+	}
+}
@@ -0,0 +1,275 @@
+package knowledge
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/blevesearch/bleve/v2"
+	"github.com/blevesearch/bleve/v2/analysis/analyzer/keyword"
+	"github.com/blevesearch/bleve/v2/mapping"
+)
+
+const (
+	// indexVersion changes whenever the index mapping or indexed fields change,
+	// so indexes written by older binaries are not reused.
+	indexVersion = "2"
+	// manifestKey stores the content hash and chunk IDs of every indexed document.
+	manifestKey = "zaia:manifest"
+	// indexLockTimeout bounds the wait for another zaia process holding the index.
+	indexLockTimeout = "2s"
+	// unusedIndexAge is how long an index may go unused before pruneIndexes removes it.
+	unusedIndexAge = 30 * 24 * time.Hour
+)
+
+// Values of the "kind" field: whole documents are ranked, sections locate the answer.
+const (
+	kindDocument = "document"
+	kindSection  = "section"
+)
+
+// newIndexMapping returns the BM25 field mapping shared by in-memory and persistent indexes.
+func newIndexMapping() mapping.IndexMapping {
+	titleMapping := bleve.NewTextFieldMapping()
+	titleMapping.Analyzer = analyzerStandard
+	titleMapping.Store = false
+	titleMapping.IncludeInAll = true
+
+	kwMapping := bleve.NewTextFieldMapping()
+	kwMapping.Analyzer = analyzerStandard
+	kwMapping.Store = false
+
+	contentMapping := bleve.NewTextFieldMapping()
+	contentMapping.Analyzer = analyzerStandard
+	contentMapping.Store = false
+
+	exactMapping := bleve.NewTextFieldMapping()
+	exactMapping.Analyzer = keyword.Name
+	exactMapping.Store = false
+	exactMapping.IncludeInAll = false
+
+	docMapping := bleve.NewDocumentMapping()
+	docMapping.AddFieldMappingsAt("title", titleMapping)
+	docMapping.AddFieldMappingsAt("keywords", kwMapping)
+	docMapping.AddFieldMappingsAt("heading", kwMapping)
+	docMapping.AddFieldMappingsAt("content", contentMapping)
+	docMapping.AddFieldMappingsAt("kind", exactMapping)
+	docMapping.AddFieldMappingsAt("doc", exactMapping)
+
+	indexMapping := bleve.NewIndexMapping()
+	indexMapping.DefaultMapping = docMapping
+	indexMapping.DefaultAnalyzer = analyzerStandard
+	return indexMapping
+}
+
+// chunk is an index record: a whole document (section nil, ID is the document URI)
+// or one of its sections (ID is <uri>#<anchor>).
+type chunk struct {
+	id      string
+	doc     *Document
+	section *Section
+}
+
+// unindexedSections duplicate other fields (keywords) or only link elsewhere.
+var unindexedSections = map[string]bool{"Keywords": true, "See Also": true}
+
+// chunksOf returns the index records of doc: the whole document, then every section
+// with body text except unindexedSections.
+func chunksOf(doc *Document) []chunk {
+	chunks := []chunk{{id: doc.URI, doc: doc}}
+	for i := range doc.Sections {
+		sec := &doc.Sections[i]
+		if unindexedSections[sec.Heading] || !hasBody(sec.Content) {
+			continue
+		}
+		chunks = append(chunks, chunk{id: doc.URI + "#" + sec.Anchor, doc: doc, section: sec})
+	}
+	return chunks
+}
+
+// hasBody reports whether section content has text after its heading line.
+func hasBody(content string) bool {
+	_, body, _ := strings.Cut(content, "\n")
+	return strings.TrimSpace(body) != ""
+}
+
+// indexFields returns the indexed representation of c.
+func indexFields(c chunk) map[string]interface{} {
+	if c.section == nil {
+		return map[string]interface{}{
+			"kind":     kindDocument,
+			"doc":      c.doc.URI,
+			"title":    c.doc.Title,
+			"keywords": strings.Join(c.doc.Keywords, " "),
+			"content":  c.doc.Content,
+		}
+	}
+	return map[string]interface{}{
+		"kind":    kindSection,
+		"doc":     c.doc.URI,
+		"heading": c.section.Heading,
+		"content": c.section.Content,
+	}
+}
+
+// indexKey names the persistent index for a set of sources: the index version and the
+// external directories. Changed documents, embedded or external, are caught by the manifest,
+// so a new binary or edited docs update the same index.
+func indexKey(dirs []string) string {
+	h := sha256.New()
+	fmt.Fprintf(h, "v%s\n", indexVersion)
+	for _, dir := range dirs {
+		if abs, err := filepath.Abs(dir); err == nil {
+			dir = abs
+		}
+		fmt.Fprintf(h, "dir %s\n", dir)
+	}
+	return hex.EncodeToString(h.Sum(nil))[:16]
+}
+
+// pruneIndexes marks the index at path as used and removes the other indexes under its
+// directory that have not been used for unusedIndexAge: those of directory sets no longer
+// passed, or of older index versions. Failures are ignored; an index still open elsewhere
+// is rebuilt on its next use.
+func pruneIndexes(path string) {
+	now := time.Now()
+	_ = os.Chtimes(path, now, now)
+	dir := filepath.Dir(path)
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return
+	}
+	for _, e := range entries {
+		sibling := filepath.Join(dir, e.Name())
+		if !e.IsDir() || !strings.HasPrefix(e.Name(), "index-") || sibling == path {
+			continue
+		}
+		if info, err := e.Info(); err == nil && now.Sub(info.ModTime()) > unusedIndexAge {
+			_ = os.RemoveAll(sibling)
+		}
+	}
+}
+
+func docHash(doc *Document) string {
+	// Title and keywords may come from frontmatter, outside Content.
+	sum := sha256.Sum256([]byte(doc.Title + "\n" + strings.Join(doc.Keywords, ",") + "\n" + doc.Content))
+	return hex.EncodeToString(sum[:])
+}
+
+// manifestEntry records what was indexed for one document.
+type manifestEntry struct {
+	Hash   string   `json:"hash"`
+	Chunks []string `json:"chunks"`
+}
+
+func sameHash(a, b manifestEntry) bool { return a.Hash == b.Hash }
+
+// openCachedIndex opens the persistent index at path for chunks, reindexing only documents
+// whose content changed since it was written. It returns the number of documents reindexed.
+// The returned index is read-only, so concurrent zaia processes can share it.
+func openCachedIndex(path string, chunks map[string]chunk) (bleve.Index, int, error) {
+	want := make(map[string]manifestEntry)
+	byDoc := make(map[string][]chunk)
+	for id, c := range chunks {
+		e := want[c.doc.URI]
+		e.Hash = docHash(c.doc)
+		e.Chunks = append(e.Chunks, id)
+		want[c.doc.URI] = e
+		byDoc[c.doc.URI] = append(byDoc[c.doc.URI], c)
+	}
+
+	readOnly := map[string]interface{}{"read_only": true, "bolt_timeout": indexLockTimeout}
+	if idx, err := bleve.OpenUsing(path, readOnly); err == nil {
+		if have, err := readManifest(idx); err == nil && maps.EqualFunc(have, want, sameHash) {
+			return idx, 0, nil
+		}
+		_ = idx.Close()
+	}
+
+	reindexed, err := updateIndex(path, byDoc, want)
+	if err != nil {
+		return nil, 0, err
+	}
+	idx, err := bleve.OpenUsing(path, readOnly)
+	if err != nil {
+		return nil, 0, err
+	}
+	return idx, reindexed, nil
+}
+
+// updateIndex brings the index at path in line with want, creating it if needed.
+func updateIndex(path string, byDoc map[string][]chunk, want map[string]manifestEntry) (int, error) {
+	idx, err := bleve.OpenUsing(path, map[string]interface{}{"bolt_timeout": indexLockTimeout})
+	have := map[string]manifestEntry{}
+	switch {
+	case errors.Is(err, bleve.ErrorIndexPathDoesNotExist):
+		if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+			return 0, err
+		}
+		if idx, err = bleve.New(path, newIndexMapping()); err != nil {
+			return 0, err
+		}
+	case err != nil:
+		return 0, err
+	default:
+		if m, err := readManifest(idx); err == nil {
+			have = m
+		}
+	}
+
+	batch := idx.NewBatch()
+	for uri, old := range have {
+		if cur, ok := want[uri]; ok && sameHash(old, cur) {
+			continue
+		}
+		for _, id := range old.Chunks {
+			batch.Delete(id)
+		}
+	}
+	reindexed := 0
+	for uri, cur := range want {
+		if old, ok := have[uri]; ok && sameHash(old, cur) {
+			continue
+		}
+		for _, c := range byDoc[uri] {
+			if err := batch.Index(c.id, indexFields(c)); err != nil {
+				_ = idx.Close()
+				return 0, err
+			}
+		}
+		reindexed++
+	}
+	manifest, err := json.Marshal(want)
+	if err != nil {
+		_ = idx.Close()
+		return 0, err
+	}
+	batch.SetInternal([]byte(manifestKey), manifest)
+	if err := idx.Batch(batch); err != nil {
+		_ = idx.Close()
+		return 0, err
+	}
+	return reindexed, idx.Close()
+}
+
+func readManifest(idx bleve.Index) (map[string]manifestEntry, error) {
+	data, err := idx.GetInternal([]byte(manifestKey))
+	if err != nil {
+		return nil, err
+	}
+	if data == nil {
+		return nil, errors.New("index has no manifest")
+	}
+	var m map[string]manifestEntry
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
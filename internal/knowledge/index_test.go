@@ -0,0 +1,140 @@
+package knowledge
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestOpenCachedIndex_ReusesAndReindexesChanged(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "index")
+	docs := loadFromEmbedded()
+
+	idx, reindexed, err := openCachedIndex(path, chunkMap(docs))
+	if err != nil {
+		t.Fatal(err)
+	}
+	_ = idx.Close()
+	if reindexed != len(docs) {
+		t.Errorf("first open reindexed %d, want all %d", reindexed, len(docs))
+	}
+
+	idx, reindexed, err = openCachedIndex(path, chunkMap(docs))
+	if err != nil {
+		t.Fatal(err)
+	}
+	_ = idx.Close()
+	if reindexed != 0 {
+		t.Errorf("unchanged docs reindexed %d, want 0", reindexed)
+	}
+
+	changed := *docs["zerops://docs/services/postgresql"]
+	changed.Content += "\nquetzalcoatl\n"
+	changed.Sections = splitSections(changed.Content)
+	docs["zerops://docs/services/postgresql"] = &changed
+	delete(docs, "zerops://docs/services/valkey")
+
+	chunks := chunkMap(docs)
+	idx, reindexed, err = openCachedIndex(path, chunks)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer idx.Close()
+	if reindexed != 1 {
+		t.Errorf("reindexed %d, want only the changed document", reindexed)
+	}
+	count, _ := idx.DocCount()
+	if int(count) != len(chunks) {
+		t.Errorf("index holds %d chunks, want %d", count, len(chunks))
+	}
+}
+
+func chunkMap(docs map[string]*Document) map[string]chunk {
+	m := make(map[string]chunk)
+	for _, doc := range docs {
+		for _, c := range chunksOf(doc) {
+			m[c.id] = c
+		}
+	}
+	return m
+}
+
+func TestOpenStore_Cached(t *testing.T) {
+	cacheDir := t.TempDir()
+	dir := t.TempDir()
+	writeDoc(t, dir, "runbook.md", "# Runbook\n\n## Keywords\naxolotl\n")
+
+	for i := 0; i < 2; i++ {
+		store, err := OpenStore([]string{dir}, cacheDir)
+		if err != nil {
+			t.Fatal(err)
+		}
+		results := store.Search("axolotl", 5)
+		_ = store.Close()
+		if len(results) == 0 || results[0].URI != "team://runbook" {
+			t.Fatalf("run %d: results = %+v, want team://runbook", i, results)
+		}
+	}
+
+	entries, err := os.ReadDir(cacheDir)
+	if err != nil || len(entries) != 1 {
+		t.Fatalf("cache dir entries = %v (%v), want one index", entries, err)
+	}
+
+	writeDoc(t, dir, "runbook.md", "# Runbook\n\n## Keywords\npangolin\n")
+	store, err := OpenStore([]string{dir}, cacheDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer store.Close()
+	if results := store.Search("pangolin", 5); len(results) == 0 || results[0].URI != "team://runbook" {
+		t.Errorf("edited doc not reindexed: %+v", results)
+	}
+	if entries, _ := os.ReadDir(cacheDir); len(entries) != 1 {
+		t.Errorf("cache dir entries = %v, want the same index updated", entries)
+	}
+}
+
+func TestOpenStore_PrunesUnusedIndexes(t *testing.T) {
+	cacheDir := t.TempDir()
+	stale := filepath.Join(cacheDir, "index-stale")
+	recent := filepath.Join(cacheDir, "index-recent")
+	for _, dir := range []string{stale, recent} {
+		if err := os.MkdirAll(dir, 0o700); err != nil {
+			t.Fatal(err)
+		}
+	}
+	old := time.Now().Add(-2 * unusedIndexAge)
+	if err := os.Chtimes(stale, old, old); err != nil {
+		t.Fatal(err)
+	}
+
+	store, err := OpenStore(nil, cacheDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_ = store.Close()
+
+	if _, err := os.Stat(stale); !os.IsNotExist(err) {
+		t.Error("unused index not pruned")
+	}
+	if _, err := os.Stat(recent); err != nil {
+		t.Errorf("recently used index removed: %v", err)
+	}
+}
+
+func TestOpenStore_UnwritableCacheFallsBack(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "not-a-dir")
+	if err := os.WriteFile(file, nil, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	store, err := OpenStore(nil, file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer store.Close()
+	if len(store.Search("postgresql", 5)) == 0 {
+		t.Error("expected in-memory fallback to return results")
+	}
+}
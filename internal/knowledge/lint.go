@@ -0,0 +1,272 @@
+package knowledge
+
+import (
+	"fmt"
+	"maps"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/zeropsio/zaia/internal/validation"
+)
+
+// Lint severities: errors fail `zaia knowledge lint`, warnings are reported only.
+const (
+	LintError   = "error"
+	LintWarning = "warning"
+)
+
+// Lint rule IDs.
+const (
+	ruleTitle            = "title"
+	ruleRequiredSection  = "required-section"
+	ruleDeadLink         = "dead-link"
+	ruleDuplicateKeyword = "duplicate-keyword"
+	ruleInvalidYAML      = "invalid-yaml"
+	ruleFrontmatter      = "frontmatter"
+)
+
+// requiredSections are the ## sections every document needs: search indexes Keywords,
+// descriptions come from TL;DR and suggestions from See Also. Frontmatter keywords and
+// description replace the first two.
+var requiredSections = []string{"Keywords", "TL;DR", "See Also"}
+
+// LintIssue is a convention violation in a knowledge document.
+type LintIssue struct {
+	URI      string `json:"uri"`
+	File     string `json:"file"`
+	Line     int    `json:"line,omitempty"`
+	Rule     string `json:"rule"`
+	Severity string `json:"severity"`
+	Message  string `json:"message"`
+}
+
+// LintReport is the result of linting a set of documents.
+type LintReport struct {
+	Documents int         `json:"documents"`
+	Errors    []LintIssue `json:"errors"`
+	Warnings  []LintIssue `json:"warnings"`
+}
+
+// Lint lints the documents of the store, embedded and external alike.
+func (s *Store) Lint() LintReport {
+	return lintDocs(s.docs, s.docs)
+}
+
+// LintDir lints the markdown under dir. Links resolve against dir and the embedded documents,
+// and keywords are compared with both.
+func LintDir(dir string) (LintReport, error) {
+	docs, err := loadFromDir(dir)
+	if err != nil {
+		return LintReport{}, err
+	}
+	known := loadFromEmbedded()
+	maps.Copy(known, docs)
+	return lintDocs(docs, known), nil
+}
+
+// lintDocs checks docs; known holds every document links and keywords are checked against.
+func lintDocs(docs, known map[string]*Document) LintReport {
+	uris := make([]string, 0, len(docs))
+	for uri := range docs {
+		uris = append(uris, uri)
+	}
+	sort.Strings(uris)
+
+	keywordDocs := make(map[string][]string)
+	for uri, doc := range known {
+		for _, kw := range uniqueStrings(doc.Keywords) {
+			keywordDocs[kw] = append(keywordDocs[kw], uri)
+		}
+	}
+
+	report := LintReport{Documents: len(docs), Errors: []LintIssue{}, Warnings: []LintIssue{}}
+	for _, uri := range uris {
+		for _, is := range lintDocument(docs[uri], docs, known, keywordDocs) {
+			if is.Severity == LintError {
+				report.Errors = append(report.Errors, is)
+			} else {
+				report.Warnings = append(report.Warnings, is)
+			}
+		}
+	}
+	return report
+}
+
+func lintDocument(doc *Document, docs, known map[string]*Document, keywordDocs map[string][]string) []LintIssue {
+	var issues []LintIssue
+	add := func(line int, rule, severity, msg string) {
+		issues = append(issues, LintIssue{URI: doc.URI, File: doc.Path, Line: line, Rule: rule, Severity: severity, Message: msg})
+	}
+	// fileLine turns a line of doc.Content into a line of the file, past any frontmatter.
+	fileLine := func(line int) int {
+		if line > 0 {
+			line += doc.bodyLine
+		}
+		return line
+	}
+
+	for _, msg := range frontmatterIssues(doc) {
+		add(1, ruleFrontmatter, LintError, msg)
+	}
+
+	if doc.frontmatter.Title == "" && (len(doc.Sections) == 0 || doc.Sections[0].Level != 1) {
+		add(fileLine(1), ruleTitle, LintError, "Document must start with a '# Title' heading")
+	}
+	for _, name := range requiredSections {
+		if !sectionRequired(doc, name) {
+			continue
+		}
+		sec := sectionByHeading(doc, name)
+		switch {
+		case sec == nil:
+			add(0, ruleRequiredSection, LintError, fmt.Sprintf("Missing '## %s' section", name))
+		case !hasBody(sec.Content):
+			add(fileLine(headingLine(doc.Content, sec.Heading)), ruleRequiredSection, LintError, fmt.Sprintf("'## %s' section is empty", name))
+		}
+	}
+
+	for _, l := range links(doc.Content) {
+		uri, anchor := linkTarget(l.uri)
+		target, ok := known[uri]
+		switch {
+		case !ok:
+			add(fileLine(l.line), ruleDeadLink, LintError, fmt.Sprintf("Link %s points to a missing document", l.uri))
+		case anchor != "" && target.Section(anchor) == nil:
+			add(fileLine(l.line), ruleDeadLink, LintError, fmt.Sprintf("Link %s points to a missing section", l.uri))
+		}
+	}
+
+	keywordsLine := headingLine(doc.Content, "Keywords")
+	if keywordsLine > 0 {
+		keywordsLine = fileLine(keywordsLine + 1)
+	}
+	seen := make(map[string]bool)
+	for _, kw := range doc.Keywords {
+		if seen[kw] {
+			add(keywordsLine, ruleDuplicateKeyword, LintWarning, fmt.Sprintf("Keyword '%s' is listed twice", kw))
+			continue
+		}
+		seen[kw] = true
+		var others []string
+		reported := false
+		for _, uri := range keywordDocs[kw] {
+			if uri == doc.URI {
+				continue
+			}
+			// Report a keyword shared by linted documents once, at the first of them.
+			if _, linted := docs[uri]; linted && uri < doc.URI {
+				reported = true
+			}
+			others = append(others, uri)
+		}
+		if len(others) > 0 && !reported {
+			sort.Strings(others)
+			add(keywordsLine, ruleDuplicateKeyword, LintWarning,
+				fmt.Sprintf("Keyword '%s' is also used by %s", kw, strings.Join(others, ", ")))
+		}
+	}
+
+	for _, ex := range docExamples(doc) {
+		for _, is := range ex.Issues {
+			msg := is.Error
+			if is.Path != "" {
+				msg = is.Path + ": " + msg
+			}
+			add(is.Line, ruleInvalidYAML, LintError, fmt.Sprintf("Invalid %s example: %s", exampleKindName(ex), msg))
+		}
+	}
+	return issues
+}
+
+// sectionRequired reports whether doc needs the ## section name: frontmatter keywords and
+// description stand in for the Keywords and TL;DR sections.
+func sectionRequired(doc *Document, name string) bool {
+	switch name {
+	case "Keywords":
+		return len(doc.frontmatter.Keywords) == 0
+	case "TL;DR":
+		return doc.frontmatter.Description == ""
+	}
+	return true
+}
+
+// projectImportIssues drops issues about the project: section. zaia imports into an existing
+// project, but docs also show project-level imports, where the section is valid.
+func projectImportIssues(issues []validation.Issue) []validation.Issue {
+	out := issues[:0]
+	for _, is := range issues {
+		if is.Path != "project" && !strings.HasPrefix(is.Path, "project.") {
+			out = append(out, is)
+		}
+	}
+	return out
+}
+
+// linkPattern matches knowledge URIs in markdown text.
+var linkPattern = regexp.MustCompile(`(?:zerops|team)://[A-Za-z0-9_./#-]+`)
+
+type link struct {
+	uri  string
+	line int
+}
+
+// links returns the knowledge URIs outside code fences.
+func links(content string) []link {
+	var out []link
+	inFence := false
+	for i, line := range strings.Split(content, "\n") {
+		if strings.HasPrefix(strings.TrimSpace(line), "```") {
+			inFence = !inFence
+			continue
+		}
+		if inFence {
+			continue
+		}
+		for _, m := range linkPattern.FindAllString(line, -1) {
+			out = append(out, link{uri: strings.TrimRight(m, "./"), line: i + 1})
+		}
+	}
+	return out
+}
+
+// linkTarget returns the document URI and anchor a link points to. Short zerops:// links,
+// as used in See Also (zerops://networking/vpn), address zerops://docs/ documents.
+func linkTarget(l string) (string, string) {
+	uri, anchor := SplitFragment(l)
+	if strings.HasPrefix(uri, "zerops://") && !strings.HasPrefix(uri, embeddedScheme) {
+		uri = embeddedScheme + strings.TrimPrefix(uri, "zerops://")
+	}
+	return uri, anchor
+}
+
+func sectionByHeading(doc *Document, heading string) *Section {
+	for i := range doc.Sections {
+		if doc.Sections[i].Level == 2 && doc.Sections[i].Heading == heading {
+			return &doc.Sections[i]
+		}
+	}
+	return nil
+}
+
+// headingLine returns the 1-based line of the ## heading, or 0.
+func headingLine(content, heading string) int {
+	for i, line := range strings.Split(content, "\n") {
+		if strings.TrimSpace(line) == "## "+heading {
+			return i + 1
+		}
+	}
+	return 0
+}
+
+func uniqueStrings(list []string) []string {
+	seen := make(map[string]bool, len(list))
+	out := make([]string, 0, len(list))
+	for _, s := range list {
+		if !seen[s] {
+			seen[s] = true
+			out = append(out, s)
+		}
+	}
+	return out
+}
@@ -0,0 +1,128 @@
+package knowledge
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestLint_EmbeddedDocs keeps the embedded knowledge base free of lint errors.
+func TestLint_EmbeddedDocs(t *testing.T) {
+	report := GetEmbeddedStore().Lint()
+	if report.Documents < 60 {
+		t.Errorf("Documents = %d, want >= 60", report.Documents)
+	}
+	for _, is := range report.Errors {
+		t.Errorf("%s:%d: [%s] %s", is.File, is.Line, is.Rule, is.Message)
+	}
+	t.Logf("%d warnings", len(report.Warnings))
+}
+
+func writeLintDoc(t *testing.T, dir, rel, content string) {
+	t.Helper()
+	path := filepath.Join(dir, rel)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+const validLintDoc = `# Deploy Runbook
+
+## Keywords
+runbook-deploy, rollout
+
+## TL;DR
+How we deploy.
+
+## Steps
+See zerops://docs/services/postgresql#tldr first.
+
+## See Also
+- zerops://networking/vpn
+- team://runbooks/rollback
+`
+
+func TestLintDir_Valid(t *testing.T) {
+	dir := t.TempDir()
+	writeLintDoc(t, dir, "runbooks/deploy.md", validLintDoc)
+	writeLintDoc(t, dir, "runbooks/rollback.md", strings.ReplaceAll(validLintDoc, "runbook-deploy", "runbook-rollback"))
+
+	report, err := LintDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Documents != 2 {
+		t.Errorf("Documents = %d, want 2", report.Documents)
+	}
+	if len(report.Errors) != 0 {
+		t.Errorf("Errors = %v, want none", report.Errors)
+	}
+	// "rollout" is shared by the two linted docs: reported once.
+	if len(report.Warnings) != 1 || report.Warnings[0].Rule != ruleDuplicateKeyword {
+		t.Errorf("Warnings = %v, want one duplicate-keyword", report.Warnings)
+	}
+}
+
+func TestLintDir_Errors(t *testing.T) {
+	dir := t.TempDir()
+	writeLintDoc(t, dir, "broken.md", "Intro without a title.\n\n## Keywords\npostgresql\n\n## TL;DR\n\n"+
+		"## Example\n```yaml\nzerops:\n  - setup: app\n    run:\n      start: node app.js\n      bogus: true\n```\n\n"+
+		"```yaml template\nzerops:\n  - setup: <hostname>\n    bogus: true\n```\n\n"+
+		"```yaml\nservices:\n  - hostname: db\n    type: postgresql@16\n```\n\n"+
+		"```yaml\nrun:\n  bogus: true\n```\n\n"+
+		"Links: zerops://docs/services/nope, zerops://networking/vpn#nope.\n")
+
+	report, err := LintDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := make(map[string]int)
+	for _, is := range report.Errors {
+		got[is.Rule]++
+	}
+	want := map[string]int{
+		ruleTitle:           1,
+		ruleRequiredSection: 2, // empty TL;DR, missing See Also
+		ruleDeadLink:        2,
+		ruleInvalidYAML:     3, // unknown key in zerops.yml and in a run: fragment, missing mode in import.yml
+	}
+	for rule, n := range want {
+		if got[rule] != n {
+			t.Errorf("%s errors = %d, want %d (%v)", rule, got[rule], n, report.Errors)
+		}
+	}
+	for _, is := range report.Errors {
+		if is.Rule == ruleInvalidYAML && strings.Contains(is.Message, "bogus") && is.Line != 14 && is.Line != 31 {
+			t.Errorf("bogus key reported at line %d, want 14 or 31", is.Line)
+		}
+	}
+
+	// postgresql is an embedded keyword too.
+	if len(report.Warnings) != 1 || !strings.Contains(report.Warnings[0].Message, "zerops://docs/services/postgresql") {
+		t.Errorf("Warnings = %v, want postgresql shared with the embedded doc", report.Warnings)
+	}
+}
+
+func TestLintDir_Missing(t *testing.T) {
+	if _, err := LintDir(filepath.Join(t.TempDir(), "missing")); err == nil {
+		t.Fatal("expected error for missing directory")
+	}
+}
+
+func TestCodeBlocks(t *testing.T) {
+	content := "# T\n\n```yaml template\na: 1\n```\n\ntext\n```bash\nls\n"
+	blocks := codeBlocks(content)
+	if len(blocks) != 2 {
+		t.Fatalf("got %d blocks, want 2", len(blocks))
+	}
+	if blocks[0].Lang != "yaml" || !blocks[0].isTemplate() || blocks[0].Line != 4 || blocks[0].Content != "a: 1\n" {
+		t.Errorf("block 0 = %+v", blocks[0])
+	}
+	if blocks[1].Lang != "bash" || blocks[1].isTemplate() || blocks[1].Content != "ls\n\n" {
+		t.Errorf("block 1 = %+v", blocks[1])
+	}
+}
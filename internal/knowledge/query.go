@@ -80,8 +80,7 @@ func (s *Store) GenerateSuggestions(query string, results []SearchResult) []stri
 
 	// Has results — add See Also suggestions from topResult
 	if len(results) > 0 {
-		topDoc := s.docs[results[0].URI]
-		if topDoc != nil {
+		if topDoc, err := s.Get(results[0].URI); err == nil {
 			seeAlso := extractSeeAlsoSuggestions(topDoc.Content)
 			suggestions = append(suggestions, seeAlso...)
 		}
@@ -133,9 +132,9 @@ func extractSnippet(content, query string, maxLen int) string {
 	}
 
 	if bestPos < 0 {
-		lines := strings.SplitN(content, "\n", 3)
-		if len(lines) >= 3 {
-			return truncate(lines[2], maxLen)
+		// No match: start after the heading line.
+		if _, rest, ok := strings.Cut(content, "\n"); ok && strings.TrimSpace(rest) != "" {
+			return truncate(strings.TrimSpace(rest), maxLen)
 		}
 		return truncate(content, maxLen)
 	}
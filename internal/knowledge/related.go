@@ -0,0 +1,92 @@
+package knowledge
+
+import (
+	"fmt"
+	"sort"
+)
+
+// RelatedDoc is a document reached from another through its See Also links.
+type RelatedDoc struct {
+	URI         string `json:"uri"`
+	Title       string `json:"title"`
+	Description string `json:"description"`
+	Depth       int    `json:"depth"` // 1 for documents the start links to directly
+	Via         string `json:"via"`   // URI of the document whose See Also links here
+}
+
+// Related walks the See Also graph from uri breadth-first, up to depth links away.
+// Every document is returned once, at its shortest distance; links to missing documents
+// are skipped (lint reports them). Short zerops:// URIs are accepted like in See Also.
+func (s *Store) Related(uri string, depth int) (*Document, []RelatedDoc, error) {
+	if depth < 1 {
+		return nil, nil, fmt.Errorf("depth must be at least 1, got %d", depth)
+	}
+	startURI, _ := linkTarget(uri)
+	start, err := s.Get(startURI)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	seen := map[string]bool{start.URI: true}
+	out := []RelatedDoc{}
+	level := []*Document{start}
+	for d := 1; d <= depth && len(level) > 0; d++ {
+		var next []*Document
+		for _, from := range level {
+			for _, target := range s.seeAlso(from) {
+				if seen[target.URI] {
+					continue
+				}
+				seen[target.URI] = true
+				out = append(out, RelatedDoc{
+					URI:         target.URI,
+					Title:       target.Title,
+					Description: target.Description,
+					Depth:       d,
+					Via:         from.URI,
+				})
+				next = append(next, target)
+			}
+		}
+		level = next
+	}
+	return start, out, nil
+}
+
+// seeAlso returns the documents the See Also section of doc links to, in link order.
+func (s *Store) seeAlso(doc *Document) []*Document {
+	sec := sectionByHeading(doc, "See Also")
+	if sec == nil {
+		return nil
+	}
+	var out []*Document
+	for _, l := range links(sec.Content) {
+		uri, _ := linkTarget(l.uri)
+		if target, err := s.Get(uri); err == nil {
+			out = append(out, target)
+		}
+	}
+	return out
+}
+
+// ResourceCategory is a category of the knowledge base with its documents.
+type ResourceCategory struct {
+	Name      string     `json:"name"`
+	Documents []Resource `json:"documents"`
+}
+
+// Tree groups resources by category, categories and documents sorted by name and URI.
+// Documents without a category are grouped under "".
+func Tree(resources []Resource) []ResourceCategory {
+	byCategory := make(map[string][]Resource)
+	for _, r := range resources {
+		byCategory[r.Category] = append(byCategory[r.Category], r)
+	}
+	tree := make([]ResourceCategory, 0, len(byCategory))
+	for name, docs := range byCategory {
+		sort.Slice(docs, func(i, j int) bool { return docs[i].URI < docs[j].URI })
+		tree = append(tree, ResourceCategory{Name: name, Documents: docs})
+	}
+	sort.Slice(tree, func(i, j int) bool { return tree[i].Name < tree[j].Name })
+	return tree
+}
@@ -0,0 +1,56 @@
+package knowledge
+
+import "testing"
+
+func TestStore_Related(t *testing.T) {
+	store := NewStore()
+	_, direct, err := store.Related("zerops://docs/services/postgresql", 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, deep, err := store.Related("zerops://docs/services/postgresql", 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(deep) <= len(direct) {
+		t.Errorf("depth 2 found %d documents, depth 1 %d; want more", len(deep), len(direct))
+	}
+
+	seen := make(map[string]bool)
+	for _, r := range deep {
+		if r.URI == "zerops://docs/services/postgresql" {
+			t.Error("start document listed as related")
+		}
+		if seen[r.URI] {
+			t.Errorf("%s listed twice", r.URI)
+		}
+		seen[r.URI] = true
+		if r.Depth == 2 && !seen[r.Via] {
+			t.Errorf("%s reached via %s, which is not listed before it", r.URI, r.Via)
+		}
+	}
+}
+
+func TestStore_RelatedErrors(t *testing.T) {
+	store := NewStore()
+	if _, _, err := store.Related("zerops://docs/services/nope", 1); err == nil {
+		t.Error("expected error for missing document")
+	}
+	if _, _, err := store.Related("zerops://docs/services/postgresql", 0); err == nil {
+		t.Error("expected error for depth 0")
+	}
+}
+
+func TestTree(t *testing.T) {
+	tree := Tree([]Resource{
+		{URI: "team://runbooks/b", Category: "runbooks"},
+		{URI: "zerops://docs/services/x", Category: "services"},
+		{URI: "team://runbooks/a", Category: "runbooks"},
+	})
+	if len(tree) != 2 || tree[0].Name != "runbooks" || tree[1].Name != "services" {
+		t.Fatalf("tree = %v", tree)
+	}
+	if tree[0].Documents[0].URI != "team://runbooks/a" {
+		t.Errorf("runbooks not sorted: %v", tree[0].Documents)
+	}
+}
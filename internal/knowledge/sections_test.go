@@ -0,0 +1,86 @@
+package knowledge
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSplitSections(t *testing.T) {
+	content := "# Title\n\nIntro.\n\n## TL;DR\nShort.\n\n## Gotchas\n```bash\n## not a heading\n```\n### Ports & Protocols\nDetail.\n\n## Gotchas\nAgain.\n"
+	sections := splitSections(content)
+
+	want := []struct {
+		anchor string
+		level  int
+	}{
+		{"title", 1},
+		{"tldr", 2},
+		{"gotchas", 2},
+		{"ports--protocols", 3},
+		{"gotchas-1", 2},
+	}
+	if len(sections) != len(want) {
+		t.Fatalf("got %d sections, want %d: %+v", len(sections), len(want), sections)
+	}
+	for i, w := range want {
+		if sections[i].Anchor != w.anchor || sections[i].Level != w.level {
+			t.Errorf("section %d = %s (level %d), want %s (level %d)",
+				i, sections[i].Anchor, sections[i].Level, w.anchor, w.level)
+		}
+	}
+	if !strings.Contains(sections[2].Content, "## not a heading") {
+		t.Error("headings inside code fences must stay in their section")
+	}
+	if sections[1].Content != "## TL;DR\nShort.\n\n" {
+		t.Errorf("TL;DR content = %q", sections[1].Content)
+	}
+}
+
+func TestStore_GetSection(t *testing.T) {
+	store := NewStore()
+	doc, sec, err := store.GetSection("zerops://docs/services/postgresql#tldr")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if doc.URI != "zerops://docs/services/postgresql" || !strings.HasPrefix(sec.Content, "## TL;DR") {
+		t.Errorf("got %s / %q", doc.URI, sec.Content)
+	}
+	if strings.Contains(sec.Content, "## Keywords") {
+		t.Error("section content must not include other sections")
+	}
+
+	if _, _, err := store.GetSection("zerops://docs/services/postgresql#nonexistent"); err == nil {
+		t.Error("expected error for unknown section")
+	}
+}
+
+func TestSearch_ResultsPointAtSections(t *testing.T) {
+	store := NewStore()
+	results := store.Search("postgresql connection string", 5)
+	if len(results) == 0 {
+		t.Fatal("expected results")
+	}
+
+	seen := make(map[string]bool)
+	for _, r := range results {
+		doc, anchor := SplitFragment(r.URI)
+		if seen[doc] {
+			t.Errorf("document %s returned twice", doc)
+		}
+		seen[doc] = true
+		if anchor == "" {
+			continue
+		}
+		_, sec, err := store.GetSection(r.URI)
+		if err != nil {
+			t.Errorf("result %s does not resolve: %v", r.URI, err)
+			continue
+		}
+		if sec.Heading != r.Section {
+			t.Errorf("result %s section = %q, want %q", r.URI, r.Section, sec.Heading)
+		}
+	}
+	if _, anchor := SplitFragment(results[0].URI); anchor == "" {
+		t.Errorf("top result %s should point at a section", results[0].URI)
+	}
+}
@@ -0,0 +1,114 @@
+package knowledge
+
+import (
+	"strings"
+	"unicode"
+
+	"github.com/zeropsio/zaia/internal/validation"
+)
+
+// spellIndex corrects query words that appear nowhere in the knowledge base.
+type spellIndex struct {
+	known map[string]bool // every word of every document, plus query aliases
+	dict  map[string]int  // correction candidates (title and keyword words) → document frequency
+}
+
+// spelling returns the spelling index of s, building it on first use.
+func (s *Store) spelling() *spellIndex {
+	s.spellOnce.Do(func() {
+		s.spell = newSpellIndex(s.docs)
+	})
+	return s.spell
+}
+
+func newSpellIndex(docs map[string]*Document) *spellIndex {
+	sp := &spellIndex{known: make(map[string]bool), dict: make(map[string]int)}
+	for _, doc := range docs {
+		for _, w := range words(doc.Content) {
+			sp.known[w] = true
+		}
+		seen := make(map[string]bool)
+		for _, w := range words(doc.Title + " " + strings.Join(doc.Keywords, " ")) {
+			sp.known[w] = true
+			if !seen[w] {
+				seen[w] = true
+				sp.dict[w]++
+			}
+		}
+	}
+	for alias, expansion := range queryAliases {
+		sp.known[alias] = true
+		sp.dict[alias]++
+		for _, w := range strings.Fields(expansion) {
+			sp.known[w] = true
+		}
+	}
+	for w := range unsupportedServices {
+		sp.known[w] = true
+	}
+	return sp
+}
+
+// maxEdits is how many typos a word of length n may contain: none for short words,
+// where a single edit already reaches unrelated terms.
+func maxEdits(n int) int {
+	switch {
+	case n <= 3:
+		return 0
+	case n <= 5:
+		return 1
+	}
+	return 2
+}
+
+// correct returns the dictionary word closest to w, preferring fewer edits, then words
+// used by more documents. It returns "" when nothing is within maxEdits.
+func (sp *spellIndex) correct(w string) string {
+	limit := maxEdits(len(w))
+	best, bestDist, bestFreq := "", limit+1, 0
+	for cand, freq := range sp.dict {
+		if abs(len(cand)-len(w)) > limit {
+			continue
+		}
+		d := validation.Levenshtein(w, cand)
+		if d < bestDist || d == bestDist && (freq > bestFreq || freq == bestFreq && cand < best) {
+			best, bestDist, bestFreq = cand, d, freq
+		}
+	}
+	return best
+}
+
+// DidYouMean returns query with misspelled words corrected, or "" when every word is known
+// or no correction is close enough. Words found anywhere in the knowledge base are kept as is.
+func (s *Store) DidYouMean(query string) string {
+	sp := s.spelling()
+	fields := strings.Fields(query)
+	changed := false
+	for i, f := range fields {
+		w := strings.ToLower(f)
+		if sp.known[w] || stopWords[w] || !isWord(w) {
+			continue
+		}
+		if c := sp.correct(w); c != "" {
+			fields[i] = c
+			changed = true
+		}
+	}
+	if !changed {
+		return ""
+	}
+	return strings.Join(fields, " ")
+}
+
+// isWord reports whether w is a single plain word, not a path, URI or number.
+func isWord(w string) bool {
+	ws := words(w)
+	return len(ws) == 1 && ws[0] == w && strings.IndexFunc(w, unicode.IsDigit) < 0
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
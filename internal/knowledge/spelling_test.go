@@ -0,0 +1,42 @@
+package knowledge
+
+import "testing"
+
+func TestDidYouMean(t *testing.T) {
+	store := NewStore()
+	tests := []struct {
+		query string
+		want  string
+	}{
+		{"postgress conection", "postgres connection"},
+		{"elasticserch", "elasticsearch"},
+		{"valky cache", "valkey cache"},
+		{"enviroment variabels", "environment variables"},
+		{"zerops.yml buidl", "zerops.yml build"},
+		// Known words, stop words, short words and unsupported services are left alone.
+		{"postgresql connection string", ""},
+		{"how to deploy my app", ""},
+		{"pg port", ""},
+		{"mongodb", ""},
+		{"xyznonexistent", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			if got := store.DidYouMean(tt.query); got != tt.want {
+				t.Errorf("DidYouMean(%q) = %q, want %q", tt.query, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDidYouMean_CorrectedQueryFindsDocument(t *testing.T) {
+	store := NewStore()
+	query := "elasticserch fulltext"
+	if results := store.Search(query, 5); len(results) != 0 {
+		t.Fatalf("%q should not match before correction, got %v", query, urisFromResults(results))
+	}
+	corrected := store.DidYouMean(query)
+	if !containsURI(store.Search(corrected, 5), "zerops://docs/services/elasticsearch") {
+		t.Errorf("Search(%q) should find services/elasticsearch", corrected)
+	}
+}
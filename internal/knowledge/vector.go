@@ -0,0 +1,312 @@
+package knowledge
+
+import (
+	"math"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Mode selects how Search ranks documents.
+type Mode string
+
+const (
+	ModeBM25   Mode = "bm25"   // lexical BM25 only (default)
+	ModeVector Mode = "vector" // embedding similarity only
+	ModeHybrid Mode = "hybrid" // BM25 fused with embedding similarity
+)
+
+// Modes lists the accepted search modes.
+var Modes = []Mode{ModeHybrid, ModeBM25, ModeVector}
+
+// SearchOptions control a search. The zero value is a BM25 search for 5 results.
+type SearchOptions struct {
+	Limit       int
+	Mode        Mode
+	Category    string // only documents of this category (services, decisions, ...)
+	ServiceType string // only documents about this type: postgresql, or postgresql@16 to respect minVersions
+
+	// Boost ranks documents about these service types (the project's, as postgresql@16) higher.
+	Boost []string
+}
+
+// rankAll reports whether ranking must cover every document: filters and boosts apply after
+// ranking and would otherwise only see the top results.
+func (o SearchOptions) rankAll() bool {
+	return o.filtered() || len(o.Boost) > 0
+}
+
+func (o SearchOptions) filtered() bool {
+	return o.Category != "" || o.ServiceType != ""
+}
+
+// matches reports whether doc passes the category and service type filters.
+func (o SearchOptions) matches(doc *Document) bool {
+	if o.Category != "" && doc.Category != o.Category {
+		return false
+	}
+	return o.ServiceType == "" || doc.appliesTo(o.ServiceType)
+}
+
+const (
+	// projectBoost multiplies the score of documents about a service type in SearchOptions.Boost.
+	projectBoost = 1.5
+	// hybridVectorWeight is the share of embedding similarity in hybrid scores. BM25 keeps
+	// the larger share, so a clear keyword match is not outvoted by a vaguely similar text.
+	hybridVectorWeight = 0.3
+	// minSimilarity drops vector matches that share nothing meaningful with the query.
+	minSimilarity = 0.05
+	// titleShare and keywordShare weight the title (with the document name and service
+	// types) and the keywords of a document against its content, like the field boosts of BM25.
+	titleShare   = 0.4
+	keywordShare = 0.3
+)
+
+// TopResultThreshold is the score from which the first result of mode is confident
+// enough to return in full. Hybrid and vector scores are bounded by 1, so their
+// thresholds sit where the ranking suite's top hits stop being mostly right.
+func TopResultThreshold(mode Mode) float64 {
+	switch mode {
+	case ModeVector:
+		return 0.7
+	case ModeHybrid:
+		return 0.6
+	case ModeBM25:
+	}
+	return 1.0
+}
+
+// vectorIndex holds the embeddings of all documents and sections for one model.
+type vectorIndex struct {
+	model    WordModel
+	idf      map[string]float64
+	maxIDF   float64
+	docs     map[string][]float32 // document URI → vector
+	sections map[string][]float32 // section chunk ID → vector
+	mean     []float32            // mean document vector, subtracted from every vector
+}
+
+// SetWordModel replaces the built-in model used by vector and hybrid search.
+// It must not be called concurrently with searches.
+func (s *Store) SetWordModel(m WordModel) {
+	s.model = m
+	s.vectors = nil
+	s.vectorsOnce = sync.Once{}
+}
+
+// vectorIndex embeds all documents on first use; BM25-only searches never pay for it.
+func (s *Store) vectorIndex() *vectorIndex {
+	s.vectorsOnce.Do(func() {
+		model := s.model
+		if model == nil {
+			model = builtinModel()
+		}
+		v := &vectorIndex{
+			model:    model,
+			docs:     make(map[string][]float32, len(s.docs)),
+			sections: make(map[string][]float32, len(s.chunks)),
+		}
+		v.idf, v.maxIDF = documentIDF(s.docs)
+		for uri, doc := range s.docs {
+			v.docs[uri] = mix(
+				[][]float32{
+					v.embed(doc.Title + "\n" + path.Base(doc.URI) + "\n" + strings.Join(doc.ServiceTypes, " ")),
+					v.embed(strings.Join(doc.Keywords, " ")),
+					v.embed(doc.Content),
+				},
+				[]float64{titleShare, keywordShare, 1 - titleShare - keywordShare})
+		}
+		v.mean = meanVector(v.docs, model.Dim())
+		for uri, vec := range v.docs {
+			v.docs[uri] = v.center(vec)
+		}
+		for id, c := range s.chunks {
+			if c.section != nil {
+				v.sections[id] = v.center(v.embed(c.doc.Title + "\n" + strings.Repeat(c.section.Heading+"\n", 2) + c.section.Content))
+			}
+		}
+		s.vectors = v
+	})
+	return s.vectors
+}
+
+func (v *vectorIndex) embed(text string) []float32 {
+	return embedText(v.model, v.weight, text)
+}
+
+// query embeds a query like the documents it is compared with.
+func (v *vectorIndex) query(text string) []float32 {
+	return v.center(v.embed(text))
+}
+
+// center returns the unit vector of vec minus the mean document vector. Every document
+// shares the words of the platform, which puts all of them close to each other; centering
+// leaves the directions that tell them apart, so similarities spread out.
+func (v *vectorIndex) center(vec []float32) []float32 {
+	if vec == nil {
+		return nil
+	}
+	out := make([]float32, len(vec))
+	for k, x := range vec {
+		out[k] = x - v.mean[k]
+	}
+	if !normalize(out) {
+		return nil
+	}
+	return out
+}
+
+// meanVector returns the mean of vecs, skipping nil vectors.
+func meanVector(vecs map[string][]float32, dim int) []float32 {
+	mean := make([]float32, dim)
+	var n int
+	for _, vec := range vecs {
+		if vec == nil {
+			continue
+		}
+		n++
+		for k, x := range vec {
+			mean[k] += x
+		}
+	}
+	if n == 0 {
+		return mean
+	}
+	for k := range mean {
+		mean[k] /= float32(n)
+	}
+	return mean
+}
+
+// weight is the IDF of a stem; words unseen in the corpus get the highest weight.
+func (v *vectorIndex) weight(stem string) float64 {
+	if w, ok := v.idf[stem]; ok {
+		return w
+	}
+	return v.maxIDF
+}
+
+// documentIDF returns the smoothed inverse document frequency of every stem in docs.
+func documentIDF(docs map[string]*Document) (map[string]float64, float64) {
+	df := make(map[string]int)
+	for _, doc := range docs {
+		seen := make(map[string]bool)
+		for _, w := range words(doc.Title + " " + strings.Join(doc.Keywords, " ") + " " + doc.Content) {
+			stem := stemWord(w)
+			if !seen[stem] {
+				seen[stem] = true
+				df[stem]++
+			}
+		}
+	}
+	n := float64(len(docs))
+	idf := make(map[string]float64, len(df))
+	for stem, count := range df {
+		idf[stem] = math.Log(1 + n/float64(count))
+	}
+	return idf, math.Log(1 + n)
+}
+
+// vectorRank ranks all documents by cosine similarity to the query.
+func (s *Store) vectorRank(expanded string) []scoredDoc {
+	v := s.vectorIndex()
+	q := v.query(expanded)
+	if q == nil {
+		return nil
+	}
+	var ranked []scoredDoc
+	for uri, vec := range v.docs {
+		if sim := cosine(q, vec); sim >= minSimilarity {
+			ranked = append(ranked, scoredDoc{uri: uri, score: sim})
+		}
+	}
+	sortScored(ranked)
+	return ranked
+}
+
+// hybridRank fuses BM25 and vector scores: BM25 is squashed into [0,1) with b/(b+1)
+// and mixed with cosine similarity by hybridVectorWeight.
+func (s *Store) hybridRank(expanded string) []scoredDoc {
+	bm25 := make(map[string]float64)
+	for _, d := range s.bm25Rank(expanded, len(s.docs)) {
+		bm25[d.uri] = d.score
+	}
+	sims := make(map[string]float64)
+	for _, d := range s.vectorRank(expanded) {
+		sims[d.uri] = d.score
+	}
+
+	ranked := make([]scoredDoc, 0, len(bm25)+len(sims))
+	for uri := range s.docs {
+		b, inBM25 := bm25[uri]
+		sim, inVector := sims[uri]
+		if !inBM25 && !inVector {
+			continue
+		}
+		score := (1-hybridVectorWeight)*b/(b+1) + hybridVectorWeight*sim
+		ranked = append(ranked, scoredDoc{uri: uri, score: score})
+	}
+	sortScored(ranked)
+	return ranked
+}
+
+// vectorSections returns the section most similar to the query for each document in uris.
+func (s *Store) vectorSections(expanded string, uris []string) map[string]chunk {
+	best := make(map[string]chunk)
+	v := s.vectorIndex()
+	q := v.query(expanded)
+	if q == nil {
+		return best
+	}
+	want := make(map[string]bool, len(uris))
+	for _, uri := range uris {
+		want[uri] = true
+	}
+	bestSim := make(map[string]float64)
+	for id, vec := range v.sections {
+		c := s.chunks[id]
+		if !want[c.doc.URI] {
+			continue
+		}
+		sim := cosine(q, vec)
+		if sim < minSimilarity {
+			continue
+		}
+		if cur, ok := bestSim[c.doc.URI]; !ok || sim > cur || sim == cur && id < best[c.doc.URI].id {
+			best[c.doc.URI] = c
+			bestSim[c.doc.URI] = sim
+		}
+	}
+	return best
+}
+
+// mix returns the unit vector of the weighted sum of vecs, skipping nil vectors.
+func mix(vecs [][]float32, weights []float64) []float32 {
+	var out []float32
+	for i, vec := range vecs {
+		if vec == nil {
+			continue
+		}
+		if out == nil {
+			out = make([]float32, len(vec))
+		}
+		for k, x := range vec {
+			out[k] += float32(weights[i]) * x
+		}
+	}
+	if out == nil || !normalize(out) {
+		return nil
+	}
+	return out
+}
+
+// sortScored orders by descending score, then URI for a stable result.
+func sortScored(ranked []scoredDoc) {
+	sort.Slice(ranked, func(i, j int) bool {
+		if ranked[i].score != ranked[j].score {
+			return ranked[i].score > ranked[j].score
+		}
+		return ranked[i].uri < ranked[j].uri
+	})
+}
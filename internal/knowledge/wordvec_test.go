@@ -0,0 +1,435 @@
+package knowledge
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"flag"
+	"fmt"
+	"io/fs"
+	"math"
+	"math/rand"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+)
+
+var generateWordVectors = flag.Bool("generate-wordvec", false, "retrain the built-in word vectors into model/wordvec.bin")
+
+const (
+	trainDims     = 100 // dimensions of the built-in word vectors
+	trainMinCount = 2   // stems seen less often get no vector
+	trainWindow   = 4   // co-occurrence window, in words either side
+)
+
+// goSkipDirs are GOROOT/src directories left out of the training text: tools, the runtime
+// and internal packages, whose comments give everyday words a low-level meaning ("live",
+// "reachable" and "load" about the garbage collector and registers).
+var goSkipDirs = map[string]bool{"cmd": true, "runtime": true, "simd": true, "internal": true, "vendor": true, "testdata": true}
+
+// TestGenerateWordVectors retrains model/wordvec.bin; run it with go generate after
+// changing the embedded documents. Training is deterministic for a given Go release.
+func TestGenerateWordVectors(t *testing.T) {
+	if !*generateWordVectors {
+		t.Skip("run go generate ./internal/knowledge to retrain the built-in word vectors")
+	}
+	docs := loadFromEmbedded()
+	texts := documentTexts(docs)
+	goroot, err := exec.Command("go", "env", "GOROOT").Output()
+	if err != nil {
+		t.Fatal(err)
+	}
+	comments, err := goDocComments(filepath.Join(strings.TrimSpace(string(goroot)), "src"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	vectors := trainWordVectors(append(texts, comments...), trainDims)
+	data, err := encodeWordVectors(vectors, trainDims, documentsDigest(docs))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join("model", "wordvec.bin"), data, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	t.Logf("%d word vectors, %d bytes", len(vectors), len(data))
+}
+
+// documentTexts returns the stems of every document, in URI order.
+func documentTexts(docs map[string]*Document) [][]string {
+	texts := make([][]string, 0, len(docs))
+	for _, uri := range sortedURIs(docs) {
+		doc := docs[uri]
+		texts = append(texts, stems(doc.Title+"\n"+strings.Join(doc.Keywords, " ")+"\n"+doc.Content))
+	}
+	return texts
+}
+
+// documentsDigest identifies the documents the built-in vectors were trained on.
+func documentsDigest(docs map[string]*Document) string {
+	h := sha256.New()
+	for _, uri := range sortedURIs(docs) {
+		doc := docs[uri]
+		fmt.Fprintf(h, "%s\n%s\n%s\n%s\n", uri, doc.Title, strings.Join(doc.Keywords, " "), doc.Content)
+	}
+	return hex.EncodeToString(h.Sum(nil))[:16]
+}
+
+func sortedURIs(docs map[string]*Document) []string {
+	uris := make([]string, 0, len(docs))
+	for uri := range docs {
+		uris = append(uris, uri)
+	}
+	sort.Strings(uris)
+	return uris
+}
+
+func stems(text string) []string {
+	var out []string
+	for _, w := range words(text) {
+		out = append(out, stemWord(w))
+	}
+	return out
+}
+
+// goDocComments returns the stems of every block of // comments in the non-test Go files
+// under src, outside goSkipDirs.
+func goDocComments(src string) ([][]string, error) {
+	var texts [][]string
+	err := filepath.WalkDir(src, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if goSkipDirs[d.Name()] {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !strings.HasSuffix(path, ".go") || strings.HasSuffix(path, "_test.go") {
+			return nil
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		var block []string
+		for _, line := range strings.Split(string(data), "\n") {
+			comment, ok := strings.CutPrefix(strings.TrimSpace(line), "//")
+			if ok && !strings.HasPrefix(comment, "go:") {
+				block = append(block, comment)
+				continue
+			}
+			if len(block) > 0 {
+				texts = append(texts, stems(strings.Join(block, "\n")))
+				block = nil
+			}
+		}
+		if len(block) > 0 {
+			texts = append(texts, stems(strings.Join(block, "\n")))
+		}
+		return nil
+	})
+	return texts, err
+}
+
+// encodeWordVectors writes vectors in the format read by decodeWordVectors, quantizing
+// each vector to int8 with its own scale.
+func encodeWordVectors(vectors map[string][]float32, dim int, digest string) ([]byte, error) {
+	words := make([]string, 0, len(vectors))
+	for w := range vectors {
+		words = append(words, w)
+	}
+	sort.Strings(words)
+	data := fmt.Appendf(nil, "zaia-wordvec 1 %d %d %s\n", dim, len(words), digest)
+	for _, w := range words {
+		if len(w) > math.MaxUint8 {
+			return nil, fmt.Errorf("word %q is too long", w)
+		}
+		vec := vectors[w]
+		var maxAbs float32
+		for _, x := range vec {
+			maxAbs = max(maxAbs, float32(math.Abs(float64(x))))
+		}
+		scale := maxAbs / math.MaxInt8
+		data = append(data, byte(len(w)))
+		data = append(data, w...)
+		data = binary.LittleEndian.AppendUint32(data, math.Float32bits(scale))
+		for _, x := range vec {
+			var q int8
+			if scale > 0 {
+				q = int8(math.Round(float64(x / scale)))
+			}
+			data = append(data, byte(q))
+		}
+	}
+	return data, nil
+}
+
+// sparseRow is one row of a sparse symmetric matrix.
+type sparseRow []struct {
+	col int
+	val float64
+}
+
+// trainWordVectors learns dim-dimensional unit word vectors from texts (sequences of stems):
+// positive PMI over a ±trainWindow co-occurrence window with context distribution
+// smoothing, factorized by a randomized truncated SVD. Stems seen fewer than trainMinCount
+// times get no vector. Training is deterministic.
+func trainWordVectors(texts [][]string, dim int) map[string][]float32 {
+	counts := make(map[string]int)
+	for _, text := range texts {
+		for _, w := range text {
+			counts[w]++
+		}
+	}
+	var vocab []string
+	for w, n := range counts {
+		if n >= trainMinCount {
+			vocab = append(vocab, w)
+		}
+	}
+	sort.Strings(vocab)
+	if len(vocab) <= dim {
+		return nil
+	}
+	index := make(map[string]int, len(vocab))
+	for i, w := range vocab {
+		index[w] = i
+	}
+
+	// Co-occurrences weighted by 1/distance, counted both ways.
+	cooc := make([]map[int]float64, len(vocab))
+	for i := range cooc {
+		cooc[i] = make(map[int]float64)
+	}
+	for _, text := range texts {
+		for i, w := range text {
+			a, ok := index[w]
+			if !ok {
+				continue
+			}
+			for d := 1; d <= trainWindow && i+d < len(text); d++ {
+				if b, ok := index[text[i+d]]; ok && b != a {
+					cooc[a][b] += 1 / float64(d)
+					cooc[b][a] += 1 / float64(d)
+				}
+			}
+		}
+	}
+
+	// Positive PMI, with context counts raised to 0.75 so rare contexts don't dominate.
+	rowSum := make([]float64, len(vocab))
+	ctxSum := make([]float64, len(vocab))
+	var total, ctxTotal float64
+	for a, row := range cooc {
+		for _, c := range row {
+			rowSum[a] += c
+			total += c
+		}
+	}
+	for b := range ctxSum {
+		ctxSum[b] = math.Pow(rowSum[b], 0.75)
+		ctxTotal += ctxSum[b]
+	}
+	m := make([]sparseRow, len(vocab))
+	for a, row := range cooc {
+		cols := make([]int, 0, len(row))
+		for b := range row {
+			cols = append(cols, b)
+		}
+		sort.Ints(cols)
+		for _, b := range cols {
+			pmi := math.Log(row[b] / total / (rowSum[a] / total * ctxSum[b] / ctxTotal))
+			if pmi > 0 {
+				m[a] = append(m[a], struct {
+					col int
+					val float64
+				}{b, pmi})
+			}
+		}
+	}
+
+	u, s := truncatedSVD(m, dim)
+	vectors := make(map[string][]float32, len(vocab))
+	for i, w := range vocab {
+		vec := make([]float32, dim)
+		for k := range dim {
+			vec[k] = float32(u[i][k] * math.Sqrt(s[k]))
+		}
+		if normalize(vec) {
+			vectors[w] = vec
+		}
+	}
+	return vectors
+}
+
+// truncatedSVD returns the top k singular vectors and values of the square matrix m,
+// which is symmetric up to the smoothing of its columns, by randomized subspace iteration
+// (Halko et al.): the range of m is found from a random projection refined by a few
+// power iterations, and the small projected matrix is diagonalized exactly.
+func truncatedSVD(m []sparseRow, k int) ([][]float64, []float64) {
+	const (
+		oversample = 16
+		iterations = 4
+	)
+	n := len(m)
+	l := min(k+oversample, n)
+	rng := rand.New(rand.NewSource(1))
+	q := make([][]float64, n)
+	for i := range q {
+		q[i] = make([]float64, l)
+		for j := range q[i] {
+			q[i][j] = rng.NormFloat64()
+		}
+	}
+
+	// Power iterations on m·mᵀ, whose eigenvectors are m's left singular vectors.
+	for range iterations {
+		q = mul(m, mulT(m, orthonormalize(q)))
+	}
+	q = orthonormalize(q)
+
+	// b = qᵀ·m·mᵀ·q is small; its eigenvectors rotate q onto the singular vectors.
+	mtq := mulT(m, q) // mᵀ·q
+	b := make([][]float64, l)
+	for i := range b {
+		b[i] = make([]float64, l)
+		for j := range b[i] {
+			for r := range n {
+				b[i][j] += mtq[r][i] * mtq[r][j]
+			}
+		}
+	}
+	values, vectors := symmetricEigen(b)
+
+	order := make([]int, l)
+	for i := range order {
+		order[i] = i
+	}
+	sort.Slice(order, func(a, c int) bool { return values[order[a]] > values[order[c]] })
+
+	u := make([][]float64, n)
+	for r := range n {
+		u[r] = make([]float64, k)
+		for c := range k {
+			for j := range l {
+				u[r][c] += q[r][j] * vectors[j][order[c]]
+			}
+		}
+	}
+	s := make([]float64, k)
+	for c := range k {
+		s[c] = math.Sqrt(max(values[order[c]], 0))
+	}
+	return u, s
+}
+
+// mul returns m·x for a dense n×l matrix x.
+func mul(m []sparseRow, x [][]float64) [][]float64 {
+	out := make([][]float64, len(m))
+	for r, row := range m {
+		out[r] = make([]float64, len(x[0]))
+		for _, e := range row {
+			for j, v := range x[e.col] {
+				out[r][j] += e.val * v
+			}
+		}
+	}
+	return out
+}
+
+// mulT returns mᵀ·x for a dense n×l matrix x.
+func mulT(m []sparseRow, x [][]float64) [][]float64 {
+	out := make([][]float64, len(m))
+	for r := range out {
+		out[r] = make([]float64, len(x[0]))
+	}
+	for r, row := range m {
+		for _, e := range row {
+			for j, v := range x[r] {
+				out[e.col][j] += e.val * v
+			}
+		}
+	}
+	return out
+}
+
+// orthonormalize makes the columns of x orthonormal in place (modified Gram-Schmidt).
+// A column that becomes zero stays zero.
+func orthonormalize(x [][]float64) [][]float64 {
+	cols := len(x[0])
+	for c := range cols {
+		for p := range c {
+			var dot float64
+			for r := range x {
+				dot += x[r][c] * x[r][p]
+			}
+			for r := range x {
+				x[r][c] -= dot * x[r][p]
+			}
+		}
+		var norm float64
+		for r := range x {
+			norm += x[r][c] * x[r][c]
+		}
+		if norm = math.Sqrt(norm); norm > 1e-12 {
+			for r := range x {
+				x[r][c] /= norm
+			}
+		}
+	}
+	return x
+}
+
+// symmetricEigen diagonalizes the symmetric matrix a with cyclic Jacobi rotations and
+// returns its eigenvalues and the eigenvectors as columns.
+func symmetricEigen(a [][]float64) ([]float64, [][]float64) {
+	n := len(a)
+	v := make([][]float64, n)
+	for i := range v {
+		v[i] = make([]float64, n)
+		v[i][i] = 1
+	}
+	for range 50 {
+		var off float64
+		for i := range n {
+			for j := i + 1; j < n; j++ {
+				off += a[i][j] * a[i][j]
+			}
+		}
+		if off < 1e-18 {
+			break
+		}
+		for p := range n {
+			for q := p + 1; q < n; q++ {
+				if math.Abs(a[p][q]) < 1e-15 {
+					continue
+				}
+				theta := (a[q][q] - a[p][p]) / (2 * a[p][q])
+				t := math.Copysign(1, theta) / (math.Abs(theta) + math.Sqrt(theta*theta+1))
+				c := 1 / math.Sqrt(t*t+1)
+				s := t * c
+				for k := range n {
+					akp, akq := a[k][p], a[k][q]
+					a[k][p], a[k][q] = c*akp-s*akq, s*akp+c*akq
+				}
+				for k := range n {
+					apk, aqk := a[p][k], a[q][k]
+					a[p][k], a[q][k] = c*apk-s*aqk, s*apk+c*aqk
+				}
+				for k := range n {
+					vkp, vkq := v[k][p], v[k][q]
+					v[k][p], v[k][q] = c*vkp-s*vkq, s*vkp+c*vkq
+				}
+			}
+		}
+	}
+	values := make([]float64, n)
+	for i := range n {
+		values[i] = a[i][i]
+	}
+	return values, v
+}
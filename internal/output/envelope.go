@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"io"
 	"os"
+	"sync"
 
 	"github.com/zeropsio/zaia/internal/platform"
 )
@@ -119,6 +120,18 @@ func ErrTo(w io.Writer, code, message, suggestion string, ctx interface{}) error
 	return &ZaiaError{Message: message, Code: code}
 }
 
+// Raw writes data to stdout unchanged, followed by a newline.
+// Used by report formats that replace the JSON envelope (e.g. SARIF).
+func Raw(data []byte) error {
+	writeMu.Lock()
+	defer writeMu.Unlock()
+	if _, err := writer.Write(data); err != nil {
+		return err
+	}
+	_, err := io.WriteString(writer, "\n")
+	return err
+}
+
 // MapProcessToOutput converts a platform.Process to an output ProcessOutput.
 // Maps API status names to ZAIA status names.
 func MapProcessToOutput(p *platform.Process, hostname string) ProcessOutput {
@@ -157,7 +170,12 @@ func mapProcessStatus(apiStatus string) string {
 	}
 }
 
+// writeMu keeps lines whole when progress events are written from several goroutines.
+var writeMu sync.Mutex
+
 func writeJSON(v interface{}) error {
+	writeMu.Lock()
+	defer writeMu.Unlock()
 	return writeJSONTo(writer, v)
 }
 
@@ -318,6 +318,7 @@ func TestZaiaError_ExitCodes(t *testing.T) {
 		{"CONFIRM_REQUIRED", 3},
 		{"INVALID_ZEROPS_YML", 3},
 		{"INVALID_IMPORT_YML", 3},
+		{"KNOWLEDGE_LINT_FAILED", 3},
 		{"INVALID_SCALING", 3},
 		{"INVALID_PARAMETER", 3},
 		{"INVALID_ENV_FORMAT", 3},
@@ -326,6 +327,9 @@ func TestZaiaError_ExitCodes(t *testing.T) {
 		{"PROCESS_NOT_FOUND", 4},
 		{"PERMISSION_DENIED", 5},
 		{"NETWORK_ERROR", 6},
+		{"PROCESS_FAILED", 1},
+		{"WAIT_TIMEOUT", 1},
+		{"JOURNAL_ERROR", 1},
 		{"API_ERROR", 1},
 		{"API_TIMEOUT", 1},
 		{"UNKNOWN_CODE", 1},
@@ -340,3 +344,44 @@ func TestZaiaError_ExitCodes(t *testing.T) {
 		})
 	}
 }
+
+func TestRaw_WritesUnchanged(t *testing.T) {
+	var buf bytes.Buffer
+	SetWriter(&buf)
+	defer ResetWriter()
+
+	if err := Raw([]byte("<testsuites/>")); err != nil {
+		t.Fatal(err)
+	}
+	if buf.String() != "<testsuites/>\n" {
+		t.Errorf("output = %q, want raw data with trailing newline", buf.String())
+	}
+}
+
+func TestEvent_OneLinePerEvent(t *testing.T) {
+	var buf bytes.Buffer
+	SetWriter(&buf)
+	defer ResetWriter()
+
+	if err := Event(EventStatus, ProcessOutput{ProcessID: "p1", Status: "RUNNING"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := Event(EventHeartbeat, map[string]interface{}{"waiting": []string{"p1"}}); err != nil {
+		t.Fatal(err)
+	}
+
+	lines := bytes.Split(bytes.TrimSpace(buf.Bytes()), []byte("\n"))
+	if len(lines) != 2 {
+		t.Fatalf("lines = %d, want 2", len(lines))
+	}
+	var resp EventResponse
+	if err := json.Unmarshal(lines[0], &resp); err != nil {
+		t.Fatal(err)
+	}
+	if resp.Type != "event" || resp.Event != EventStatus || resp.Time == "" {
+		t.Errorf("event = %+v", resp)
+	}
+	if resp.Data.(map[string]interface{})["processId"] != "p1" {
+		t.Errorf("data = %v, want process p1", resp.Data)
+	}
+}
@@ -0,0 +1,28 @@
+package output
+
+import "time"
+
+// Event names of a --stream progress stream.
+const (
+	EventStatus    = "status"    // a process changed status
+	EventHeartbeat = "heartbeat" // still waiting; sent periodically while nothing changes
+)
+
+// EventResponse is one line of a newline-delimited JSON progress stream.
+// The stream ends with a regular sync or error envelope.
+type EventResponse struct {
+	Type  string      `json:"type"`  // always "event"
+	Event string      `json:"event"` // EventStatus or EventHeartbeat
+	Time  string      `json:"time"`  // RFC 3339, UTC
+	Data  interface{} `json:"data,omitempty"`
+}
+
+// Event writes one progress event line to stdout. Safe for concurrent use.
+func Event(event string, data interface{}) error {
+	return writeJSON(EventResponse{
+		Type:  "event",
+		Event: event,
+		Time:  time.Now().UTC().Format(time.RFC3339),
+		Data:  data,
+	})
+}
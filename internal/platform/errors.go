@@ -22,14 +22,19 @@ const (
 	ErrZeropsYmlNotFound      = "ZEROPS_YML_NOT_FOUND"
 	ErrInvalidZeropsYml       = "INVALID_ZEROPS_YML"
 	ErrInvalidImportYml       = "INVALID_IMPORT_YML"
+	ErrKnowledgeLintFailed    = "KNOWLEDGE_LINT_FAILED"
 	ErrImportHasProject       = "IMPORT_HAS_PROJECT"
 	ErrInvalidScaling         = "INVALID_SCALING"
 	ErrInvalidParameter       = "INVALID_PARAMETER"
 	ErrInvalidEnvFormat       = "INVALID_ENV_FORMAT"
 	ErrInvalidHostname        = "INVALID_HOSTNAME"
 	ErrUnknownType            = "UNKNOWN_TYPE"
+	ErrServiceTypeNotFound    = "SERVICE_TYPE_NOT_FOUND"
 	ErrProcessNotFound        = "PROCESS_NOT_FOUND"
 	ErrProcessAlreadyTerminal = "PROCESS_ALREADY_TERMINAL"
+	ErrProcessFailed          = "PROCESS_FAILED"
+	ErrWaitTimeout            = "WAIT_TIMEOUT"
+	ErrJournalError           = "JOURNAL_ERROR"
 	ErrPermissionDenied       = "PERMISSION_DENIED"
 	ErrAPIError               = "API_ERROR"
 	ErrAPITimeout             = "API_TIMEOUT"
@@ -99,11 +104,11 @@ func ExitCodeForError(code string) int {
 		ErrTokenNoProject, ErrTokenMultiProject:
 		return 2
 	case ErrServiceRequired, ErrConfirmRequired, ErrFileNotFound, ErrZeropsYmlNotFound,
-		ErrInvalidZeropsYml, ErrInvalidImportYml, ErrImportHasProject,
+		ErrInvalidZeropsYml, ErrInvalidImportYml, ErrImportHasProject, ErrKnowledgeLintFailed,
 		ErrInvalidScaling, ErrInvalidParameter, ErrInvalidEnvFormat,
 		ErrInvalidHostname, ErrUnknownType, ErrInvalidUsage:
 		return 3
-	case ErrServiceNotFound, ErrProcessNotFound, ErrProcessAlreadyTerminal:
+	case ErrServiceNotFound, ErrProcessNotFound, ErrProcessAlreadyTerminal, ErrServiceTypeNotFound:
 		return 4
 	case ErrPermissionDenied:
 		return 5
@@ -85,6 +85,7 @@ func TestExitCodeForError(t *testing.T) {
 		{ErrServiceNotFound, 4},
 		{ErrProcessNotFound, 4},
 		{ErrProcessAlreadyTerminal, 4},
+		{ErrServiceTypeNotFound, 4},
 		{ErrPermissionDenied, 5},
 		{ErrNetworkError, 6},
 		{ErrAPIError, 1},
@@ -6,8 +6,6 @@ import (
 	"sync"
 )
 
-const statusCancelled = "CANCELLED"
-
 // Compile-time interface check
 var _ Client = (*Mock)(nil)
 
@@ -21,6 +19,7 @@ type Mock struct {
 	services         []ServiceStack
 	service          *ServiceStack
 	processes        map[string]*Process
+	progress         map[string][]string // processID -> statuses still to report
 	envVars          map[string][]EnvVar // serviceID -> env vars
 	projectEnv       []EnvVar
 	logAccess        *LogAccess
@@ -36,6 +35,7 @@ type Mock struct {
 func NewMock() *Mock {
 	return &Mock{
 		processes: make(map[string]*Process),
+		progress:  make(map[string][]string),
 		envVars:   make(map[string][]EnvVar),
 		errors:    make(map[string]error),
 	}
@@ -89,6 +89,15 @@ func (m *Mock) WithProcess(process *Process) *Mock {
 	return m
 }
 
+// WithProcessProgress makes GetProcess report the process through statuses, one per call;
+// the last status sticks. The process must be added with WithProcess.
+func (m *Mock) WithProcessProgress(processID string, statuses ...string) *Mock {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.progress[processID] = statuses
+	return m
+}
+
 // WithServiceEnv sets env vars for a service.
 func (m *Mock) WithServiceEnv(serviceID string, vars []EnvVar) *Mock {
 	m.mu.Lock()
@@ -346,13 +355,21 @@ func (m *Mock) GetProcess(_ context.Context, processID string) (*Process, error)
 	if err := m.getError("GetProcess"); err != nil {
 		return nil, err
 	}
-	m.mu.RLock()
-	defer m.mu.RUnlock()
+	m.mu.Lock()
+	defer m.mu.Unlock()
 	p, ok := m.processes[processID]
 	if !ok {
 		return nil, fmt.Errorf("mock: process %s not found", processID)
 	}
-	return p, nil
+	if steps := m.progress[processID]; len(steps) > 0 {
+		p.Status = steps[0]
+		if len(steps) > 1 {
+			m.progress[processID] = steps[1:]
+		}
+	}
+	// A copy, so callers polling concurrently never share state with later calls.
+	cp := *p
+	return &cp, nil
 }
 
 func (m *Mock) CancelProcess(_ context.Context, processID string) (*Process, error) {
@@ -365,7 +382,7 @@ func (m *Mock) CancelProcess(_ context.Context, processID string) (*Process, err
 	if !ok {
 		return nil, fmt.Errorf("mock: process %s not found", processID)
 	}
-	p.Status = statusCancelled
+	p.Status = ProcessCancelled
 	return p, nil
 }
 
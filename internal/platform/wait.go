@@ -0,0 +1,90 @@
+package platform
+
+import (
+	"context"
+	"time"
+)
+
+// Process statuses as returned by the API.
+const (
+	ProcessPending   = "PENDING"
+	ProcessRunning   = "RUNNING"
+	ProcessDone      = "DONE"
+	ProcessFailed    = "FAILED"
+	ProcessCancelled = "CANCELLED"
+)
+
+// Terminal process statuses as reported by ZeropsClient, which maps DONE and CANCELLED.
+const (
+	ProcessFinished = "FINISHED"
+	ProcessCanceled = "CANCELED"
+)
+
+// Default polling of WaitProcess.
+const (
+	DefaultWaitInterval    = 2 * time.Second
+	DefaultWaitMaxInterval = 30 * time.Second
+)
+
+// IsTerminal reports whether a process in status will not change any more.
+// Both the raw API statuses and the mapped ones returned by ZeropsClient are accepted.
+func IsTerminal(status string) bool {
+	switch status {
+	case ProcessDone, ProcessFinished, ProcessFailed, ProcessCancelled, ProcessCanceled:
+		return true
+	}
+	return false
+}
+
+// WaitOptions control how WaitProcess polls.
+type WaitOptions struct {
+	// Interval is the delay before the second poll; it doubles after every poll
+	// up to MaxInterval. Zero means DefaultWaitInterval.
+	Interval time.Duration
+	// MaxInterval caps the delay between polls. Zero means DefaultWaitMaxInterval,
+	// or Interval when that is longer.
+	MaxInterval time.Duration
+	// OnPoll, if set, is called with every polled state, including the final one.
+	OnPoll func(*Process)
+}
+
+// WaitProcess polls the process until it reaches a terminal status (see IsTerminal)
+// and returns it. The first poll is immediate. When ctx ends first, WaitProcess returns the
+// last polled state with ctx.Err(); a failed poll returns its error.
+func WaitProcess(ctx context.Context, client Client, processID string, opts WaitOptions) (*Process, error) {
+	interval := opts.Interval
+	if interval <= 0 {
+		interval = DefaultWaitInterval
+	}
+	maxInterval := opts.MaxInterval
+	if maxInterval <= 0 {
+		maxInterval = max(DefaultWaitMaxInterval, interval)
+	}
+
+	var last *Process
+	for {
+		p, err := client.GetProcess(ctx, processID)
+		if err != nil {
+			if ctx.Err() != nil {
+				return last, ctx.Err()
+			}
+			return last, err
+		}
+		last = p
+		if opts.OnPoll != nil {
+			opts.OnPoll(p)
+		}
+		if IsTerminal(p.Status) {
+			return p, nil
+		}
+
+		timer := time.NewTimer(interval)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return last, ctx.Err()
+		case <-timer.C:
+		}
+		interval = min(interval*2, maxInterval)
+	}
+}
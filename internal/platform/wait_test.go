@@ -0,0 +1,90 @@
+package platform
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestWaitProcess_Progress(t *testing.T) {
+	mock := NewMock().
+		WithProcess(&Process{ID: "p1", Status: ProcessPending}).
+		WithProcessProgress("p1", ProcessPending, ProcessRunning, ProcessDone)
+
+	var seen []string
+	p, err := WaitProcess(context.Background(), mock, "p1", WaitOptions{
+		Interval: time.Millisecond,
+		OnPoll:   func(p *Process) { seen = append(seen, p.Status) },
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.Status != ProcessDone {
+		t.Errorf("Status = %s, want DONE", p.Status)
+	}
+	if len(seen) != 3 {
+		t.Errorf("polls = %v, want 3", seen)
+	}
+}
+
+// ZeropsClient maps DONE to FINISHED, so WaitProcess must stop on the mapped status too.
+func TestWaitProcess_MappedStatus(t *testing.T) {
+	for _, status := range []string{ProcessFinished, ProcessCanceled} {
+		mock := NewMock().
+			WithProcess(&Process{ID: "p1", Status: ProcessPending}).
+			WithProcessProgress("p1", ProcessRunning, status)
+
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		p, err := WaitProcess(ctx, mock, "p1", WaitOptions{Interval: time.Millisecond})
+		cancel()
+		if err != nil {
+			t.Fatalf("%s: %v", status, err)
+		}
+		if p.Status != status {
+			t.Errorf("Status = %s, want %s", p.Status, status)
+		}
+	}
+}
+
+func TestWaitProcess_Backoff(t *testing.T) {
+	mock := NewMock().WithProcess(&Process{ID: "p1", Status: ProcessRunning})
+
+	var polls []time.Time
+	ctx, cancel := context.WithTimeout(context.Background(), 70*time.Millisecond)
+	defer cancel()
+	p, err := WaitProcess(ctx, mock, "p1", WaitOptions{
+		Interval:    5 * time.Millisecond,
+		MaxInterval: 20 * time.Millisecond,
+		OnPoll:      func(*Process) { polls = append(polls, time.Now()) },
+	})
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("err = %v, want deadline exceeded", err)
+	}
+	if p == nil || p.Status != ProcessRunning {
+		t.Errorf("last state = %v, want RUNNING", p)
+	}
+	// Polls at 0, 5, 15, 35 and 55ms as intervals double up to the cap; a fixed 5ms
+	// interval would poll 14 times.
+	if len(polls) < 2 || len(polls) > 5 {
+		t.Errorf("polled %d times in 70ms, want at most 5", len(polls))
+	}
+}
+
+func TestWaitProcess_Error(t *testing.T) {
+	if _, err := WaitProcess(context.Background(), NewMock(), "missing", WaitOptions{}); err == nil {
+		t.Fatal("expected error for missing process")
+	}
+}
+
+func TestIsTerminal(t *testing.T) {
+	for status, want := range map[string]bool{
+		ProcessPending: false, ProcessRunning: false,
+		ProcessDone: true, ProcessFailed: true, ProcessCancelled: true,
+		ProcessFinished: true, ProcessCanceled: true,
+	} {
+		if got := IsTerminal(status); got != want {
+			t.Errorf("IsTerminal(%s) = %v, want %v", status, got, want)
+		}
+	}
+}
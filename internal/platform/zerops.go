@@ -647,7 +647,7 @@ func mapProcess(p output.Process) Process {
 	switch status {
 	case "DONE":
 		status = "FINISHED"
-	case statusCancelled:
+	case ProcessCancelled:
 		status = "CANCELED"
 	}
 
@@ -860,7 +860,7 @@ func mapEsProcessEvent(p output.EsProcess) ProcessEvent {
 	switch status {
 	case "DONE":
 		status = "FINISHED"
-	case statusCancelled:
+	case ProcessCancelled:
 		status = "CANCELED"
 	}
 
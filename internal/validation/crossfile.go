@@ -0,0 +1,225 @@
+package validation
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+
+	"gopkg.in/yaml.v3"
+)
+
+// CrossFileRules are the rules applied by CrossCheck. Findings carry the file they point into.
+var CrossFileRules = []Rule{
+	{
+		ID:          "dangling-setup",
+		Severity:    SeverityWarning,
+		Description: "zerops.yml setup has no matching service hostname in import.yml",
+	},
+	{
+		ID:          "unused-service",
+		Severity:    SeverityInfo,
+		Description: "Runtime service in import.yml has no zerops.yml setup to deploy",
+	},
+	{
+		ID:          "unresolved-env-ref",
+		Severity:    SeverityWarning,
+		Description: "Env reference ${hostname_VAR} points at a service that is not defined",
+	},
+}
+
+// serviceRefPattern matches cross-service env references like ${db_connectionString}.
+// Hostnames cannot contain underscores, so the first one separates hostname and variable.
+// Project variables can contain underscores too (${app_version}), so a match is only
+// known to be a service reference when the prefix is a hostname or the variable is in
+// serviceVars.
+var serviceRefPattern = regexp.MustCompile(`\$\{([a-z][a-z0-9]*)_([A-Za-z0-9_]+)\}`)
+
+// serviceVars are the env variables Zerops generates for services, which a reference to a
+// missing hostname most likely meant.
+var serviceVars = map[string]bool{
+	"hostname": true, "serviceId": true, "port": true, "connectionString": true,
+	"user": true, "password": true, "superUser": true, "superUserPassword": true, "dbName": true,
+	"apiUrl": true, "apiKey": true, "masterKey": true,
+	"accessKeyId": true, "secretAccessKey": true, "bucketName": true,
+}
+
+// CrossCheck compares a zerops.yml with the import.yml describing the same project.
+// Both files are expected to be schema-valid; unparsable input yields no findings.
+// zaia-ignore comments in either file suppress rules for the whole check.
+func CrossCheck(zeropsFile string, zeropsYml []byte, importFile string, importYml []byte) []Finding {
+	zDoc, err := parseDocument(zeropsYml)
+	if err != nil {
+		return nil
+	}
+	iDoc, err := parseDocument(importYml)
+	if err != nil {
+		return nil
+	}
+	zRoot, iRoot := documentRoot(zDoc), documentRoot(iDoc)
+	suppressed, _ := suppressions(zDoc)
+	importSuppressed, _ := suppressions(iDoc)
+	for id := range importSuppressed {
+		suppressed[id] = true
+	}
+
+	c := &crossChecker{suppressed: suppressed, hostnames: make(map[string]bool)}
+	c.collect(zRoot, iRoot)
+	c.checkSetups(zeropsFile, zRoot)
+	c.checkServices(importFile, iRoot)
+	c.checkZeropsRefs(zeropsFile, zRoot)
+	c.checkImportRefs(importFile, iRoot)
+
+	sort.SliceStable(c.findings, func(i, j int) bool {
+		if c.findings[i].File != c.findings[j].File {
+			return c.findings[i].File == zeropsFile
+		}
+		return c.findings[i].Line < c.findings[j].Line
+	})
+	return c.findings
+}
+
+type crossChecker struct {
+	suppressed map[string]bool
+	hostnames  map[string]bool
+	hostList   []string
+	setups     map[string]bool
+	// deployed holds the setup names import.yml services deploy: zeropsSetup, or the hostname.
+	deployed map[string]bool
+	findings []Finding
+}
+
+func (c *crossChecker) report(ruleID, file string, n *yaml.Node, path, msg, fix string) {
+	if c.suppressed[ruleID] {
+		return
+	}
+	for _, rule := range CrossFileRules {
+		if rule.ID == ruleID {
+			c.findings = append(c.findings, Finding{
+				Rule: rule.ID, Severity: rule.Severity, File: file,
+				Path: path, Message: msg, Fix: fix, Line: n.Line, Column: n.Column,
+			})
+			return
+		}
+	}
+}
+
+func (c *crossChecker) collect(zRoot, iRoot *yaml.Node) {
+	c.setups = make(map[string]bool)
+	c.deployed = make(map[string]bool)
+	forEachEntry(zRoot, "zerops", func(svc *yaml.Node, _ string) {
+		if s := mappingValue(svc, "setup"); s != nil {
+			c.setups[s.Value] = true
+		}
+	})
+	forEachEntry(iRoot, "services", func(svc *yaml.Node, _ string) {
+		h := mappingValue(svc, "hostname")
+		if h == nil {
+			return
+		}
+		c.hostnames[h.Value] = true
+		c.hostList = append(c.hostList, h.Value)
+		if s := mappingValue(svc, "zeropsSetup"); s != nil {
+			c.deployed[s.Value] = true
+		} else {
+			c.deployed[h.Value] = true
+		}
+	})
+}
+
+func (c *crossChecker) checkSetups(file string, root *yaml.Node) {
+	forEachEntry(root, "zerops", func(svc *yaml.Node, path string) {
+		s := mappingValue(svc, "setup")
+		if s == nil || c.deployed[s.Value] {
+			return
+		}
+		fix := fmt.Sprintf("Add a service with hostname: %s to import.yml, or rename the setup", s.Value)
+		if h := Closest(s.Value, c.hostList); h != "" {
+			fix = fmt.Sprintf("Did you mean '%s'?", h)
+		}
+		c.report("dangling-setup", file, s, path+".setup",
+			fmt.Sprintf("Setup '%s' does not match any service in import.yml", s.Value), fix)
+	})
+}
+
+func (c *crossChecker) checkServices(file string, root *yaml.Node) {
+	forEachEntry(root, "services", func(svc *yaml.Node, path string) {
+		h, t := mappingValue(svc, "hostname"), mappingValue(svc, "type")
+		if h == nil || t == nil || !runtimeServices[serviceTypeName(t.Value)] {
+			return
+		}
+		setup := h.Value
+		if s := mappingValue(svc, "zeropsSetup"); s != nil {
+			setup = s.Value
+		}
+		if c.setups[setup] {
+			return
+		}
+		c.report("unused-service", file, h, path+".hostname",
+			fmt.Sprintf("Service '%s' has no setup '%s' in zerops.yml", h.Value, setup),
+			fmt.Sprintf("Add to zerops.yml: - setup: %s", setup))
+	})
+}
+
+func (c *crossChecker) checkZeropsRefs(file string, root *yaml.Node) {
+	forEachEntry(root, "zerops", func(svc *yaml.Node, path string) {
+		for _, section := range []string{"build", "run"} {
+			if sec := mappingValue(svc, section); sec != nil {
+				c.checkRefs(file, mappingValue(sec, "envVariables"), path+"."+section+".envVariables")
+			}
+		}
+	})
+}
+
+func (c *crossChecker) checkImportRefs(file string, root *yaml.Node) {
+	forEachEntry(root, "services", func(svc *yaml.Node, path string) {
+		c.checkRefs(file, mappingValue(svc, "envVariables"), path+".envVariables")
+		c.checkRefs(file, mappingValue(svc, "envSecrets"), path+".envSecrets")
+	})
+}
+
+// checkRefs reports ${hostname_VAR} references to hostnames missing from import.yml.
+// References to other variables, such as ${app_version}, may be project variables and
+// are left to --live.
+func (c *crossChecker) checkRefs(file string, vars *yaml.Node, path string) {
+	for _, ref := range ServiceRefs(vars, path) {
+		if c.hostnames[ref.Hostname] || !serviceVars[ref.Variable] {
+			continue
+		}
+		fix := "Define a service with hostname '" + ref.Hostname + "' in import.yml"
+		if h := Closest(ref.Hostname, c.hostList); h != "" {
+			fix = fmt.Sprintf("Did you mean '${%s_%s}'?", h, ref.Variable)
+		}
+		c.report("unresolved-env-ref", file, ref.Node, ref.Path,
+			fmt.Sprintf("'%s' references unknown service '%s'", ref.Ref, ref.Hostname), fix)
+	}
+}
+
+// ServiceRef is a ${hostname_VAR} reference found in an env variable value.
+type ServiceRef struct {
+	Ref      string
+	Hostname string
+	Variable string
+	Path     string
+	Node     *yaml.Node
+}
+
+// ServiceRefs returns the cross-service references in the values of an env variable mapping.
+func ServiceRefs(vars *yaml.Node, path string) []ServiceRef {
+	if vars == nil || vars.Kind != yaml.MappingNode {
+		return nil
+	}
+	var refs []ServiceRef
+	for i := 0; i+1 < len(vars.Content); i += 2 {
+		key, val := vars.Content[i], resolveAlias(vars.Content[i+1])
+		if val.Kind != yaml.ScalarNode {
+			continue
+		}
+		for _, m := range serviceRefPattern.FindAllStringSubmatch(val.Value, -1) {
+			refs = append(refs, ServiceRef{
+				Ref: m[0], Hostname: m[1], Variable: m[2],
+				Path: joinPath(path, key.Value), Node: val,
+			})
+		}
+	}
+	return refs
+}
@@ -0,0 +1,87 @@
+package validation
+
+import (
+	"testing"
+)
+
+const crossZeropsYml = `zerops:
+  - setup: api
+    run:
+      envVariables:
+        DB_URL: ${db_connectionString}
+        CACHE: redis://${cahce_hostname}:6379
+  - setup: worker
+    run:
+      start: node worker.js
+`
+
+const crossImportYml = `services:
+  - hostname: api
+    type: nodejs@22
+  - hostname: web
+    type: static
+  - hostname: db
+    type: postgresql@16
+    mode: NON_HA
+  - hostname: cache
+    type: valkey@7.2
+    mode: NON_HA
+    envVariables:
+      PEER: ${api_hostname}
+`
+
+func TestCrossCheck(t *testing.T) {
+	findings := CrossCheck("zerops.yml", []byte(crossZeropsYml), "import.yml", []byte(crossImportYml))
+
+	type key struct{ rule, path string }
+	want := map[key]string{
+		{"dangling-setup", "zerops[1].setup"}:                      "",
+		{"unresolved-env-ref", "zerops[0].run.envVariables.CACHE"}: "Did you mean '${cache_hostname}'?",
+		{"unused-service", "services[1].hostname"}:                 "",
+	}
+	if len(findings) != len(want) {
+		t.Fatalf("expected %d findings, got %+v", len(want), findings)
+	}
+	for _, f := range findings {
+		fix, ok := want[key{f.Rule, f.Path}]
+		if !ok {
+			t.Errorf("unexpected finding %+v", f)
+			continue
+		}
+		if fix != "" && f.Fix != fix {
+			t.Errorf("%s fix = %q, want %q", f.Rule, f.Fix, fix)
+		}
+		if f.File == "" || f.Line == 0 {
+			t.Errorf("finding %+v lacks file or position", f)
+		}
+	}
+}
+
+func TestCrossCheck_ZeropsSetupAndSuppression(t *testing.T) {
+	zeropsYml := "# zaia-ignore: unused-service\nzerops:\n  - setup: prod\n"
+	importYml := "services:\n  - hostname: app\n    type: go@1\n    zeropsSetup: prod\n  - hostname: other\n    type: go@1\n"
+	if findings := CrossCheck("z", []byte(zeropsYml), "i", []byte(importYml)); len(findings) != 0 {
+		t.Errorf("expected no findings, got %+v", findings)
+	}
+}
+
+// Project variables may contain underscores; only known service variables of a missing
+// hostname are reported offline.
+func TestCrossCheck_ProjectVarWithUnderscore(t *testing.T) {
+	zeropsYml := `zerops:
+  - setup: api
+    run:
+      envVariables:
+        VERSION: ${app_version}
+        DB_HOST: ${db_host}
+        DB_PASS: ${dbb_password}
+`
+	importYml := "services:\n  - hostname: api\n    type: nodejs@22\n  - hostname: db\n    type: postgresql@16\n"
+	findings := CrossCheck("z", []byte(zeropsYml), "i", []byte(importYml))
+	if len(findings) != 1 || findings[0].Path != "zerops[0].run.envVariables.DB_PASS" {
+		t.Fatalf("expected only the DB_PASS finding, got %+v", findings)
+	}
+	if findings[0].Fix != "Did you mean '${db_password}'?" {
+		t.Errorf("fix = %q", findings[0].Fix)
+	}
+}
@@ -0,0 +1,369 @@
+package validation
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// AppliedFix describes one mechanical change made by FixZeropsYml or FixImportYml.
+type AppliedFix struct {
+	Path        string `json:"path"`
+	Description string `json:"description"`
+	Line        int    `json:"line,omitempty"`
+}
+
+// FixZeropsYml applies mechanical fixes to zerops.yml content:
+// a single service under zerops is wrapped into an array and base versions are normalized.
+// Only the fixed spans change; every other byte is kept. Content is returned unchanged
+// when nothing was fixed.
+func FixZeropsYml(content []byte) ([]byte, []AppliedFix, error) {
+	return fixDocument(content, func(root *yaml.Node, f *fixer) {
+		if i := keyIndex(root, "zerops"); i >= 0 {
+			z := root.Content[i+1]
+			if z.Kind != yaml.SequenceNode && z.Kind != yaml.AliasNode && z.ShortTag() != "!!null" &&
+				f.wrapInArray(z, nextKeyLine(root, i)) {
+				f.add(z, "zerops", "Wrapped 'zerops' into an array")
+				wrapped := *z
+				*z = yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq", Content: []*yaml.Node{&wrapped}, Line: z.Line, Column: z.Column}
+			}
+		}
+		forEachEntry(root, "zerops", func(svc *yaml.Node, path string) {
+			for _, section := range []string{"build", "run"} {
+				sec := mappingValue(svc, section)
+				if sec == nil {
+					continue
+				}
+				for _, base := range scalarValues(mappingValue(sec, "base")) {
+					if norm := normalizeVersionSyntax(base.Value); norm != base.Value {
+						f.replaceScalar(base, norm, path+"."+section+".base",
+							fmt.Sprintf("Normalized '%s' to '%s'", base.Value, norm))
+					}
+				}
+			}
+		})
+	})
+}
+
+// FixImportYml applies mechanical fixes to import.yml content: the project: section
+// is removed, name: becomes hostname: and service types are normalized.
+// Only the fixed spans change; every other byte is kept. Content is returned unchanged
+// when nothing was fixed.
+func FixImportYml(content []byte) ([]byte, []AppliedFix, error) {
+	return fixDocument(content, func(root *yaml.Node, f *fixer) {
+		if i := keyIndex(root, "project"); i >= 0 && f.removeEntry(root.Content[i], nextKeyLine(root, i)) {
+			f.add(root.Content[i], "project", "Removed 'project:' section (not allowed in project-scoped import)")
+			root.Content = append(root.Content[:i], root.Content[i+2:]...)
+		}
+		forEachEntry(root, "services", func(svc *yaml.Node, path string) {
+			if i := keyIndex(svc, "name"); i >= 0 && keyIndex(svc, "hostname") < 0 {
+				f.replaceScalar(svc.Content[i], "hostname", path+".name", "Renamed 'name' to 'hostname'")
+			}
+			if t := mappingValue(svc, "type"); t != nil && t.Kind == yaml.ScalarNode {
+				if norm := NormalizeServiceType(t.Value); norm != t.Value {
+					f.replaceScalar(t, norm, path+".type", fmt.Sprintf("Normalized '%s' to '%s'", t.Value, norm))
+				}
+			}
+		})
+	})
+}
+
+// fixer collects the fixes made to a document as byte-range edits of its source.
+type fixer struct {
+	content    []byte
+	lineStarts []int // byte offset of every line
+	fixes      []AppliedFix
+	edits      []edit
+}
+
+// edit replaces content[start:end] with text; start == end inserts.
+type edit struct {
+	start, end int
+	text       string
+}
+
+func newFixer(content []byte) *fixer {
+	f := &fixer{content: content, lineStarts: []int{0}}
+	for i, c := range content {
+		if c == '\n' {
+			f.lineStarts = append(f.lineStarts, i+1)
+		}
+	}
+	return f
+}
+
+func (f *fixer) add(n *yaml.Node, path, desc string) {
+	f.fixes = append(f.fixes, AppliedFix{Path: path, Description: desc, Line: n.Line})
+}
+
+// apply returns the content with every edit made, later ones first so offsets stay valid.
+func (f *fixer) apply() []byte {
+	edits := slices.Clone(f.edits)
+	sort.SliceStable(edits, func(i, j int) bool {
+		if edits[i].start != edits[j].start {
+			return edits[i].start > edits[j].start
+		}
+		return edits[i].end > edits[j].end
+	})
+	out := slices.Clone(f.content)
+	for _, e := range edits {
+		out = slices.Concat(out[:e.start], []byte(e.text), out[e.end:])
+	}
+	return out
+}
+
+// lineCount returns the number of lines, not counting an empty one after a final newline.
+func (f *fixer) lineCount() int {
+	if n := len(f.lineStarts); f.lineStarts[n-1] == len(f.content) {
+		return n - 1
+	}
+	return len(f.lineStarts)
+}
+
+// line returns the text of the 1-based line without its newline.
+func (f *fixer) line(line int) string {
+	end := len(f.content)
+	if line < len(f.lineStarts) {
+		end = f.lineStarts[line] - 1
+	}
+	return string(f.content[f.lineStarts[line-1]:end])
+}
+
+// lineEnd returns the offset just past the newline of the 1-based line.
+func (f *fixer) lineEnd(line int) int {
+	if line < len(f.lineStarts) {
+		return f.lineStarts[line]
+	}
+	return len(f.content)
+}
+
+// offset converts a 1-based node position to a byte offset; ok is false when it is out of range.
+func (f *fixer) offset(line, column int) (int, bool) {
+	if line < 1 || line > len(f.lineStarts) || column < 1 {
+		return 0, false
+	}
+	text := f.line(line)
+	col := 1
+	for i := range text {
+		if col == column {
+			return f.lineStarts[line-1] + i, true
+		}
+		col++
+	}
+	return 0, false
+}
+
+// replaceScalar rewrites the scalar n to value in place, keeping its quoting, and records the fix.
+// Block scalars and spans that cannot be located are left alone.
+func (f *fixer) replaceScalar(n *yaml.Node, value, path, desc string) {
+	start, end, ok := f.scalarSpan(n)
+	if !ok {
+		return
+	}
+	text := value
+	switch n.Style {
+	case yaml.DoubleQuotedStyle:
+		text = strconv.Quote(value)
+	case yaml.SingleQuotedStyle:
+		text = "'" + strings.ReplaceAll(value, "'", "''") + "'"
+	}
+	f.edits = append(f.edits, edit{start: start, end: end, text: text})
+	f.add(n, path, desc)
+	n.Value = value
+}
+
+// scalarSpan locates the source text of the scalar n, quotes included.
+func (f *fixer) scalarSpan(n *yaml.Node) (start, end int, ok bool) {
+	start, ok = f.offset(n.Line, n.Column)
+	if !ok {
+		return 0, 0, false
+	}
+	rest := f.content[start:]
+	switch n.Style {
+	case yaml.DoubleQuotedStyle, yaml.SingleQuotedStyle:
+		end = quotedEnd(rest)
+	case 0:
+		if bytes.HasPrefix(rest, []byte(n.Value)) {
+			end = len(n.Value)
+		}
+	}
+	if end <= 0 {
+		return 0, 0, false
+	}
+	return start, start + end, true
+}
+
+// wrapInArray turns the value n of a top-level key into a one-item sequence. A block mapping
+// gets "- " before its first key and its following lines (up to nextLine) indented by two
+// spaces; a flow collection or scalar is enclosed in [ ].
+func (f *fixer) wrapInArray(n *yaml.Node, nextLine int) bool {
+	start, ok := f.offset(n.Line, n.Column)
+	if !ok {
+		return false
+	}
+	if n.Kind == yaml.MappingNode && n.Style&yaml.FlowStyle == 0 {
+		f.edits = append(f.edits, edit{start: start, end: start, text: "- "})
+		for line := n.Line + 1; line < min(nextLine, f.lineCount()+1); line++ {
+			text := f.line(line)
+			indent := len(text) - len(strings.TrimLeft(text, " "))
+			if strings.TrimSpace(text) != "" && indent >= n.Column-1 {
+				at := f.lineStarts[line-1]
+				f.edits = append(f.edits, edit{start: at, end: at, text: "  "})
+			}
+		}
+		return true
+	}
+
+	var end int
+	switch {
+	case n.Kind != yaml.ScalarNode:
+		end = flowEnd(f.content[start:])
+	default:
+		_, e, ok := f.scalarSpan(n)
+		if ok {
+			end = e - start
+		}
+	}
+	if end <= 0 {
+		return false
+	}
+	f.edits = append(f.edits, edit{start: start, end: start, text: "["}, edit{start: start + end, end: start + end, text: "]"})
+	return true
+}
+
+// removeEntry deletes the lines of a top-level key and its value, which ends before nextLine.
+// Blank lines and unindented comments right before nextLine are kept with the next key.
+func (f *fixer) removeEntry(key *yaml.Node, nextLine int) bool {
+	if key.Column != 1 || key.Line < 1 || key.Line > len(f.lineStarts) {
+		return false
+	}
+	last := min(nextLine, f.lineCount()+1) - 1
+	for last > key.Line {
+		text := f.line(last)
+		if strings.TrimSpace(text) != "" && !strings.HasPrefix(text, "#") {
+			break
+		}
+		last--
+	}
+	f.edits = append(f.edits, edit{start: f.lineStarts[key.Line-1], end: f.lineEnd(last)})
+	return true
+}
+
+// nextKeyLine returns the line of the key after the i-th key of the mapping root,
+// or math.MaxInt for the last key.
+func nextKeyLine(root *yaml.Node, i int) int {
+	if i+2 < len(root.Content) {
+		return root.Content[i+2].Line
+	}
+	return math.MaxInt
+}
+
+// quotedEnd returns the length of the quoted scalar at the start of b, or 0.
+func quotedEnd(b []byte) int {
+	quote := b[0]
+	for i := 1; i < len(b); i++ {
+		switch {
+		case quote == '"' && b[i] == '\\':
+			i++
+		case b[i] == quote && quote == '\'' && i+1 < len(b) && b[i+1] == '\'':
+			i++
+		case b[i] == quote:
+			return i + 1
+		}
+	}
+	return 0
+}
+
+// flowEnd returns the length of the flow collection at the start of b, or 0.
+func flowEnd(b []byte) int {
+	depth := 0
+	for i := 0; i < len(b); i++ {
+		switch b[i] {
+		case '"', '\'':
+			n := quotedEnd(b[i:])
+			if n == 0 {
+				return 0
+			}
+			i += n - 1
+		case '[', '{':
+			depth++
+		case ']', '}':
+			depth--
+			if depth == 0 {
+				return i + 1
+			}
+		}
+	}
+	return 0
+}
+
+// fixDocument parses content, lets apply record fixes and makes their edits if there are any.
+func fixDocument(content []byte, apply func(root *yaml.Node, f *fixer)) ([]byte, []AppliedFix, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(content, &doc); err != nil {
+		return nil, nil, err
+	}
+	if doc.Kind != yaml.DocumentNode || len(doc.Content) == 0 || doc.Content[0].Kind != yaml.MappingNode ||
+		doc.Content[0].Style&yaml.FlowStyle != 0 {
+		return content, nil, nil
+	}
+	f := newFixer(content)
+	apply(doc.Content[0], f)
+	if len(f.fixes) == 0 {
+		return content, nil, nil
+	}
+	return f.apply(), f.fixes, nil
+}
+
+// keyIndex returns the index of key in a mapping node's Content, or -1.
+func keyIndex(n *yaml.Node, key string) int {
+	if n.Kind != yaml.MappingNode {
+		return -1
+	}
+	for i := 0; i+1 < len(n.Content); i += 2 {
+		if n.Content[i].Value == key {
+			return i
+		}
+	}
+	return -1
+}
+
+// versionSyntaxPattern matches "name@version" written with a different separator or a v prefix:
+// "NodeJS:22", "nodejs 22", "nodejs@v22".
+var versionSyntaxPattern = regexp.MustCompile(`^([A-Za-z][A-Za-z0-9-]*)\s*[@: ]\s*[vV]?([0-9][0-9A-Za-z.]*|latest|stable|nightly|canary)$`)
+
+// normalizeVersionSyntax lowercases the name and rewrites the separator to '@'.
+func normalizeVersionSyntax(s string) string {
+	trimmed := strings.TrimSpace(s)
+	m := versionSyntaxPattern.FindStringSubmatch(trimmed)
+	if m == nil {
+		return s
+	}
+	return strings.ToLower(m[1]) + "@" + m[2]
+}
+
+// NormalizeServiceType rewrites a service type to its canonical name@version form
+// when the intended type is unambiguous, e.g. "Postgres:16" → "postgresql@16".
+// Unrecognized values are returned unchanged.
+func NormalizeServiceType(s string) string {
+	if containsString(serviceTypes, s) {
+		return s
+	}
+	norm := normalizeVersionSyntax(s)
+	if lower := strings.ToLower(strings.TrimSpace(norm)); containsString(serviceTypes, lower) {
+		return lower
+	}
+	// Only the name may be corrected; guessing a different version is not mechanical.
+	_, version, _ := strings.Cut(norm, "@")
+	if c := Closest(norm, serviceTypes); c != "" && strings.HasSuffix(c, "@"+version) {
+		return c
+	}
+	return s
+}
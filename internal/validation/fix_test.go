@@ -0,0 +1,229 @@
+package validation
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestFixZeropsYml_WrapsSingleService(t *testing.T) {
+	content := `# app config
+zerops:
+  setup: api # main service
+  build:
+    base: NodeJS:22
+    deployFiles: dist
+`
+	fixed, fixes, err := FixZeropsYml([]byte(content))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(fixes) != 2 {
+		t.Fatalf("expected 2 fixes, got %+v", fixes)
+	}
+	want := `# app config
+zerops:
+  - setup: api # main service
+    build:
+      base: nodejs@22
+      deployFiles: dist
+`
+	if string(fixed) != want {
+		t.Errorf("fixed content:\n%s\nwant:\n%s", fixed, want)
+	}
+	if issues := ValidateZeropsYml(fixed); len(issues) != 0 {
+		t.Errorf("fixed content still invalid: %+v", issues)
+	}
+}
+
+func TestFixImportYml(t *testing.T) {
+	content := `project:
+  name: demo
+services:
+  # database first
+  - name: db
+    type: Postgres:16
+    mode: NON_HA
+  - hostname: api
+    type: nodejs@99
+`
+	fixed, fixes, err := FixImportYml([]byte(content))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(fixes) != 3 {
+		t.Fatalf("expected 3 fixes, got %+v", fixes)
+	}
+	want := `services:
+  # database first
+  - hostname: db
+    type: postgresql@16
+    mode: NON_HA
+  - hostname: api
+    type: nodejs@99
+`
+	if string(fixed) != want {
+		t.Errorf("fixed content:\n%s\nwant:\n%s", fixed, want)
+	}
+}
+
+func TestFix_KeepsUntouchedLines(t *testing.T) {
+	content := `#   app config, odd spacing kept
+
+zerops:
+    setup: api     #   main service
+
+    build:
+        base: [ php@8.4,   "NodeJS:22" ]   # two bases
+        deployFiles:   dist
+
+
+    run:
+        base: 'php-nginx 8.4'
+# trailing note
+`
+	fixed, fixes, err := FixZeropsYml([]byte(content))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(fixes) != 3 {
+		t.Fatalf("expected 3 fixes, got %+v", fixes)
+	}
+	want := `#   app config, odd spacing kept
+
+zerops:
+    - setup: api     #   main service
+
+      build:
+          base: [ php@8.4,   "nodejs@22" ]   # two bases
+          deployFiles:   dist
+
+
+      run:
+          base: 'php-nginx@8.4'
+# trailing note
+`
+	if string(fixed) != want {
+		t.Errorf("fixed content:\n%s\nwant:\n%s", fixed, want)
+	}
+}
+
+func TestFix_FlowAndScalarWrap(t *testing.T) {
+	tests := map[string]string{
+		"zerops: {setup: api, run: {start: x}} # c\nother: 1\n": "zerops: [{setup: api, run: {start: x}}] # c\nother: 1\n",
+		"zerops: \"api\"\n": "zerops: [\"api\"]\n",
+	}
+	for in, want := range tests {
+		fixed, _, err := FixZeropsYml([]byte(in))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(fixed) != want {
+			t.Errorf("FixZeropsYml(%q) = %q, want %q", in, fixed, want)
+		}
+	}
+}
+
+func TestFixImportYml_RemovesProjectOnly(t *testing.T) {
+	content := `services:
+  - hostname: api
+    type: nodejs@22
+
+project:
+  name: demo   # old
+
+  tags: [a]
+
+# services end here
+`
+	fixed, fixes, err := FixImportYml([]byte(content))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(fixes) != 1 {
+		t.Fatalf("expected 1 fix, got %+v", fixes)
+	}
+	// The blank line and unindented comment after the section are kept.
+	want := `services:
+  - hostname: api
+    type: nodejs@22
+
+
+# services end here
+`
+	if string(fixed) != want {
+		t.Errorf("fixed content:\n%q\nwant:\n%q", fixed, want)
+	}
+}
+
+func TestFix_NothingToFix(t *testing.T) {
+	content := "zerops:\n  - setup: api\n"
+	fixed, fixes, err := FixZeropsYml([]byte(content))
+	if err != nil || len(fixes) != 0 || string(fixed) != content {
+		t.Errorf("expected content unchanged, got %q %+v %v", fixed, fixes, err)
+	}
+}
+
+func TestNormalizeServiceType(t *testing.T) {
+	tests := map[string]string{
+		"nodejs@22":   "nodejs@22",
+		"NodeJS@22":   "nodejs@22",
+		"nodejs:22":   "nodejs@22",
+		"nodejs@v22":  "nodejs@22",
+		"postgres@16": "postgresql@16",
+		"Static":      "static",
+		"nodejs@99":   "nodejs@99",
+		"foo":         "foo",
+	}
+	for in, want := range tests {
+		if got := NormalizeServiceType(in); got != want {
+			t.Errorf("NormalizeServiceType(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestUnifiedDiff(t *testing.T) {
+	a := "a\nb\nc\nd\ne\nf\ng\nh\ni\nj\n"
+	b := "a\nB\nc\nd\ne\nf\ng\nh\ni\nj\nk\n"
+	got := UnifiedDiff("a/f", "b/f", []byte(a), []byte(b))
+	want := `--- a/f
++++ b/f
+@@ -1,5 +1,5 @@
+ a
+-b
++B
+ c
+ d
+ e
+@@ -8,3 +8,4 @@
+ h
+ i
+ j
++k
+`
+	if got != want {
+		t.Errorf("diff:\n%s\nwant:\n%s", got, want)
+	}
+	if UnifiedDiff("a", "b", []byte(a), []byte(a)) != "" {
+		t.Error("expected empty diff for equal input")
+	}
+	if !strings.HasPrefix(UnifiedDiff("a", "b", nil, []byte("x\n")), "--- a\n+++ b\n@@ -0,0 +1 @@\n+x\n") {
+		t.Error("unexpected diff for new content")
+	}
+}
+
+// patch and git apply need the marker to tell a missing final newline from a present one.
+func TestUnifiedDiff_NoNewlineAtEnd(t *testing.T) {
+	const marker = "\\ No newline at end of file\n"
+	tests := []struct{ a, b, want string }{
+		{"x\ny", "x\nz", "@@ -1,2 +1,2 @@\n x\n-y\n" + marker + "+z\n" + marker},
+		{"x\ny", "x\ny\n", "@@ -1,2 +1,2 @@\n x\n-y\n" + marker + "+y\n"},
+		{"x\ny\n", "x\ny", "@@ -1,2 +1,2 @@\n x\n-y\n+y\n" + marker},
+		{"x\ny", "w\nx\ny", "@@ -1,2 +1,3 @@\n+w\n x\n y\n" + marker},
+	}
+	for _, tt := range tests {
+		got := UnifiedDiff("a/f", "b/f", []byte(tt.a), []byte(tt.b))
+		if want := "--- a/f\n+++ b/f\n" + tt.want; got != want {
+			t.Errorf("diff %q → %q:\n%s\nwant:\n%s", tt.a, tt.b, got, want)
+		}
+	}
+}
@@ -0,0 +1,173 @@
+package validation
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+
+	"github.com/zeropsio/zaia/internal/catalog"
+	"gopkg.in/yaml.v3"
+)
+
+// Scaling limits, kept in sync with the ranges documented by `zaia scale`.
+const (
+	minCPU        = 1
+	maxCPU        = 8
+	minRAM        = 0.125
+	maxRAM        = 48
+	minDisk       = 0.5
+	maxDisk       = 250
+	minContainers = 1
+	maxContainers = 10
+)
+
+// hostnamePattern: lowercase letters and digits, starting with a letter.
+var hostnamePattern = regexp.MustCompile(`^[a-z][a-z0-9]*$`)
+
+func verticalAutoscalingSpec() *Spec {
+	return object("Vertical autoscaling limits",
+		Field{Name: "cpuMode", Spec: enum("CPU mode", "SHARED", "DEDICATED")},
+		Field{Name: "minCpu", Spec: integer("Minimum CPU cores", minCPU, maxCPU)},
+		Field{Name: "maxCpu", Spec: integer("Maximum CPU cores", minCPU, maxCPU)},
+		Field{Name: "startCpuCoreCount", Spec: integer("CPU cores at container start", minCPU, maxCPU)},
+		Field{Name: "minFreeCpuCores", Spec: number("Absolute free CPU threshold", 0, maxCPU)},
+		Field{Name: "minFreeCpuPercent", Spec: number("Free CPU threshold in percent", 0, 100)},
+		Field{Name: "minRam", Spec: number("Minimum RAM in GB", minRAM, maxRAM)},
+		Field{Name: "maxRam", Spec: number("Maximum RAM in GB", minRAM, maxRAM)},
+		Field{Name: "minFreeRamGB", Spec: number("Absolute free RAM threshold in GB", 0, maxRAM)},
+		Field{Name: "minFreeRamPercent", Spec: number("Free RAM threshold in percent", 0, 100)},
+		Field{Name: "minDisk", Spec: number("Minimum disk in GB", minDisk, maxDisk)},
+		Field{Name: "maxDisk", Spec: number("Maximum disk in GB", minDisk, maxDisk)},
+	)
+}
+
+// importServiceSpec describes one entry of the services array.
+func importServiceSpec() *Spec {
+	s := object("Service definition",
+		Field{Name: "hostname", Spec: &Spec{
+			Kind:        KindString,
+			Description: "Service hostname, also its internal DNS name",
+			Pattern:     hostnamePattern,
+			PatternHint: "Use only lowercase letters a-z and digits 0-9, starting with a letter (e.g. 'api', 'db1')",
+			MaxLength:   25,
+		}, Required: true, Example: "hostname: api"},
+		Field{Name: "type", Spec: &Spec{
+			Kind:        KindString,
+			Description: "Service type as name@version",
+			Check:       catalog.Check,
+			Enum:        serviceTypes,
+		}, Required: true, Example: "type: nodejs@22"},
+		Field{Name: "mode", Spec: enum("Deployment mode (immutable after creation)", "HA", "NON_HA"), Example: "mode: NON_HA"},
+		Field{Name: "priority", Spec: &Spec{Kind: KindInt, Description: "Startup order, higher starts first"}},
+		Field{Name: "minContainers", Spec: integer("Minimum containers", minContainers, maxContainers)},
+		Field{Name: "maxContainers", Spec: integer("Maximum containers", minContainers, maxContainers)},
+		Field{Name: "verticalAutoscaling", Spec: verticalAutoscalingSpec()},
+		Field{Name: "envSecrets", Spec: mapOf("Secret environment variables (masked in GUI)", &Spec{Kind: KindScalar})},
+		Field{Name: "envVariables", Spec: envVariablesSpec()},
+		Field{Name: "dotEnvSecrets", Spec: str("Secrets in .env format")},
+		Field{Name: "buildFromGit", Spec: &Spec{
+			Kind:        KindString,
+			Description: "Repository to build and deploy after import",
+			Pattern:     regexp.MustCompile(`^https?://\S+$`),
+			PatternHint: "Use a full repository URL, e.g. https://github.com/zeropsio/recipe-nodejs",
+		}},
+		Field{Name: "zeropsSetup", Spec: str("zerops.yml setup used by buildFromGit")},
+		Field{Name: "enableSubdomainAccess", Spec: boolean("Enable the *.zerops.app subdomain")},
+		Field{Name: "startWithoutCode", Spec: boolean("Start the service before the first deploy")},
+		Field{Name: "override", Spec: boolean("Replace an existing service with the same hostname")},
+		Field{Name: "objectStorageSize", Spec: integer("Object storage size in GB", 1, 100)},
+		Field{Name: "objectStoragePolicy", Spec: enum("Bucket access policy",
+			"private", "public-read", "public-objects-read", "public-write", "public-read-write", "custom")},
+		Field{Name: "objectStorageRawPolicy", Spec: str("Custom bucket policy JSON")},
+		Field{Name: "mount", Spec: arrayOf("Shared storages to mount", str(""))},
+	)
+	s.Aliases = map[string]string{"name": "hostname"}
+	return s
+}
+
+// ImportYmlSpec is the schema of import.yml in project-scoped context (no project: section).
+var ImportYmlSpec = object("import.yml",
+	Field{
+		Name:     "services",
+		Spec:     &Spec{Kind: KindArray, Description: "Services to create", Items: importServiceSpec(), MinItems: 1},
+		Required: true,
+		Example:  "services:\n  - hostname: api\n    type: nodejs@22",
+	},
+)
+
+// ValidateImportYml validates import.yml content and returns every issue found.
+// The project: section is reported by the caller, which uses a dedicated error code for it.
+func ValidateImportYml(content []byte) []Issue {
+	root, err := parseRoot(content)
+	if err != nil {
+		return []Issue{syntaxIssue(content, err)}
+	}
+	v := &validator{}
+	v.check(root, ImportYmlSpec, "", Field{})
+
+	services := mappingValue(root, "services")
+	if services == nil || services.Kind != yaml.SequenceNode {
+		return v.issues
+	}
+
+	seen := make(map[string]int)
+	for i, svc := range services.Content {
+		svc = resolveAlias(svc)
+		if svc.Kind != yaml.MappingNode {
+			continue
+		}
+		path := fmt.Sprintf("services[%d]", i)
+
+		if h := mappingValue(svc, "hostname"); h != nil && h.Kind == yaml.ScalarNode {
+			if prev, dup := seen[h.Value]; dup {
+				v.add(h, path+".hostname", fmt.Sprintf("Duplicate hostname '%s' (also used by services[%d])", h.Value, prev),
+					"Use a unique hostname for every service")
+			} else {
+				seen[h.Value] = i
+			}
+		}
+
+		if t := mappingValue(svc, "type"); t != nil && modeRequiredServices[serviceTypeName(t.Value)] && mappingValue(svc, "mode") == nil {
+			v.add(svc, path, fmt.Sprintf("Missing 'mode' key (required for %s)", serviceTypeName(t.Value)),
+				"Add: mode: NON_HA (or HA for production)")
+		}
+
+		v.checkMinMax(svc, path, "minContainers", "maxContainers")
+		if va := mappingValue(svc, "verticalAutoscaling"); va != nil && va.Kind == yaml.MappingNode {
+			vaPath := path + ".verticalAutoscaling"
+			v.checkMinMax(va, vaPath, "minCpu", "maxCpu")
+			v.checkMinMax(va, vaPath, "minRam", "maxRam")
+			v.checkMinMax(va, vaPath, "minDisk", "maxDisk")
+		}
+	}
+	return v.issues
+}
+
+// checkMinMax reports minKey > maxKey when both are numeric.
+func (v *validator) checkMinMax(n *yaml.Node, path, minKey, maxKey string) {
+	lo, hi := mappingValue(n, minKey), mappingValue(n, maxKey)
+	if lo == nil || hi == nil {
+		return
+	}
+	loVal, err1 := strconv.ParseFloat(lo.Value, 64)
+	hiVal, err2 := strconv.ParseFloat(hi.Value, 64)
+	if err1 != nil || err2 != nil || loVal <= hiVal {
+		return
+	}
+	v.add(lo, joinPath(path, minKey), fmt.Sprintf("%s must be <= %s (%s > %s)", minKey, maxKey, lo.Value, hi.Value),
+		fmt.Sprintf("Set %s <= %s", minKey, maxKey))
+}
+
+// mappingValue returns the value node for key in a mapping node, or nil.
+func mappingValue(n *yaml.Node, key string) *yaml.Node {
+	n = resolveAlias(n)
+	if n.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(n.Content); i += 2 {
+		if n.Content[i].Value == key {
+			return resolveAlias(n.Content[i+1])
+		}
+	}
+	return nil
+}
@@ -0,0 +1,122 @@
+package validation
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestValidateImportYml_Valid(t *testing.T) {
+	content := `services:
+  - hostname: db
+    type: postgresql@16
+    mode: NON_HA
+    priority: 10
+  - hostname: storage
+    type: object-storage
+    objectStorageSize: 10
+    objectStoragePolicy: public-read
+  - hostname: api
+    type: nodejs@22
+    buildFromGit: https://github.com/zeropsio/recipe-nodejs
+    enableSubdomainAccess: true
+    minContainers: 1
+    maxContainers: 3
+    verticalAutoscaling:
+      cpuMode: SHARED
+      minCpu: 1
+      maxCpu: 5
+      minRam: 0.5
+      maxRam: 4
+      minFreeRamGB: 0.5
+      minDisk: 1
+      maxDisk: 20
+    envSecrets:
+      DB_PASSWORD: ${db_password}
+      SECRET_KEY: <@generateRandomString(<64>)>
+`
+	issues := ValidateImportYml([]byte(content))
+	if len(issues) != 0 {
+		t.Fatalf("expected no issues, got %+v", issues)
+	}
+}
+
+func TestValidateImportYml_ServiceErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		service string
+		path    string
+		err     string
+	}{
+		{"uppercase hostname", "hostname: MyApi\n    type: nodejs@22", "services[0].hostname", "Invalid value 'MyApi'"},
+		{"underscore hostname", "hostname: my_api\n    type: nodejs@22", "services[0].hostname", "Invalid value 'my_api'"},
+		{"long hostname", "hostname: abcdefghijklmnopqrstuvwxyz\n    type: nodejs@22", "services[0].hostname", "too long"},
+		{"missing hostname", "type: nodejs@22", "services[0]", "Missing 'hostname' key"},
+		{"name alias", "name: api\n    type: nodejs@22", "services[0].name", "Unknown key 'name'"},
+		{"unknown type", "hostname: api\n    type: nodes@22", "services[0].type", "Invalid value 'nodes@22'"},
+		{"unknown version", "hostname: api\n    type: nodejs@21", "services[0].type", "Unknown version '21' for nodejs"},
+		{"eol version", "hostname: api\n    type: dotnet@6", "services[0].type", "'dotnet@6' is end-of-life"},
+		{"missing type", "hostname: api", "services[0]", "Missing 'type' key"},
+		{"bad mode", "hostname: db\n    type: postgresql@16\n    mode: SINGLE", "services[0].mode", "Invalid value 'SINGLE'"},
+		{"mode required", "hostname: db\n    type: postgresql@16", "services[0]", "Missing 'mode' key"},
+		{"containers range", "hostname: api\n    type: nodejs@22\n    maxContainers: 20", "services[0].maxContainers", "between 1 and 10"},
+		{"containers order", "hostname: api\n    type: nodejs@22\n    minContainers: 4\n    maxContainers: 2", "services[0].minContainers", "minContainers must be <= maxContainers"},
+		{"cpu range", "hostname: api\n    type: nodejs@22\n    verticalAutoscaling:\n      maxCpu: 16", "services[0].verticalAutoscaling.maxCpu", "between 1 and 8"},
+		{"ram range", "hostname: api\n    type: nodejs@22\n    verticalAutoscaling:\n      minRam: 0.1", "services[0].verticalAutoscaling.minRam", "between 0.125 and 48"},
+		{"ram order", "hostname: api\n    type: nodejs@22\n    verticalAutoscaling:\n      minRam: 4\n      maxRam: 1", "services[0].verticalAutoscaling.minRam", "minRam must be <= maxRam"},
+		{"cpu mode", "hostname: api\n    type: nodejs@22\n    verticalAutoscaling:\n      cpuMode: FAST", "services[0].verticalAutoscaling.cpuMode", "Invalid value 'FAST'"},
+		{"env secrets type", "hostname: api\n    type: nodejs@22\n    envSecrets: [A, B]", "services[0].envSecrets", "must be a mapping"},
+		{"priority type", "hostname: api\n    type: nodejs@22\n    priority: high", "services[0].priority", "must be an integer"},
+		{"buildFromGit url", "hostname: api\n    type: nodejs@22\n    buildFromGit: github.com/user/repo", "services[0].buildFromGit", "Invalid value"},
+		{"storage size", "hostname: files\n    type: object-storage\n    objectStorageSize: 500", "services[0].objectStorageSize", "between 1 and 100"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			issues := ValidateImportYml([]byte("services:\n  - " + tt.service + "\n"))
+			found := false
+			for _, is := range issues {
+				if is.Path == tt.path && strings.Contains(is.Error, tt.err) {
+					found = true
+				}
+			}
+			if !found {
+				t.Errorf("expected issue %q at %q, got %+v", tt.err, tt.path, issues)
+			}
+		})
+	}
+}
+
+func TestValidateImportYml_DuplicateHostname(t *testing.T) {
+	content := `services:
+  - hostname: api
+    type: nodejs@22
+  - hostname: api
+    type: go@1
+`
+	issues := ValidateImportYml([]byte(content))
+	if len(issues) != 1 || issues[0].Path != "services[1].hostname" {
+		t.Fatalf("expected duplicate hostname issue, got %+v", issues)
+	}
+}
+
+func TestValidateImportYml_TypeSuggestion(t *testing.T) {
+	issues := ValidateImportYml([]byte("services:\n  - hostname: db\n    type: postgres@16\n    mode: HA\n"))
+	if len(issues) != 1 {
+		t.Fatalf("expected 1 issue, got %+v", issues)
+	}
+	if issues[0].Fix != "Did you mean 'postgresql@16'?" {
+		t.Errorf("fix = %q, want postgresql@16 suggestion", issues[0].Fix)
+	}
+}
+
+func TestValidateImportYml_MissingServices(t *testing.T) {
+	issues := ValidateImportYml([]byte("foo: bar"))
+	found := false
+	for _, is := range issues {
+		if is.Error == "Missing 'services' key" {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("expected missing services issue, got %+v", issues)
+	}
+}
@@ -0,0 +1,99 @@
+package validation
+
+import "encoding/json"
+
+// jsonSchemaDraft is the JSON Schema dialect emitted by JSONSchema.
+// Draft-07 is the newest version supported by the common YAML editor integrations.
+const jsonSchemaDraft = "http://json-schema.org/draft-07/schema#"
+
+// JSONSchema converts spec into a JSON Schema document titled title.
+// Custom Check functions have no JSON Schema equivalent; their accepted values are covered by Enum.
+func JSONSchema(spec *Spec, title string) ([]byte, error) {
+	s := toJSONSchema(spec)
+	s["$schema"] = jsonSchemaDraft
+	s["title"] = title
+	return json.MarshalIndent(s, "", "  ")
+}
+
+func toJSONSchema(spec *Spec) map[string]interface{} {
+	s := make(map[string]interface{})
+	if spec.Description != "" {
+		s["description"] = spec.Description
+	}
+
+	if len(spec.OneOf) > 0 {
+		alts := make([]interface{}, len(spec.OneOf))
+		for i, alt := range spec.OneOf {
+			alts[i] = toJSONSchema(alt)
+		}
+		// anyOf, not oneOf: the validator takes the first alternative matching the node kind.
+		s["anyOf"] = alts
+		return s
+	}
+
+	switch spec.Kind {
+	case KindString:
+		s["type"] = "string"
+	case KindInt:
+		s["type"] = "integer"
+	case KindNumber:
+		s["type"] = "number"
+	case KindBool:
+		s["type"] = "boolean"
+	case KindScalar:
+		s["type"] = []string{"string", "number", "boolean"}
+	case KindObject:
+		s["type"] = "object"
+		s["additionalProperties"] = false
+		props := make(map[string]interface{}, len(spec.Fields))
+		var required []string
+		for _, f := range spec.Fields {
+			props[f.Name] = toJSONSchema(f.Spec)
+			if f.Required {
+				required = append(required, f.Name)
+			}
+		}
+		s["properties"] = props
+		if len(required) > 0 {
+			s["required"] = required
+		}
+		if len(spec.RequireOneOf) > 0 {
+			alts := make([]interface{}, len(spec.RequireOneOf))
+			for i, name := range spec.RequireOneOf {
+				alts[i] = map[string]interface{}{"required": []string{name}}
+			}
+			s["anyOf"] = alts
+		}
+	case KindMap:
+		s["type"] = "object"
+		if spec.Values != nil {
+			s["additionalProperties"] = toJSONSchema(spec.Values)
+		}
+	case KindArray:
+		s["type"] = "array"
+		if spec.Items != nil {
+			s["items"] = toJSONSchema(spec.Items)
+		}
+		if spec.MinItems > 0 {
+			s["minItems"] = spec.MinItems
+		}
+	case KindAny:
+	}
+
+	if len(spec.Enum) > 0 {
+		s["enum"] = spec.Enum
+	}
+	if spec.Min != nil {
+		s["minimum"] = *spec.Min
+	}
+	if spec.Max != nil {
+		s["maximum"] = *spec.Max
+	}
+	if spec.Pattern != nil {
+		s["pattern"] = spec.Pattern.String()
+	}
+	if spec.MaxLength > 0 {
+		s["maxLength"] = spec.MaxLength
+	}
+	return s
+}
@@ -0,0 +1,73 @@
+package validation
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestJSONSchema_ZeropsYml(t *testing.T) {
+	data, err := JSONSchema(ZeropsYmlSpec, "zerops.yml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var s map[string]interface{}
+	if err := json.Unmarshal(data, &s); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	if s["$schema"] != jsonSchemaDraft || s["title"] != "zerops.yml" {
+		t.Errorf("header = %v / %v", s["$schema"], s["title"])
+	}
+	if req := s["required"].([]interface{}); len(req) != 1 || req[0] != "zerops" {
+		t.Errorf("required = %v, want [zerops]", req)
+	}
+
+	zerops := s["properties"].(map[string]interface{})["zerops"].(map[string]interface{})
+	if zerops["type"] != "array" || zerops["minItems"] != float64(1) {
+		t.Errorf("zerops = %v", zerops)
+	}
+	svc := zerops["items"].(map[string]interface{})
+	if svc["additionalProperties"] != false {
+		t.Error("service entries must reject unknown keys")
+	}
+	run := svc["properties"].(map[string]interface{})["run"].(map[string]interface{})
+	base := run["properties"].(map[string]interface{})["base"].(map[string]interface{})
+	if !containsValue(base["enum"], "nodejs@22") || containsValue(base["enum"], "dotnet@6") {
+		t.Errorf("run.base enum = %v, want current versions only", base["enum"])
+	}
+}
+
+func TestJSONSchema_ImportYml(t *testing.T) {
+	data, err := JSONSchema(ImportYmlSpec, "import.yml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var s map[string]interface{}
+	if err := json.Unmarshal(data, &s); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	svc := s["properties"].(map[string]interface{})["services"].(map[string]interface{})["items"].(map[string]interface{})
+	props := svc["properties"].(map[string]interface{})
+
+	hostname := props["hostname"].(map[string]interface{})
+	if hostname["pattern"] != hostnamePattern.String() || hostname["maxLength"] != float64(25) {
+		t.Errorf("hostname = %v", hostname)
+	}
+	minC := props["minContainers"].(map[string]interface{})
+	if minC["type"] != "integer" || minC["minimum"] != float64(minContainers) || minC["maximum"] != float64(maxContainers) {
+		t.Errorf("minContainers = %v", minC)
+	}
+	envValues := props["envSecrets"].(map[string]interface{})["additionalProperties"].(map[string]interface{})
+	if types := envValues["type"].([]interface{}); len(types) != 3 {
+		t.Errorf("env value type = %v, want any scalar", types)
+	}
+}
+
+func containsValue(list interface{}, s string) bool {
+	items, _ := list.([]interface{})
+	for _, v := range items {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
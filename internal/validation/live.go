@@ -0,0 +1,96 @@
+package validation
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ProjectEnv is the env state of a live project, used to resolve ${hostname_VAR} references.
+type ProjectEnv struct {
+	// Services maps each existing hostname to the names of its env variables.
+	Services map[string]map[string]bool
+	// Project holds the names of project-level env variables.
+	Project map[string]bool
+}
+
+// generatedServiceVars exist on every service without being listed by the env API.
+var generatedServiceVars = map[string]bool{
+	"hostname":  true,
+	"serviceId": true,
+}
+
+// ZeropsYmlServiceRefs returns every ${hostname_VAR} reference in the build and run
+// envVariables of a zerops.yml. Unparsable input yields no references.
+func ZeropsYmlServiceRefs(content []byte) []ServiceRef {
+	root, err := parseRoot(content)
+	if err != nil {
+		return nil
+	}
+	var refs []ServiceRef
+	forEachEntry(root, "zerops", func(svc *yaml.Node, path string) {
+		for _, section := range []string{"build", "run"} {
+			if sec := mappingValue(svc, section); sec != nil {
+				refs = append(refs, ServiceRefs(mappingValue(sec, "envVariables"), path+"."+section+".envVariables")...)
+			}
+		}
+	})
+	return refs
+}
+
+// CheckLiveRefs reports zerops.yml references that do not resolve against env.
+// Hostnames in planned (services an accompanying import.yml will create) are not checked.
+func CheckLiveRefs(content []byte, env ProjectEnv, planned map[string]bool) []Issue {
+	hosts := make([]string, 0, len(env.Services))
+	for h := range env.Services {
+		hosts = append(hosts, h)
+	}
+	sort.Strings(hosts)
+
+	var issues []Issue
+	for _, ref := range ZeropsYmlServiceRefs(content) {
+		vars, exists := env.Services[ref.Hostname]
+		switch {
+		case exists:
+			if vars[ref.Variable] || generatedServiceVars[ref.Variable] {
+				continue
+			}
+			fix := fmt.Sprintf("Set it with: zaia env set --service %s %s=<value>", ref.Hostname, ref.Variable)
+			if s := Closest(ref.Variable, sortedKeys(vars)); s != "" {
+				fix = fmt.Sprintf("Did you mean '${%s_%s}'?", ref.Hostname, s)
+			}
+			issues = append(issues, Issue{Path: ref.Path, Line: ref.Node.Line, Column: ref.Node.Column,
+				Error: fmt.Sprintf("'%s': service '%s' has no variable '%s'", ref.Ref, ref.Hostname, ref.Variable),
+				Fix:   fix})
+		case planned[ref.Hostname], env.Project[ref.Hostname+"_"+ref.Variable]:
+			continue
+		default:
+			fix := "Available services: " + joinOrNone(hosts)
+			if s := Closest(ref.Hostname, hosts); s != "" {
+				fix = fmt.Sprintf("Did you mean '${%s_%s}'?", s, ref.Variable)
+			}
+			issues = append(issues, Issue{Path: ref.Path, Line: ref.Node.Line, Column: ref.Node.Column,
+				Error: fmt.Sprintf("'%s': service '%s' does not exist in the project", ref.Ref, ref.Hostname),
+				Fix:   fix})
+		}
+	}
+	return issues
+}
+
+func sortedKeys(m map[string]bool) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func joinOrNone(list []string) string {
+	if len(list) == 0 {
+		return "(none)"
+	}
+	return strings.Join(list, ", ")
+}
@@ -0,0 +1,57 @@
+package validation
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCheckLiveRefs(t *testing.T) {
+	content := `zerops:
+  - setup: api
+    run:
+      envVariables:
+        DB_URL: ${db_connectionString}
+        DB_HOST: ${db_hostname}
+        DB_PASS: ${db_pasword}
+        CACHE: ${cahce_hostname}
+        QUEUE: ${queue_url}
+`
+	env := ProjectEnv{
+		Services: map[string]map[string]bool{
+			"db":    {"connectionString": true, "password": true},
+			"cache": {},
+		},
+		Project: map[string]bool{},
+	}
+	issues := CheckLiveRefs([]byte(content), env, map[string]bool{"queue": true})
+
+	want := map[string]string{
+		"zerops[0].run.envVariables.DB_PASS": "Did you mean '${db_password}'?",
+		"zerops[0].run.envVariables.CACHE":   "Did you mean '${cache_hostname}'?",
+	}
+	if len(issues) != len(want) {
+		t.Fatalf("expected %d issues, got %+v", len(want), issues)
+	}
+	for _, is := range issues {
+		if fix, ok := want[is.Path]; !ok || is.Fix != fix {
+			t.Errorf("issue %+v, want fix %q", is, fix)
+		}
+		if is.Line == 0 {
+			t.Errorf("issue at %s has no position", is.Path)
+		}
+	}
+}
+
+func TestCheckLiveRefs_ProjectVariable(t *testing.T) {
+	content := "zerops:\n  - setup: api\n    run:\n      envVariables:\n        KEY: ${shared_key}\n"
+	env := ProjectEnv{Services: map[string]map[string]bool{}, Project: map[string]bool{"shared_key": true}}
+	if issues := CheckLiveRefs([]byte(content), env, nil); len(issues) != 0 {
+		t.Errorf("expected project variable to resolve, got %+v", issues)
+	}
+
+	env.Project = map[string]bool{}
+	issues := CheckLiveRefs([]byte(content), env, nil)
+	if len(issues) != 1 || !strings.Contains(issues[0].Error, "does not exist") {
+		t.Errorf("expected unknown service issue, got %+v", issues)
+	}
+}
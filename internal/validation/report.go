@@ -0,0 +1,261 @@
+package validation
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Rule IDs reported for errors, which unlike lint findings carry no rule of their own.
+const (
+	RuleSchema         = "schema"
+	RuleSyntax         = "yaml-syntax"
+	RuleProjectSection = "project-section"
+	RuleLiveEnvRef     = "live-env-ref"
+	RuleFileRead       = "file-read"
+	RuleLiveAPI        = "live-api"
+)
+
+// FileResult is the outcome of validating one file, the input of the CI report formats.
+type FileResult struct {
+	File   string
+	Type   string
+	Errors []Issue
+	// ErrorRule is the rule ID reported for Errors; empty means schema (or yaml-syntax).
+	ErrorRule string
+	Findings  []Finding
+}
+
+func (r FileResult) errorRule(is Issue) string {
+	if r.ErrorRule != "" {
+		return r.ErrorRule
+	}
+	if is.Path == "" && strings.HasPrefix(is.Error, "Invalid YAML syntax") {
+		return RuleSyntax
+	}
+	return RuleSchema
+}
+
+// --- SARIF 2.1.0 ---
+
+type sarifLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string      `json:"name"`
+	Version        string      `json:"version,omitempty"`
+	InformationURI string      `json:"informationUri"`
+	Rules          []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID               string       `json:"id"`
+	ShortDescription sarifMessage `json:"shortDescription"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifResult struct {
+	RuleID    string          `json:"ruleId"`
+	Level     string          `json:"level"`
+	Message   sarifMessage    `json:"message"`
+	Locations []sarifLocation `json:"locations"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation sarifPhysicalLocation `json:"physicalLocation"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifact `json:"artifactLocation"`
+	Region           *sarifRegion  `json:"region,omitempty"`
+}
+
+type sarifArtifact struct {
+	URI string `json:"uri"`
+}
+
+type sarifRegion struct {
+	StartLine   int `json:"startLine"`
+	StartColumn int `json:"startColumn,omitempty"`
+}
+
+// ruleDescriptions covers every rule ID that can appear in a report.
+func ruleDescriptions() map[string]string {
+	desc := map[string]string{
+		RuleSchema:         "Configuration does not match the schema",
+		RuleSyntax:         "File is not valid YAML",
+		RuleProjectSection: "import.yml must not contain a project: section",
+		RuleLiveEnvRef:     "Env reference does not resolve in the current project",
+		RuleFileRead:       "File could not be read",
+		RuleLiveAPI:        "Project env could not be read for --live",
+	}
+	for _, rules := range [][]Rule{ZeropsYmlRules, ImportYmlRules, CrossFileRules, {UnknownSuppressionRule}} {
+		for _, r := range rules {
+			desc[r.ID] = r.Description
+		}
+	}
+	return desc
+}
+
+// SARIF renders results as a SARIF 2.1.0 log for code scanning tools.
+func SARIF(results []FileResult, toolVersion string) ([]byte, error) {
+	desc := ruleDescriptions()
+	used := make(map[string]bool)
+	out := []sarifResult{}
+	add := func(file, rule, level, msg, fix string, line, col int) {
+		used[rule] = true
+		if fix != "" {
+			msg += " (" + fix + ")"
+		}
+		loc := sarifLocation{PhysicalLocation: sarifPhysicalLocation{ArtifactLocation: sarifArtifact{URI: file}}}
+		if line > 0 {
+			loc.PhysicalLocation.Region = &sarifRegion{StartLine: line, StartColumn: col}
+		}
+		out = append(out, sarifResult{RuleID: rule, Level: level, Message: sarifMessage{Text: msg}, Locations: []sarifLocation{loc}})
+	}
+	for _, r := range results {
+		for _, is := range r.Errors {
+			add(r.File, r.errorRule(is), "error", is.Error, is.Fix, is.Line, is.Column)
+		}
+		for _, f := range r.Findings {
+			level := "note"
+			if f.Severity == SeverityWarning {
+				level = "warning"
+			}
+			add(findingFile(r, f), f.Rule, level, f.Message, f.Fix, f.Line, f.Column)
+		}
+	}
+
+	ids := make([]string, 0, len(used))
+	for id := range used {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	rules := make([]sarifRule, 0, len(ids))
+	for _, id := range ids {
+		rules = append(rules, sarifRule{ID: id, ShortDescription: sarifMessage{Text: desc[id]}})
+	}
+
+	return json.MarshalIndent(sarifLog{
+		Schema:  "https://json.schemastore.org/sarif-2.1.0.json",
+		Version: "2.1.0",
+		Runs: []sarifRun{{
+			Tool: sarifTool{Driver: sarifDriver{
+				Name:           "zaia",
+				Version:        toolVersion,
+				InformationURI: "https://github.com/zeropsio/zaia",
+				Rules:          rules,
+			}},
+			Results: out,
+		}},
+	}, "", "  ")
+}
+
+// findingFile returns the file a finding points into; cross-file findings carry their own.
+func findingFile(r FileResult, f Finding) string {
+	if f.File != "" {
+		return f.File
+	}
+	return r.File
+}
+
+// --- JUnit XML ---
+
+type junitTestSuites struct {
+	XMLName  xml.Name         `xml:"testsuites"`
+	Name     string           `xml:"name,attr"`
+	Tests    int              `xml:"tests,attr"`
+	Failures int              `xml:"failures,attr"`
+	Suites   []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name     string          `xml:"name,attr"`
+	Tests    int             `xml:"tests,attr"`
+	Failures int             `xml:"failures,attr"`
+	Cases    []junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Failure   *junitFailure `xml:"failure,omitempty"`
+	SystemOut string        `xml:"system-out,omitempty"`
+}
+
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr"`
+	Text    string `xml:",chardata"`
+}
+
+// JUnit renders results as JUnit XML, one test suite per file and one test case per problem.
+// Errors are failures; warnings fail only when strict, info never does.
+func JUnit(results []FileResult, strict bool) ([]byte, error) {
+	doc := junitTestSuites{Name: "zaia validate"}
+	for _, r := range results {
+		suite := junitTestSuite{Name: r.File}
+		addCase := func(rule, path, msg, fix string, line, col int, failed bool) {
+			tc := junitTestCase{Name: rule + ": " + displayName(path), ClassName: r.File}
+			text := fmt.Sprintf("%s\n%s", location(r.File, line, col), msg)
+			if fix != "" {
+				text += "\nFix: " + fix
+			}
+			if failed {
+				tc.Failure = &junitFailure{Message: msg, Type: rule, Text: text}
+				suite.Failures++
+			} else {
+				tc.SystemOut = text
+			}
+			suite.Cases = append(suite.Cases, tc)
+		}
+		for _, is := range r.Errors {
+			addCase(r.errorRule(is), is.Path, is.Error, is.Fix, is.Line, is.Column, true)
+		}
+		for _, f := range r.Findings {
+			addCase(f.Rule, f.Path, f.Message, f.Fix, f.Line, f.Column, strict && f.Severity == SeverityWarning)
+		}
+		if len(suite.Cases) == 0 {
+			suite.Cases = append(suite.Cases, junitTestCase{Name: "valid", ClassName: r.File})
+		}
+		suite.Tests = len(suite.Cases)
+		doc.Tests += suite.Tests
+		doc.Failures += suite.Failures
+		doc.Suites = append(doc.Suites, suite)
+	}
+
+	data, err := xml.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(xml.Header), data...), nil
+}
+
+// location formats file:line:column, omitting unknown parts.
+func location(file string, line, col int) string {
+	switch {
+	case line == 0:
+		return file
+	case col == 0:
+		return fmt.Sprintf("%s:%d", file, line)
+	default:
+		return fmt.Sprintf("%s:%d:%d", file, line, col)
+	}
+}
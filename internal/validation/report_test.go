@@ -0,0 +1,77 @@
+package validation
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"strings"
+	"testing"
+)
+
+var reportResults = []FileResult{
+	{
+		File: "zerops.yml", Type: "zerops.yml",
+		Errors: []Issue{{Path: "zerops[0].run.start", Error: "'zerops[0].run.start' must be a string", Fix: "Format: start: npm start", Line: 4, Column: 14}},
+	},
+	{
+		File: "import.yml", Type: "import.yml",
+		Findings: []Finding{{Rule: "non-ha-database", Severity: SeverityWarning, Path: "services[0].mode", Message: "NON_HA", Line: 3, Column: 11}},
+	},
+}
+
+func TestSARIF(t *testing.T) {
+	data, err := SARIF(reportResults, "1.2.3")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var log sarifLog
+	if err := json.Unmarshal(data, &log); err != nil {
+		t.Fatalf("invalid SARIF JSON: %v", err)
+	}
+	if log.Version != "2.1.0" || len(log.Runs) != 1 {
+		t.Fatalf("unexpected log header: %+v", log)
+	}
+	run := log.Runs[0]
+	if run.Tool.Driver.Version != "1.2.3" || len(run.Tool.Driver.Rules) != 2 {
+		t.Errorf("driver = %+v, want version and 2 rules", run.Tool.Driver)
+	}
+	if len(run.Results) != 2 {
+		t.Fatalf("expected 2 results, got %+v", run.Results)
+	}
+	first := run.Results[0]
+	if first.RuleID != RuleSchema || first.Level != "error" {
+		t.Errorf("first result = %+v, want schema error", first)
+	}
+	loc := first.Locations[0].PhysicalLocation
+	if loc.ArtifactLocation.URI != "zerops.yml" || loc.Region.StartLine != 4 || loc.Region.StartColumn != 14 {
+		t.Errorf("location = %+v, want zerops.yml:4:14", loc)
+	}
+	if run.Results[1].RuleID != "non-ha-database" || run.Results[1].Level != "warning" {
+		t.Errorf("second result = %+v, want non-ha-database warning", run.Results[1])
+	}
+}
+
+func TestJUnit(t *testing.T) {
+	for _, strict := range []bool{false, true} {
+		data, err := JUnit(reportResults, strict)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !strings.HasPrefix(string(data), "<?xml") {
+			t.Errorf("missing XML header")
+		}
+		var doc junitTestSuites
+		if err := xml.Unmarshal(data, &doc); err != nil {
+			t.Fatalf("invalid JUnit XML: %v", err)
+		}
+		wantFailures := 1
+		if strict {
+			wantFailures = 2
+		}
+		if doc.Tests != 2 || doc.Failures != wantFailures {
+			t.Errorf("strict=%v: tests=%d failures=%d, want 2/%d", strict, doc.Tests, doc.Failures, wantFailures)
+		}
+		if doc.Suites[0].Cases[0].Name != "schema: zerops[0].run.start" {
+			t.Errorf("case name = %q", doc.Suites[0].Cases[0].Name)
+		}
+	}
+}
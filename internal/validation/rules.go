@@ -0,0 +1,391 @@
+package validation
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/zeropsio/zaia/internal/catalog"
+	"gopkg.in/yaml.v3"
+)
+
+// Finding severities.
+const (
+	SeverityWarning = "warning"
+	SeverityInfo    = "info"
+)
+
+// Finding is a non-fatal observation reported by a lint rule.
+type Finding struct {
+	Rule     string `json:"rule"`
+	Severity string `json:"severity"`
+	File     string `json:"file,omitempty"`
+	Path     string `json:"path"`
+	Message  string `json:"message"`
+	Fix      string `json:"fix,omitempty"`
+	Line     int    `json:"line,omitempty"`
+	Column   int    `json:"column,omitempty"`
+}
+
+// Rule is a lint check with a stable ID. IDs are used in suppression comments
+// and must not change once released. Check is nil for rules evaluated outside lint.
+type Rule struct {
+	ID          string
+	Severity    string
+	Description string
+	Check       func(root *yaml.Node, r *reporter)
+}
+
+// reporter collects findings for the rule currently running.
+type reporter struct {
+	rule     *Rule
+	findings []Finding
+}
+
+func (r *reporter) report(n *yaml.Node, path, msg, fix string) {
+	r.findings = append(r.findings, Finding{
+		Rule:     r.rule.ID,
+		Severity: r.rule.Severity,
+		Path:     path,
+		Message:  msg,
+		Fix:      fix,
+		Line:     n.Line,
+		Column:   n.Column,
+	})
+}
+
+// ZeropsYmlRules are the lint rules applied to zerops.yml.
+var ZeropsYmlRules = []Rule{
+	{
+		ID:          "deprecated-version",
+		Severity:    SeverityWarning,
+		Description: "Build or run base uses a deprecated version",
+		Check:       checkDeprecatedBase,
+	},
+	{
+		ID:          "deploy-all-files",
+		Severity:    SeverityWarning,
+		Description: "deployFiles: ./ on a JavaScript build ships sources and node_modules",
+		Check:       checkDeployAllFiles,
+	},
+	{
+		ID:          "hardcoded-secret",
+		Severity:    SeverityWarning,
+		Description: "Secret-looking env variable has a literal value",
+		Check:       checkZeropsHardcodedSecrets,
+	},
+	{
+		ID:          "missing-health-check",
+		Severity:    SeverityInfo,
+		Description: "Service exposes ports but has no healthCheck",
+		Check:       checkMissingHealthCheck,
+	},
+}
+
+// ImportYmlRules are the lint rules applied to import.yml.
+var ImportYmlRules = []Rule{
+	{
+		ID:          "deprecated-version",
+		Severity:    SeverityWarning,
+		Description: "Service type is deprecated",
+		Check:       checkDeprecatedType,
+	},
+	{
+		ID:          "non-ha-database",
+		Severity:    SeverityWarning,
+		Description: "Database or cache runs in NON_HA mode",
+		Check:       checkNonHADatabase,
+	},
+	{
+		ID:          "single-container-runtime",
+		Severity:    SeverityInfo,
+		Description: "Runtime service is limited to a single container",
+		Check:       checkSingleContainerRuntime,
+	},
+	{
+		ID:          "hardcoded-secret",
+		Severity:    SeverityWarning,
+		Description: "Secret-looking env variable is stored in envVariables",
+		Check:       checkImportHardcodedSecrets,
+	},
+}
+
+// LintZeropsYml runs ZeropsYmlRules on content. Invalid YAML yields no findings.
+func LintZeropsYml(content []byte) []Finding {
+	return lint(content, ZeropsYmlRules)
+}
+
+// LintImportYml runs ImportYmlRules on content. Invalid YAML yields no findings.
+func LintImportYml(content []byte) []Finding {
+	return lint(content, ImportYmlRules)
+}
+
+func lint(content []byte, rules []Rule) []Finding {
+	doc, err := parseDocument(content)
+	if err != nil {
+		return nil
+	}
+	root := documentRoot(doc)
+	suppressed, findings := suppressions(doc)
+	for i := range rules {
+		rule := &rules[i]
+		if suppressed[rule.ID] {
+			continue
+		}
+		r := &reporter{rule: rule}
+		rule.Check(root, r)
+		findings = append(findings, r.findings...)
+	}
+	sort.SliceStable(findings, func(i, j int) bool {
+		return findings[i].Line < findings[j].Line
+	})
+	return findings
+}
+
+// UnknownSuppressionRule reports zaia-ignore comments naming a rule that does not exist,
+// which would otherwise silently suppress nothing.
+var UnknownSuppressionRule = Rule{
+	ID:          "unknown-suppression",
+	Severity:    SeverityWarning,
+	Description: "zaia-ignore comment names an unknown rule",
+}
+
+// suppressionPattern matches "# zaia-ignore: rule-a, rule-b" comment lines.
+var suppressionPattern = regexp.MustCompile(`(?m)^#[ \t]*zaia-ignore:(.*)$`)
+
+// suppressions returns the rule IDs disabled for the whole file by zaia-ignore comments,
+// and findings for the IDs that name no rule. Only YAML comments count: the same text in
+// a quoted or block string suppresses nothing.
+func suppressions(doc *yaml.Node) (map[string]bool, []Finding) {
+	known := make(map[string]bool)
+	var names []string
+	for _, rules := range [][]Rule{ZeropsYmlRules, ImportYmlRules, CrossFileRules} {
+		for _, rule := range rules {
+			if !known[rule.ID] {
+				known[rule.ID] = true
+				names = append(names, rule.ID)
+			}
+		}
+	}
+
+	ids := make(map[string]bool)
+	var findings []Finding
+	var walk func(n *yaml.Node)
+	walk = func(n *yaml.Node) {
+		for _, comment := range []string{n.HeadComment, n.LineComment, n.FootComment} {
+			for _, m := range suppressionPattern.FindAllStringSubmatch(comment, -1) {
+				for _, id := range strings.Split(m[1], ",") {
+					if id = strings.TrimSpace(id); id == "" {
+						continue
+					}
+					ids[id] = true
+					if known[id] {
+						continue
+					}
+					fix := "Known rules: " + strings.Join(names, ", ")
+					if s := Closest(id, names); s != "" {
+						fix = fmt.Sprintf("Did you mean '%s'?", s)
+					}
+					findings = append(findings, Finding{
+						Rule:     UnknownSuppressionRule.ID,
+						Severity: UnknownSuppressionRule.Severity,
+						Message:  fmt.Sprintf("zaia-ignore names unknown rule '%s'", id),
+						Fix:      fix,
+						Line:     n.Line,
+						Column:   n.Column,
+					})
+				}
+			}
+		}
+		for _, c := range n.Content {
+			walk(c)
+		}
+	}
+	walk(doc)
+	return ids, findings
+}
+
+// --- zerops.yml rules ---
+
+// forEachEntry calls fn for every mapping item of the top-level array under key.
+func forEachEntry(root *yaml.Node, key string, fn func(item *yaml.Node, path string)) {
+	list := mappingValue(root, key)
+	if list == nil || list.Kind != yaml.SequenceNode {
+		return
+	}
+	for i, item := range list.Content {
+		item = resolveAlias(item)
+		if item.Kind == yaml.MappingNode {
+			fn(item, fmt.Sprintf("%s[%d]", key, i))
+		}
+	}
+}
+
+// scalarValues returns the scalar values of a string or string-list node.
+func scalarValues(n *yaml.Node) []*yaml.Node {
+	if n == nil {
+		return nil
+	}
+	switch n.Kind {
+	case yaml.ScalarNode:
+		return []*yaml.Node{n}
+	case yaml.SequenceNode:
+		var out []*yaml.Node
+		for _, c := range n.Content {
+			if c = resolveAlias(c); c.Kind == yaml.ScalarNode {
+				out = append(out, c)
+			}
+		}
+		return out
+	case yaml.DocumentNode, yaml.MappingNode, yaml.AliasNode:
+	}
+	return nil
+}
+
+func checkDeprecatedBase(root *yaml.Node, r *reporter) {
+	forEachEntry(root, "zerops", func(svc *yaml.Node, path string) {
+		for _, section := range []string{"build", "run"} {
+			sec := mappingValue(svc, section)
+			if sec == nil {
+				continue
+			}
+			for _, base := range scalarValues(mappingValue(sec, "base")) {
+				if repl, ok := catalog.Deprecated(base.Value); ok {
+					r.report(base, path+"."+section+".base",
+						fmt.Sprintf("'%s' is deprecated", base.Value),
+						fmt.Sprintf("Use %s", repl))
+				}
+			}
+		}
+	})
+}
+
+func checkDeployAllFiles(root *yaml.Node, r *reporter) {
+	forEachEntry(root, "zerops", func(svc *yaml.Node, path string) {
+		build := mappingValue(svc, "build")
+		if build == nil || !usesJavaScriptBase(mappingValue(build, "base")) {
+			return
+		}
+		for _, f := range scalarValues(mappingValue(build, "deployFiles")) {
+			if f.Value == "./" || f.Value == "." {
+				r.report(f, path+".build.deployFiles",
+					"deployFiles: ./ ships the whole build directory, including sources and node_modules",
+					"List the build output explicitly, e.g. deployFiles: ./dist")
+			}
+		}
+	})
+}
+
+func usesJavaScriptBase(base *yaml.Node) bool {
+	for _, b := range scalarValues(base) {
+		switch serviceTypeName(b.Value) {
+		case "nodejs", "bun", "deno":
+			return true
+		}
+	}
+	return false
+}
+
+func checkZeropsHardcodedSecrets(root *yaml.Node, r *reporter) {
+	forEachEntry(root, "zerops", func(svc *yaml.Node, path string) {
+		for _, section := range []string{"build", "run"} {
+			if sec := mappingValue(svc, section); sec != nil {
+				checkSecretVars(mappingValue(sec, "envVariables"), path+"."+section+".envVariables", r,
+					"Reference a secret instead: set it with `zaia env set` and use ${KEY} or ${hostname_KEY}")
+			}
+		}
+	})
+}
+
+func checkMissingHealthCheck(root *yaml.Node, r *reporter) {
+	forEachEntry(root, "zerops", func(svc *yaml.Node, path string) {
+		run := mappingValue(svc, "run")
+		if run == nil || mappingValue(run, "ports") == nil || mappingValue(run, "healthCheck") != nil {
+			return
+		}
+		r.report(run, path+".run",
+			"No healthCheck configured — failed containers are not detected and restarted",
+			"Add: healthCheck:\n  httpGet:\n    port: <port>\n    path: /health")
+	})
+}
+
+// --- import.yml rules ---
+
+func checkDeprecatedType(root *yaml.Node, r *reporter) {
+	forEachEntry(root, "services", func(svc *yaml.Node, path string) {
+		t := mappingValue(svc, "type")
+		if t == nil {
+			return
+		}
+		if repl, ok := catalog.Deprecated(t.Value); ok {
+			r.report(t, path+".type", fmt.Sprintf("'%s' is deprecated", t.Value), fmt.Sprintf("Use %s", repl))
+		}
+	})
+}
+
+func checkNonHADatabase(root *yaml.Node, r *reporter) {
+	forEachEntry(root, "services", func(svc *yaml.Node, path string) {
+		t, mode := mappingValue(svc, "type"), mappingValue(svc, "mode")
+		if t == nil || mode == nil || mode.Value != "NON_HA" || !dataServices[serviceTypeName(t.Value)] {
+			return
+		}
+		r.report(mode, path+".mode",
+			fmt.Sprintf("%s runs in NON_HA mode — no automatic recovery, data loss risk on node failure", t.Value),
+			"Use mode: HA for production (mode cannot be changed after creation)")
+	})
+}
+
+func checkSingleContainerRuntime(root *yaml.Node, r *reporter) {
+	forEachEntry(root, "services", func(svc *yaml.Node, path string) {
+		t, minC := mappingValue(svc, "type"), mappingValue(svc, "minContainers")
+		if t == nil || minC == nil || minC.Value != "1" || !runtimeServices[serviceTypeName(t.Value)] {
+			return
+		}
+		r.report(minC, path+".minContainers",
+			"minContainers: 1 — the service is unavailable while its only container restarts",
+			"Use minContainers: 2 or more for production")
+	})
+}
+
+func checkImportHardcodedSecrets(root *yaml.Node, r *reporter) {
+	forEachEntry(root, "services", func(svc *yaml.Node, path string) {
+		checkSecretVars(mappingValue(svc, "envVariables"), path+".envVariables", r,
+			"Move it to envSecrets, ideally generated: <@generateRandomString(<32>)>")
+	})
+}
+
+// secretKeyWords are the last one or two "_"-separated segments of env variable names that
+// usually hold credentials: DB_PASSWORD, API_TOKEN, AWS_SECRET_ACCESS_KEY. Names that only
+// start with one, like TOKEN_TTL or PASSWORD_MIN_LENGTH, configure a secret but are not one.
+var secretKeyWords = map[string]bool{
+	"PASSWORD": true, "PASSWD": true, "SECRET": true, "TOKEN": true,
+	"APIKEY": true, "PRIVATEKEY": true, "ACCESSKEY": true, "SECRETKEY": true,
+}
+
+func isSecretKey(key string) bool {
+	segments := strings.Split(strings.ToUpper(key), "_")
+	last := segments[len(segments)-1]
+	if secretKeyWords[last] {
+		return true
+	}
+	return len(segments) > 1 && secretKeyWords[segments[len(segments)-2]+last]
+}
+
+// checkSecretVars reports secret-looking keys with literal (non-reference) values.
+func checkSecretVars(vars *yaml.Node, path string, r *reporter, fix string) {
+	if vars == nil || vars.Kind != yaml.MappingNode {
+		return
+	}
+	for i := 0; i+1 < len(vars.Content); i += 2 {
+		key, val := vars.Content[i], resolveAlias(vars.Content[i+1])
+		if !isSecretKey(key.Value) || val.Kind != yaml.ScalarNode {
+			continue
+		}
+		if val.Value == "" || strings.Contains(val.Value, "${") || strings.Contains(val.Value, "<@") {
+			continue
+		}
+		r.report(val, joinPath(path, key.Value),
+			fmt.Sprintf("'%s' looks like a secret but has a hardcoded value", key.Value), fix)
+	}
+}
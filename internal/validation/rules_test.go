@@ -0,0 +1,162 @@
+package validation
+
+import (
+	"testing"
+)
+
+func ruleIDs(findings []Finding) map[string]int {
+	ids := make(map[string]int)
+	for _, f := range findings {
+		ids[f.Rule]++
+	}
+	return ids
+}
+
+func TestLintZeropsYml(t *testing.T) {
+	content := `zerops:
+  - setup: api
+    build:
+      base: nodejs@22
+      buildCommands:
+        - npm ci
+      deployFiles: ./
+      envVariables:
+        NODE_ENV: production
+    run:
+      ports:
+        - port: 3000
+      envVariables:
+        DB_PASSWORD: hunter2
+        API_TOKEN: ${api_TOKEN}
+      start: node index.js
+`
+	findings := LintZeropsYml([]byte(content))
+	want := map[string]int{
+		"deploy-all-files":     1,
+		"hardcoded-secret":     1,
+		"missing-health-check": 1,
+	}
+	got := ruleIDs(findings)
+	if len(got) != len(want) {
+		t.Fatalf("rules = %v, want %v", got, want)
+	}
+	for id, n := range want {
+		if got[id] != n {
+			t.Errorf("rule %s reported %d times, want %d", id, got[id], n)
+		}
+	}
+	for _, f := range findings {
+		if f.Line == 0 || f.Fix == "" {
+			t.Errorf("finding %+v lacks position or fix", f)
+		}
+		if f.Rule == "missing-health-check" && f.Severity != SeverityInfo {
+			t.Errorf("missing-health-check severity = %s, want info", f.Severity)
+		}
+	}
+}
+
+func TestLintImportYml(t *testing.T) {
+	content := `services:
+  - hostname: api
+    type: nodejs@22
+    minContainers: 1
+    envVariables:
+      STRIPE_SECRET: sk_live_123
+  - hostname: db
+    type: postgresql@16
+    mode: NON_HA
+  - hostname: cache
+    type: keydb@6
+    mode: HA
+`
+	got := ruleIDs(LintImportYml([]byte(content)))
+	want := map[string]int{
+		"deprecated-version":       1,
+		"non-ha-database":          1,
+		"single-container-runtime": 1,
+		"hardcoded-secret":         1,
+	}
+	for id, n := range want {
+		if got[id] != n {
+			t.Errorf("rule %s reported %d times, want %d", id, got[id], n)
+		}
+	}
+}
+
+func TestLint_Suppression(t *testing.T) {
+	content := `# zaia-ignore: non-ha-database, single-container-runtime
+services:
+  - hostname: api
+    type: nodejs@22
+    minContainers: 1
+  - hostname: db
+    type: postgresql@16
+    mode: NON_HA
+`
+	if findings := LintImportYml([]byte(content)); len(findings) != 0 {
+		t.Errorf("expected all findings suppressed, got %+v", findings)
+	}
+}
+
+func TestLint_SuppressionOnlyInComments(t *testing.T) {
+	content := `services:
+  - hostname: db # zaia-ignore: non-ha-database
+    type: postgresql@16
+    mode: NON_HA
+  - hostname: api
+    type: nodejs@22
+    minContainers: 1
+    envVariables:
+      NOTE: "# zaia-ignore: single-container-runtime"
+      SCRIPT: |
+        # zaia-ignore: hardcoded-secret
+        echo hi
+      DB_PASSWORD: hunter2
+`
+	got := ruleIDs(LintImportYml([]byte(content)))
+	want := map[string]int{"single-container-runtime": 1, "hardcoded-secret": 1}
+	if len(got) != len(want) || got["single-container-runtime"] != 1 || got["hardcoded-secret"] != 1 {
+		t.Errorf("rules = %v, want %v", got, want)
+	}
+}
+
+func TestLint_UnknownSuppression(t *testing.T) {
+	content := "# zaia-ignore: non-ha-databse, single-container-runtime\n\nservices:\n  - hostname: db\n    type: postgresql@16\n    mode: NON_HA\n"
+	findings := LintImportYml([]byte(content))
+	if len(findings) != 2 {
+		t.Fatalf("expected unknown-suppression and non-ha-database, got %+v", findings)
+	}
+	f := findings[0]
+	if f.Rule != UnknownSuppressionRule.ID || f.Fix != "Did you mean 'non-ha-database'?" {
+		t.Errorf("got %+v, want unknown-suppression suggesting non-ha-database", f)
+	}
+	if findings[1].Rule != "non-ha-database" {
+		t.Errorf("misspelled rule suppressed %s", findings[1].Rule)
+	}
+}
+
+func TestLint_SecretKeys(t *testing.T) {
+	for key, want := range map[string]bool{
+		"DB_PASSWORD": true, "API_TOKEN": true, "JWT_SECRET": true, "STRIPE_API_KEY": true,
+		"AWS_SECRET_ACCESS_KEY": true, "PRIVATE_KEY": true, "apiKey": true, "password": true,
+		"TOKEN_TTL": false, "SECRET_NAME_PREFIX": false, "PASSWORD_MIN_LENGTH": false,
+		"CACHE_KEY": false, "TOKENIZER": false,
+	} {
+		content := "services:\n  - hostname: api\n    type: nodejs@22\n    envVariables:\n      " + key + ": literal\n"
+		if got := ruleIDs(LintImportYml([]byte(content)))["hardcoded-secret"] == 1; got != want {
+			t.Errorf("%s flagged = %v, want %v", key, got, want)
+		}
+	}
+}
+
+func TestLint_RuleIDsUnique(t *testing.T) {
+	for name, rules := range map[string][]Rule{"zerops.yml": ZeropsYmlRules, "import.yml": ImportYmlRules} {
+		seen := make(map[string]bool)
+		for _, r := range rules {
+			if seen[r.ID] {
+				t.Errorf("%s: duplicate rule ID %s", name, r.ID)
+			}
+			seen[r.ID] = true
+		}
+	}
+}
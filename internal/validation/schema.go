@@ -0,0 +1,150 @@
+package validation
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Kind is the YAML value kind accepted by a Spec.
+type Kind int
+
+const (
+	KindAny    Kind = iota // any value, not checked further
+	KindString             // !!str scalar
+	KindInt                // !!int scalar
+	KindNumber             // !!int or !!float scalar
+	KindBool               // !!bool scalar
+	KindScalar             // any non-null scalar (env values, "3000" or 3000)
+	KindObject             // mapping with a fixed set of keys (Fields)
+	KindMap                // mapping with arbitrary keys, values checked against Values
+	KindArray              // sequence, items checked against Items
+)
+
+// Spec describes the expected shape of a YAML value.
+// The same model drives validation and documentation of zerops.yml and import.yml.
+type Spec struct {
+	Kind        Kind
+	Description string
+
+	Fields       []Field           // KindObject: allowed keys in document order
+	RequireOneOf []string          // KindObject: at least one of these keys must be present
+	Aliases      map[string]string // KindObject: common wrong key → correct key
+	Values       *Spec             // KindMap: spec for every value
+	Items        *Spec             // KindArray: spec for every item
+	MinItems     int               // KindArray: minimum number of items
+
+	OneOf []*Spec // alternatives — the first one matching the node kind is used
+
+	Check       func(value string) (msg, fix string) // custom scalar check run before Enum; "" msg = ok
+	Enum        []string                             // allowed scalar values
+	Min         *float64                             // inclusive lower bound for numbers
+	Max         *float64                             // inclusive upper bound for numbers
+	Pattern     *regexp.Regexp                       // strings must match
+	PatternHint string                               // explains Pattern in error messages
+	MaxLength   int                                  // maximum string length (0 = unlimited)
+}
+
+// Field is a named key of a KindObject spec.
+type Field struct {
+	Name     string
+	Spec     *Spec
+	Required bool
+	Example  string // YAML snippet shown in the fix when the key is missing or malformed
+}
+
+// field returns the field with the given name, or nil.
+func (s *Spec) field(name string) *Field {
+	for i := range s.Fields {
+		if s.Fields[i].Name == name {
+			return &s.Fields[i]
+		}
+	}
+	return nil
+}
+
+// fieldNames returns the names of all allowed keys.
+func (s *Spec) fieldNames() []string {
+	names := make([]string, len(s.Fields))
+	for i, f := range s.Fields {
+		names[i] = f.Name
+	}
+	return names
+}
+
+// describe returns a human-readable kind description ("a string", "an array", ...).
+func (s *Spec) describe() string {
+	if len(s.OneOf) > 0 {
+		parts := make([]string, len(s.OneOf))
+		for i, alt := range s.OneOf {
+			parts[i] = alt.describe()
+		}
+		return strings.Join(parts, " or ")
+	}
+	switch s.Kind {
+	case KindString:
+		return "a string"
+	case KindInt:
+		return "an integer"
+	case KindNumber:
+		return "a number"
+	case KindBool:
+		return "a boolean"
+	case KindScalar:
+		return "a scalar value"
+	case KindObject, KindMap:
+		return "a mapping"
+	case KindArray:
+		return "an array"
+	case KindAny:
+		return "a value"
+	}
+	return "a value"
+}
+
+// --- Spec constructors ---
+
+func str(desc string) *Spec      { return &Spec{Kind: KindString, Description: desc} }
+func boolean(desc string) *Spec  { return &Spec{Kind: KindBool, Description: desc} }
+func anyValue(desc string) *Spec { return &Spec{Kind: KindAny, Description: desc} }
+
+func integer(desc string, minVal, maxVal float64) *Spec {
+	return &Spec{Kind: KindInt, Description: desc, Min: &minVal, Max: &maxVal}
+}
+
+func number(desc string, minVal, maxVal float64) *Spec {
+	return &Spec{Kind: KindNumber, Description: desc, Min: &minVal, Max: &maxVal}
+}
+
+func enum(desc string, values ...string) *Spec {
+	return &Spec{Kind: KindString, Description: desc, Enum: values}
+}
+
+func object(desc string, fields ...Field) *Spec {
+	return &Spec{Kind: KindObject, Description: desc, Fields: fields}
+}
+
+func arrayOf(desc string, items *Spec) *Spec {
+	return &Spec{Kind: KindArray, Description: desc, Items: items}
+}
+
+func mapOf(desc string, values *Spec) *Spec {
+	return &Spec{Kind: KindMap, Description: desc, Values: values}
+}
+
+func oneOf(desc string, alts ...*Spec) *Spec {
+	return &Spec{Description: desc, OneOf: alts}
+}
+
+// stringOrList accepts a single string or an array of strings.
+func stringOrList(desc string) *Spec {
+	return oneOf(desc, str(""), arrayOf("", str("")))
+}
+
+// baseSpec is a build or run base: one catalog type ID, or a list of them when list is set.
+func baseSpec(desc string, list bool) *Spec {
+	base := &Spec{Kind: KindString, Description: desc, Check: checkBase, Enum: baseTypes}
+	if !list {
+		return base
+	}
+	return oneOf(desc, base, arrayOf("", base))
+}
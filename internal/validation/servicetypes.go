@@ -0,0 +1,69 @@
+package validation
+
+import (
+	"strings"
+
+	"github.com/zeropsio/zaia/internal/catalog"
+)
+
+// serviceTypes lists the service types accepted in import.yml (name@version), from the catalog.
+// End-of-life versions are excluded; they are reported with a dedicated message instead.
+var serviceTypes = catalog.IDs(false)
+
+// baseTypes lists every value accepted as build.base or run.base, including build-only types.
+var baseTypes = func() []string {
+	var ids []string
+	for _, t := range catalog.Types() {
+		ids = append(ids, t.IDs(false)...)
+	}
+	return ids
+}()
+
+// modeRequiredServices must declare mode: HA or NON_HA explicitly.
+// Dry-run accepts them without it, but the real import fails with "Mandatory parameter is missing".
+var modeRequiredServices = func() map[string]bool {
+	m := make(map[string]bool)
+	for _, t := range catalog.Types() {
+		if t.ModeRequired {
+			m[t.Name] = true
+		}
+	}
+	return m
+}()
+
+// runtimeServices run user code and scale horizontally.
+var runtimeServices = namesIn(catalog.CategoryRuntime, catalog.CategoryContainer, catalog.CategoryWeb)
+
+// dataServices hold state that is lost without HA replication.
+var dataServices = namesIn(catalog.CategoryDatabase, catalog.CategoryCache)
+
+func namesIn(categories ...string) map[string]bool {
+	m := make(map[string]bool)
+	for _, c := range categories {
+		for _, name := range catalog.Names(c) {
+			m[name] = true
+		}
+	}
+	return m
+}
+
+// serviceTypeName returns the part of a service type before '@' ("postgresql@16" → "postgresql").
+func serviceTypeName(serviceType string) string {
+	name, _, _ := strings.Cut(serviceType, "@")
+	return name
+}
+
+// checkBase validates a build.base or run.base value against the catalog.
+func checkBase(value string) (msg, fix string) {
+	if msg, fix := catalog.Check(value); msg != "" {
+		return msg, fix
+	}
+	if _, ok := catalog.Lookup(serviceTypeName(value)); ok {
+		return "", ""
+	}
+	fix = "Run: zaia catalog list"
+	if s := Closest(value, baseTypes); s != "" {
+		fix = "Did you mean '" + s + "'?"
+	}
+	return "Unknown base '" + value + "'", fix
+}
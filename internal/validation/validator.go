@@ -0,0 +1,393 @@
+package validation
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Issue is a single validation finding.
+// Line and Column are 1-based source positions; zero when the position is unknown.
+type Issue struct {
+	Path   string `json:"path"`
+	Error  string `json:"error"`
+	Fix    string `json:"fix"`
+	Line   int    `json:"line,omitempty"`
+	Column int    `json:"column,omitempty"`
+}
+
+// ValidateZeropsYml validates zerops.yml content and returns every issue found.
+func ValidateZeropsYml(content []byte) []Issue {
+	return validateDocument(content, ZeropsYmlSpec)
+}
+
+// validateDocument parses content and checks it against spec.
+func validateDocument(content []byte, spec *Spec) []Issue {
+	root, err := parseRoot(content)
+	if err != nil {
+		return []Issue{syntaxIssue(content, err)}
+	}
+	v := &validator{}
+	v.check(root, spec, "", Field{})
+	return v.issues
+}
+
+// parseRoot parses content into a node tree and returns its top-level node.
+// An empty document yields an empty mapping so required keys are still reported.
+func parseRoot(content []byte) (*yaml.Node, error) {
+	doc, err := parseDocument(content)
+	if err != nil {
+		return nil, err
+	}
+	return documentRoot(doc), nil
+}
+
+// parseDocument parses content into its document node, which also holds the comments
+// before and after the root node.
+func parseDocument(content []byte) (*yaml.Node, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(content, &doc); err != nil {
+		return nil, err
+	}
+	return &doc, nil
+}
+
+func documentRoot(doc *yaml.Node) *yaml.Node {
+	if doc.Kind == yaml.DocumentNode && len(doc.Content) > 0 {
+		return doc.Content[0]
+	}
+	return &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+}
+
+type validator struct {
+	issues []Issue
+}
+
+// add records an issue located at node n.
+func (v *validator) add(n *yaml.Node, path, msg, fix string) {
+	v.issues = append(v.issues, Issue{Path: path, Error: msg, Fix: fix, Line: n.Line, Column: n.Column})
+}
+
+// check validates node against spec. f is the field the node belongs to (zero for the root and array items).
+func (v *validator) check(n *yaml.Node, spec *Spec, path string, f Field) {
+	n = resolveAlias(n)
+	if spec == nil || spec.Kind == KindAny && len(spec.OneOf) == 0 {
+		return
+	}
+
+	if len(spec.OneOf) > 0 {
+		for _, alt := range spec.OneOf {
+			if kindMatches(n, alt.Kind) {
+				v.check(n, alt, path, f)
+				return
+			}
+		}
+		v.typeError(n, spec, path, f)
+		return
+	}
+
+	if !kindMatches(n, spec.Kind) {
+		v.typeError(n, spec, path, f)
+		return
+	}
+
+	switch spec.Kind {
+	case KindObject:
+		v.checkObject(n, spec, path)
+	case KindMap:
+		for i := 0; i+1 < len(n.Content); i += 2 {
+			key := n.Content[i].Value
+			v.check(n.Content[i+1], spec.Values, joinPath(path, key), Field{Name: key})
+		}
+	case KindArray:
+		if len(n.Content) < spec.MinItems {
+			v.add(n, path, fmt.Sprintf("'%s' array is empty", displayName(path)), "Add at least one "+itemNoun(spec))
+			return
+		}
+		for i, item := range n.Content {
+			v.check(item, spec.Items, fmt.Sprintf("%s[%d]", path, i), Field{})
+		}
+	case KindString, KindInt, KindNumber, KindBool, KindScalar, KindAny:
+		v.checkScalar(n, spec, path)
+	}
+}
+
+func (v *validator) checkObject(n *yaml.Node, spec *Spec, path string) {
+	pairs := mappingPairs(n)
+	present := make(map[string]bool, len(pairs))
+	for _, pair := range pairs {
+		key := pair[0].Value
+		present[key] = true
+		childPath := joinPath(path, key)
+		fld := spec.field(key)
+		if fld == nil {
+			fix := unknownKeyFix(key, spec.fieldNames())
+			if correct, ok := spec.Aliases[key]; ok {
+				fix = fmt.Sprintf("Use '%s' instead of '%s'", correct, key)
+			}
+			v.add(pair[0], childPath, fmt.Sprintf("Unknown key '%s'", key), fix)
+			continue
+		}
+		v.check(pair[1], fld.Spec, childPath, *fld)
+	}
+
+	for _, fld := range spec.Fields {
+		if fld.Required && !present[fld.Name] {
+			fix := "Add '" + fld.Name + "'"
+			if fld.Example != "" {
+				fix = "Add: " + fld.Example
+			}
+			v.add(n, path, fmt.Sprintf("Missing '%s' key", fld.Name), fix)
+		}
+	}
+
+	if len(spec.RequireOneOf) > 0 {
+		for _, name := range spec.RequireOneOf {
+			if present[name] {
+				return
+			}
+		}
+		v.add(n, path, fmt.Sprintf("'%s' requires one of: %s", displayName(path), strings.Join(spec.RequireOneOf, ", ")),
+			"Add one of: "+strings.Join(spec.RequireOneOf, ", "))
+	}
+}
+
+func (v *validator) checkScalar(n *yaml.Node, spec *Spec, path string) {
+	if spec.Check != nil {
+		if msg, fix := spec.Check(n.Value); msg != "" {
+			v.add(n, path, msg, fix)
+			return
+		}
+	}
+	if len(spec.Enum) > 0 && !containsString(spec.Enum, n.Value) {
+		fix := "Allowed values: " + strings.Join(spec.Enum, ", ")
+		if s := Closest(n.Value, spec.Enum); s != "" {
+			fix = fmt.Sprintf("Did you mean '%s'?", s)
+		}
+		v.add(n, path, fmt.Sprintf("Invalid value '%s' for '%s'", n.Value, displayName(path)), fix)
+		return
+	}
+	if spec.MaxLength > 0 && len(n.Value) > spec.MaxLength {
+		v.add(n, path, fmt.Sprintf("'%s' is too long (%d characters, max %d)", displayName(path), len(n.Value), spec.MaxLength),
+			fmt.Sprintf("Shorten it to at most %d characters", spec.MaxLength))
+		return
+	}
+	if spec.Pattern != nil && !spec.Pattern.MatchString(n.Value) {
+		v.add(n, path, fmt.Sprintf("Invalid value '%s' for '%s'", n.Value, displayName(path)), spec.PatternHint)
+		return
+	}
+	if spec.Min == nil && spec.Max == nil {
+		return
+	}
+	num, err := strconv.ParseFloat(n.Value, 64)
+	if err != nil {
+		return
+	}
+	if (spec.Min == nil || num >= *spec.Min) && (spec.Max == nil || num <= *spec.Max) {
+		return
+	}
+	var bound, fix string
+	switch {
+	case spec.Min != nil && spec.Max != nil:
+		bound = fmt.Sprintf("between %s and %s", formatNumber(*spec.Min), formatNumber(*spec.Max))
+		fix = fmt.Sprintf("Use a value from %s to %s", formatNumber(*spec.Min), formatNumber(*spec.Max))
+	case spec.Min != nil:
+		bound = "at least " + formatNumber(*spec.Min)
+		fix = "Use a value of " + bound
+	default:
+		bound = "at most " + formatNumber(*spec.Max)
+		fix = "Use a value of " + bound
+	}
+	v.add(n, path, fmt.Sprintf("'%s' must be %s, got %s", displayName(path), bound, n.Value), fix)
+}
+
+func (v *validator) typeError(n *yaml.Node, spec *Spec, path string, f Field) {
+	fix := "Change '" + displayName(path) + "' to " + spec.describe()
+	if f.Example != "" {
+		fix = "Format: " + f.Example
+	}
+	msg := fmt.Sprintf("'%s' must be %s", displayName(path), spec.describe())
+	if n.Kind == yaml.ScalarNode && n.ShortTag() == "!!null" {
+		msg = fmt.Sprintf("'%s' is empty, expected %s", displayName(path), spec.describe())
+	}
+	v.add(n, path, msg, fix)
+}
+
+// kindMatches reports whether node n has the YAML shape required by kind.
+func kindMatches(n *yaml.Node, kind Kind) bool {
+	switch kind {
+	case KindAny:
+		return true
+	case KindObject, KindMap:
+		return n.Kind == yaml.MappingNode
+	case KindArray:
+		return n.Kind == yaml.SequenceNode
+	case KindString, KindInt, KindNumber, KindBool, KindScalar:
+	}
+	if n.Kind != yaml.ScalarNode {
+		return false
+	}
+	tag := n.ShortTag()
+	switch kind {
+	case KindString:
+		return tag == "!!str"
+	case KindInt:
+		return tag == "!!int"
+	case KindNumber:
+		return tag == "!!int" || tag == "!!float"
+	case KindBool:
+		return tag == "!!bool"
+	case KindScalar:
+		return tag != "!!null"
+	case KindAny, KindObject, KindMap, KindArray:
+	}
+	return false
+}
+
+// syntaxLinePattern extracts the line number from yaml.v3 errors ("yaml: line 3: ...").
+var syntaxLinePattern = regexp.MustCompile(`line (\d+):`)
+
+// syntaxIssue converts a YAML parse error into an Issue.
+// yaml.v3 reports only the line, so the column points at the first non-blank character on it.
+func syntaxIssue(content []byte, err error) Issue {
+	is := Issue{Path: "", Error: "Invalid YAML syntax: " + err.Error(), Fix: "Check YAML formatting"}
+	m := syntaxLinePattern.FindStringSubmatch(err.Error())
+	if m == nil {
+		return is
+	}
+	is.Line, _ = strconv.Atoi(m[1])
+	is.Column = 1
+	lines := strings.Split(string(content), "\n")
+	if is.Line >= 1 && is.Line <= len(lines) {
+		text := lines[is.Line-1]
+		is.Column = len(text) - len(strings.TrimLeft(text, " \t")) + 1
+	}
+	return is
+}
+
+func resolveAlias(n *yaml.Node) *yaml.Node {
+	for n.Kind == yaml.AliasNode && n.Alias != nil {
+		n = n.Alias
+	}
+	return n
+}
+
+// mappingPairs returns the key and value nodes of the mapping n with "<<" merge keys
+// resolved: merged keys come from the merged mappings, in order, and explicit keys win.
+func mappingPairs(n *yaml.Node) [][2]*yaml.Node {
+	var pairs [][2]*yaml.Node
+	var merges []*yaml.Node
+	seen := make(map[string]bool, len(n.Content)/2)
+	for i := 0; i+1 < len(n.Content); i += 2 {
+		key, value := n.Content[i], n.Content[i+1]
+		if key.Value == "<<" && key.Tag == "!!merge" {
+			merges = append(merges, value)
+			continue
+		}
+		pairs = append(pairs, [2]*yaml.Node{key, value})
+		seen[key.Value] = true
+	}
+	for _, m := range merges {
+		m = resolveAlias(m)
+		sources := []*yaml.Node{m}
+		if m.Kind == yaml.SequenceNode {
+			sources = m.Content
+		}
+		for _, src := range sources {
+			if src = resolveAlias(src); src.Kind != yaml.MappingNode {
+				continue
+			}
+			for _, pair := range mappingPairs(src) {
+				if !seen[pair[0].Value] {
+					pairs = append(pairs, pair)
+					seen[pair[0].Value] = true
+				}
+			}
+		}
+	}
+	return pairs
+}
+
+func joinPath(parent, key string) string {
+	if parent == "" {
+		return key
+	}
+	return parent + "." + key
+}
+
+// displayName returns the path for messages, "root" for the document itself.
+func displayName(path string) string {
+	if path == "" {
+		return "root"
+	}
+	return path
+}
+
+func itemNoun(spec *Spec) string {
+	if spec.Items != nil && spec.Items.Description != "" {
+		return strings.ToLower(spec.Items.Description)
+	}
+	return "item"
+}
+
+// unknownKeyFix suggests the closest allowed key, or lists them all.
+func unknownKeyFix(key string, allowed []string) string {
+	if s := Closest(key, allowed); s != "" {
+		return fmt.Sprintf("Did you mean '%s'?", s)
+	}
+	sorted := append([]string(nil), allowed...)
+	sort.Strings(sorted)
+	return "Remove it. Allowed keys: " + strings.Join(sorted, ", ")
+}
+
+// Closest returns the candidate nearest to s (case-insensitive, edit distance <= 2), or "".
+func Closest(s string, candidates []string) string {
+	best := ""
+	bestDist := 3
+	lower := strings.ToLower(s)
+	for _, c := range candidates {
+		d := Levenshtein(lower, strings.ToLower(c))
+		if d < bestDist {
+			best, bestDist = c, d
+		}
+	}
+	return best
+}
+
+// Levenshtein returns the edit distance between a and b.
+func Levenshtein(a, b string) int {
+	prev := make([]int, len(b)+1)
+	cur := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(b)]
+}
+
+func containsString(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+func formatNumber(f float64) string {
+	return strconv.FormatFloat(f, 'f', -1, 64)
+}
@@ -0,0 +1,311 @@
+package validation
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestValidateZeropsYml_Valid(t *testing.T) {
+	content := `zerops:
+  - setup: api
+    build:
+      base: nodejs@22
+      os: ubuntu
+      prepareCommands:
+        - apt-get install -y jq
+      buildCommands:
+        - npm ci
+        - npm run build
+      deployFiles:
+        - dist
+        - node_modules
+      cache: node_modules
+      envVariables:
+        NODE_ENV: production
+    deploy:
+      readinessCheck:
+        httpGet:
+          port: 3000
+          path: /ready
+    run:
+      initCommands:
+        - npm run migrate
+      start: node dist/index.js
+      ports:
+        - port: 3000
+          httpSupport: true
+        - port: 5000
+          protocol: UDP
+      envVariables:
+        PORT: 3000
+        DB_URL: ${db_connectionString}
+      healthCheck:
+        exec:
+          command: curl -f localhost:3000/health
+      crontab:
+        - command: node dist/cleanup.js
+          timing: "0 * * * *"
+      mount:
+        - files
+  - setup: web
+    build:
+      base: [php@8.4, nodejs@22]
+      deployFiles: ./
+      cache: true
+    run:
+      base: php-nginx@8.4
+      documentRoot: public
+`
+	issues := ValidateZeropsYml([]byte(content))
+	if len(issues) != 0 {
+		t.Fatalf("expected no issues, got %+v", issues)
+	}
+}
+
+func TestValidateZeropsYml_ReportsEveryIssue(t *testing.T) {
+	content := `zerops:
+  - setup: api
+    build:
+      base: nodejs@22
+      buildCommand:
+        - npm ci
+    run:
+      start: [npm, start]
+      ports:
+        - port: 3000
+          protocol: HTTP
+        - port: 80000
+`
+	issues := ValidateZeropsYml([]byte(content))
+
+	want := map[string]string{
+		"zerops[0].build.buildCommand":    "Unknown key 'buildCommand'",
+		"zerops[0].build":                 "Missing 'deployFiles' key",
+		"zerops[0].run.start":             "must be a string",
+		"zerops[0].run.ports[0].protocol": "Invalid value 'HTTP'",
+		"zerops[0].run.ports[1].port":     "must be between 10 and 65435",
+	}
+	if len(issues) != len(want) {
+		t.Fatalf("expected %d issues, got %d: %+v", len(want), len(issues), issues)
+	}
+	for _, is := range issues {
+		msg, ok := want[is.Path]
+		if !ok {
+			t.Errorf("unexpected issue at %q: %s", is.Path, is.Error)
+			continue
+		}
+		if !strings.Contains(is.Error, msg) {
+			t.Errorf("issue at %q = %q, want it to contain %q", is.Path, is.Error, msg)
+		}
+		if is.Fix == "" {
+			t.Errorf("issue at %q has empty fix", is.Path)
+		}
+	}
+}
+
+func TestValidate_OneSidedBound(t *testing.T) {
+	minVal, maxVal := 1.0, 10.0
+	tests := []struct {
+		spec  *Spec
+		value string
+		want  string
+	}{
+		{&Spec{Kind: KindInt, Min: &minVal}, "0", "'n' must be at least 1, got 0"},
+		{&Spec{Kind: KindInt, Max: &maxVal}, "11", "'n' must be at most 10, got 11"},
+		{&Spec{Kind: KindInt, Min: &minVal}, "11", ""},
+	}
+	for _, tt := range tests {
+		spec := &Spec{Kind: KindObject, Fields: []Field{{Name: "n", Spec: tt.spec}}}
+		issues := validateDocument([]byte("n: "+tt.value+"\n"), spec)
+		switch {
+		case tt.want == "" && len(issues) != 0:
+			t.Errorf("n: %s: unexpected issues %+v", tt.value, issues)
+		case tt.want != "" && (len(issues) != 1 || issues[0].Error != tt.want):
+			t.Errorf("n: %s: issues = %+v, want %q", tt.value, issues, tt.want)
+		}
+	}
+}
+
+func TestValidateZeropsYml_UnknownKeySuggestion(t *testing.T) {
+	content := `zerops:
+  - setup: api
+    run:
+      startt: npm start
+`
+	issues := ValidateZeropsYml([]byte(content))
+	if len(issues) != 1 {
+		t.Fatalf("expected 1 issue, got %+v", issues)
+	}
+	if issues[0].Fix != "Did you mean 'start'?" {
+		t.Errorf("fix = %q, want did-you-mean suggestion", issues[0].Fix)
+	}
+}
+
+func TestValidateZeropsYml_Root(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		path    string
+		err     string
+	}{
+		{"missing zerops", "somethingWrong: yes", "", "Missing 'zerops' key"},
+		{"zerops not array", "zerops: api", "zerops", "'zerops' must be an array"},
+		{"zerops empty", "zerops: []", "zerops", "'zerops' array is empty"},
+		{"empty document", "", "", "Missing 'zerops' key"},
+		{"invalid syntax", "{invalid yaml[", "", "Invalid YAML syntax"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			issues := ValidateZeropsYml([]byte(tt.content))
+			found := false
+			for _, is := range issues {
+				if is.Path == tt.path && strings.Contains(is.Error, tt.err) {
+					found = true
+				}
+			}
+			if !found {
+				t.Errorf("expected issue %q at %q, got %+v", tt.err, tt.path, issues)
+			}
+		})
+	}
+}
+
+func TestValidateZeropsYml_CheckRequiresProbe(t *testing.T) {
+	content := `zerops:
+  - setup: api
+    run:
+      healthCheck:
+        failureTimeout: 60
+`
+	issues := ValidateZeropsYml([]byte(content))
+	if len(issues) != 1 || issues[0].Path != "zerops[0].run.healthCheck" {
+		t.Fatalf("expected one healthCheck issue, got %+v", issues)
+	}
+}
+
+func TestLevenshtein(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"", "", 0},
+		{"start", "start", 0},
+		{"buildCommand", "buildCommands", 1},
+		{"prot", "port", 2},
+		{"abc", "xyz", 3},
+	}
+	for _, tt := range tests {
+		if got := Levenshtein(tt.a, tt.b); got != tt.want {
+			t.Errorf("Levenshtein(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestValidateZeropsYml_Positions(t *testing.T) {
+	content := `zerops:
+  - setup: api
+    build:
+      base: nodejs@22
+      deployFiles: ./
+      buildCommand: npm ci
+    run:
+      ports:
+        - port: 5
+`
+	issues := ValidateZeropsYml([]byte(content))
+	want := map[string][2]int{
+		"zerops[0].build.buildCommand": {6, 7},
+		"zerops[0].run.ports[0].port":  {9, 17},
+	}
+	if len(issues) != len(want) {
+		t.Fatalf("expected %d issues, got %+v", len(want), issues)
+	}
+	for _, is := range issues {
+		pos := want[is.Path]
+		if is.Line != pos[0] || is.Column != pos[1] {
+			t.Errorf("%s at %d:%d, want %d:%d", is.Path, is.Line, is.Column, pos[0], pos[1])
+		}
+	}
+}
+
+func TestValidateZeropsYml_MissingKeyPointsAtParent(t *testing.T) {
+	content := "zerops:\n  - run:\n      start: npm start\n"
+	issues := ValidateZeropsYml([]byte(content))
+	if len(issues) != 1 {
+		t.Fatalf("expected 1 issue, got %+v", issues)
+	}
+	if issues[0].Line != 2 || issues[0].Column != 5 {
+		t.Errorf("missing setup at %d:%d, want 2:5", issues[0].Line, issues[0].Column)
+	}
+}
+
+func TestValidateZeropsYml_MergeKeys(t *testing.T) {
+	content := `zerops:
+  - &base
+    setup: api
+    build:
+      base: nodejs@22
+      deployFiles: dist
+    run:
+      start: node dist/index.js
+  - <<: *base
+    setup: worker
+  - <<: [*base]
+    setup: cron
+    run:
+      ports: 3000
+`
+	issues := ValidateZeropsYml([]byte(content))
+	if len(issues) != 1 {
+		t.Fatalf("expected 1 issue, got %+v", issues)
+	}
+	if issues[0].Path != "zerops[2].run.ports" || issues[0].Line != 14 {
+		t.Errorf("issue %s at line %d, want zerops[2].run.ports at line 14", issues[0].Path, issues[0].Line)
+	}
+
+	// Merged keys are type-checked too.
+	content = "zerops:\n  - &base\n    setup: api\n    run:\n      ports: 3000\n  - <<: *base\n    setup: worker\n"
+	issues = ValidateZeropsYml([]byte(content))
+	if len(issues) != 2 || issues[1].Path != "zerops[1].run.ports" || issues[1].Line != 5 {
+		t.Errorf("expected ports errors for both setups, got %+v", issues)
+	}
+}
+
+func TestValidateZeropsYml_SyntaxErrorPosition(t *testing.T) {
+	content := "zerops:\n  - setup: api\n      run: x\n"
+	issues := ValidateZeropsYml([]byte(content))
+	if len(issues) != 1 {
+		t.Fatalf("expected 1 issue, got %+v", issues)
+	}
+	if !strings.HasPrefix(issues[0].Error, "Invalid YAML syntax") {
+		t.Errorf("error = %q, want syntax error", issues[0].Error)
+	}
+	if issues[0].Line != 3 || issues[0].Column != 7 {
+		t.Errorf("syntax error at %d:%d, want 3:7", issues[0].Line, issues[0].Column)
+	}
+}
+
+func TestValidateZeropsYml_Base(t *testing.T) {
+	tests := []struct {
+		base string
+		err  string
+		fix  string
+	}{
+		{"dotnet@6", "'dotnet@6' is end-of-life", "Did you mean 'dotnet@8'?"},
+		{"nodejs@21", "Unknown version '21' for nodejs", "Did you mean 'nodejs@22'? Available versions: 22, 20, 18"},
+		{"nodjs@22", "Unknown base 'nodjs@22'", "Did you mean 'nodejs@22'?"},
+		{"nodejs", "'nodejs' needs a version", "Did you mean 'nodejs@22'?"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.base, func(t *testing.T) {
+			content := "zerops:\n  - setup: api\n    run:\n      base: " + tt.base + "\n"
+			issues := ValidateZeropsYml([]byte(content))
+			if len(issues) != 1 {
+				t.Fatalf("expected 1 issue, got %+v", issues)
+			}
+			if issues[0].Error != tt.err || issues[0].Fix != tt.fix {
+				t.Errorf("got %q / %q, want %q / %q", issues[0].Error, issues[0].Fix, tt.err, tt.fix)
+			}
+		})
+	}
+}
@@ -0,0 +1,106 @@
+package validation
+
+// checkSpec describes healthCheck and readinessCheck (httpGet or exec).
+func checkSpec(desc string) *Spec {
+	s := object(desc,
+		Field{Name: "httpGet", Spec: object("HTTP GET probe",
+			Field{Name: "port", Spec: integer("Port to probe", 1, 65535), Required: true, Example: "port: 3000"},
+			Field{Name: "path", Spec: str("URL path to probe"), Required: true, Example: "path: /health"},
+			Field{Name: "host", Spec: str("Host header")},
+			Field{Name: "scheme", Spec: enum("URL scheme", "http", "https")},
+		), Example: "httpGet:\n  port: 3000\n  path: /health"},
+		Field{Name: "exec", Spec: object("Command probe",
+			Field{Name: "command", Spec: str("Command that must exit 0"), Required: true, Example: "command: curl -f localhost:3000"},
+		), Example: "exec:\n  command: curl -f localhost:3000"},
+		Field{Name: "failureTimeout", Spec: integer("Seconds until the check is considered failed", 1, 3600)},
+		Field{Name: "disconnectTimeout", Spec: integer("Seconds until the container is disconnected", 1, 3600)},
+		Field{Name: "recoveryTimeout", Spec: integer("Seconds until the container is considered recovered", 1, 3600)},
+		Field{Name: "execPeriod", Spec: integer("Seconds between checks", 1, 3600)},
+		Field{Name: "retryPeriod", Spec: integer("Seconds between retries", 1, 3600)},
+	)
+	s.RequireOneOf = []string{"httpGet", "exec"}
+	return s
+}
+
+func envVariablesSpec() *Spec {
+	return mapOf("Environment variables (KEY: value)", &Spec{Kind: KindScalar})
+}
+
+func zeropsBuildSpec() *Spec {
+	return object("Build pipeline configuration",
+		Field{Name: "base", Spec: baseSpec("Build runtime(s), e.g. nodejs@22 or [php@8.4, nodejs@22]", true), Required: true, Example: "base: nodejs@22"},
+		Field{Name: "os", Spec: enum("Build container OS", "alpine", "ubuntu")},
+		Field{Name: "prepareCommands", Spec: arrayOf("Commands installing build dependencies (cached)", str(""))},
+		Field{Name: "buildCommands", Spec: arrayOf("Build commands", str("")), Example: "buildCommands:\n  - npm ci\n  - npm run build"},
+		Field{Name: "deployFiles", Spec: stringOrList("Files and directories to deploy"), Required: true, Example: "deployFiles: ./dist"},
+		Field{Name: "cache", Spec: oneOf("Paths cached between builds", boolean(""), str(""), arrayOf("", str("")))},
+		Field{Name: "addToRunPrepare", Spec: stringOrList("Files copied from the build container into the run prepare phase")},
+		Field{Name: "envVariables", Spec: envVariablesSpec()},
+	)
+}
+
+func zeropsDeploySpec() *Spec {
+	return object("Deploy phase configuration",
+		Field{Name: "readinessCheck", Spec: checkSpec("Check run before traffic is switched to new containers")},
+		Field{Name: "temporaryShutdown", Spec: boolean("Stop old containers before new ones are started")},
+	)
+}
+
+func zeropsRunSpec() *Spec {
+	port := object("Internal port",
+		Field{Name: "port", Spec: integer("Port number", 10, 65435), Required: true, Example: "port: 3000"},
+		Field{Name: "protocol", Spec: enum("Transport protocol", "TCP", "UDP")},
+		Field{Name: "httpSupport", Spec: boolean("Port speaks HTTP")},
+	)
+	cron := object("Scheduled task",
+		Field{Name: "command", Spec: str("Command to run"), Required: true, Example: "command: php artisan schedule:run"},
+		Field{Name: "timing", Spec: str("Cron expression"), Required: true, Example: `timing: "* * * * *"`},
+		Field{Name: "allContainers", Spec: boolean("Run in every container instead of one")},
+		Field{Name: "workingDir", Spec: str("Working directory")},
+	)
+	return object("Runtime configuration",
+		Field{Name: "base", Spec: baseSpec("Run runtime, defaults to build base", false)},
+		Field{Name: "os", Spec: enum("Run container OS", "alpine", "ubuntu")},
+		Field{Name: "prepareCommands", Spec: arrayOf("Commands customizing the runtime image", str(""))},
+		Field{Name: "initCommands", Spec: arrayOf("Commands run on every container start", str(""))},
+		Field{Name: "start", Spec: str("Start command"), Example: "start: node dist/index.js"},
+		Field{Name: "ports", Spec: arrayOf("Internal ports", port), Example: "ports:\n  - port: 3000\n    httpSupport: true"},
+		Field{Name: "envVariables", Spec: envVariablesSpec()},
+		Field{Name: "envReplace", Spec: object("Replace env references in static files",
+			Field{Name: "delimiter", Spec: stringOrList("Delimiter(s) around variable names")},
+			Field{Name: "target", Spec: stringOrList("Files or directories to process")},
+		)},
+		Field{Name: "healthCheck", Spec: checkSpec("Runtime health check")},
+		Field{Name: "crontab", Spec: arrayOf("Scheduled tasks", cron)},
+		Field{Name: "mount", Spec: arrayOf("Shared storage hostnames mounted under /mnt/<hostname>", str(""))},
+		Field{Name: "documentRoot", Spec: str("Directory served by PHP/Nginx/Static")},
+		Field{Name: "siteConfigPath", Spec: str("Custom web server config file")},
+		Field{Name: "routing", Spec: object("Static/Nginx routing",
+			Field{Name: "root", Spec: str("")},
+			Field{Name: "redirects", Spec: arrayOf("", anyValue(""))},
+			Field{Name: "cors", Spec: anyValue("")},
+			Field{Name: "headers", Spec: arrayOf("", anyValue(""))},
+		)},
+	)
+}
+
+// zeropsServiceSpec describes one entry of the zerops array.
+func zeropsServiceSpec() *Spec {
+	return object("Service build/deploy/run configuration",
+		Field{Name: "setup", Spec: str("Service hostname this configuration belongs to"), Required: true, Example: "setup: api"},
+		Field{Name: "extends", Spec: str("Inherit configuration from another setup")},
+		Field{Name: "build", Spec: zeropsBuildSpec()},
+		Field{Name: "deploy", Spec: zeropsDeploySpec()},
+		Field{Name: "run", Spec: zeropsRunSpec()},
+	)
+}
+
+// ZeropsYmlSpec is the schema of zerops.yml.
+var ZeropsYmlSpec = object("zerops.yml",
+	Field{
+		Name:     "zerops",
+		Spec:     &Spec{Kind: KindArray, Description: "Service configurations", Items: zeropsServiceSpec(), MinItems: 1},
+		Required: true,
+		Example:  "zerops:\n  - setup: api\n    run:\n      base: nodejs@22",
+	},
+)